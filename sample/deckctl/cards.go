@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+func (a *app) cardsCmd(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: deckctl cards add|list|rm|move|shuffle")
+	}
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("cards list", flag.ContinueOnError)
+		tags := fs.String("tags", "", "list the cards whose tags match this expression")
+		render := fs.String("render", "", "render the faces of the cards: html")
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl cards list [-tags <expr>] [-render html] <deck-id>")
+		}
+		if err := expectArgs(fs.Args(), 1, "cards list [-tags <expr>] [-render html] <deck-id>"); err != nil {
+			return err
+		}
+		cards, err := a.decks.GetCardsRendered(a.ctx, fs.Arg(0), *tags, *render)
+		if err != nil {
+			return err
+		}
+		if cards == nil {
+			cards = []clientModel.Card{}
+		}
+		rows := [][]string{}
+		for _, c := range cards {
+			rows = append(rows, []string{strconv.Itoa(c.Position), c.ID, c.First, c.Second, strings.Join(c.Tags, ",")})
+		}
+		return a.out.print(cards, []string{"POS", "ID", "FRONT", "BACK", "TAGS"}, rows)
+
+	case "add":
+		fs := flag.NewFlagSet("cards add", flag.ContinueOnError)
+		format := fs.String("format", "", "format of the faces: plain, markdown or html")
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl cards add [-format plain|markdown|html] <deck-id> <card-id> <front> <back>")
+		}
+		if err := expectArgs(fs.Args(), 4, "cards add [-format plain|markdown|html] <deck-id> <card-id> <front> <back>"); err != nil {
+			return err
+		}
+		c := clientModel.Card{ID: fs.Arg(1), First: fs.Arg(2), Second: fs.Arg(3), Format: *format}
+		if err := a.decks.PostCard(a.ctx, fs.Arg(0), mapper.FromClientCard(c)); err != nil {
+			return err
+		}
+		a.out.message("card %s added to deck %s", c.ID, fs.Arg(0))
+		return nil
+
+	case "rm":
+		if err := expectArgs(args[1:], 2, "cards rm <deck-id> <card-id>"); err != nil {
+			return err
+		}
+		if err := a.decks.DeleteCard(a.ctx, args[1], args[2]); err != nil {
+			return err
+		}
+		a.out.message("card %s removed from deck %s", args[2], args[1])
+		return nil
+
+	case "move":
+		fs := flag.NewFlagSet("cards move", flag.ContinueOnError)
+		before := fs.String("before", "", "move the card right before this one")
+		after := fs.String("after", "", "move the card right after this one")
+		index := fs.Int("index", -1, "move the card to this position, from 0")
+		const move = "cards move -before <card-id>|-after <card-id>|-index <n> <deck-id> <card-id>"
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl %s", move)
+		}
+		if err := expectArgs(fs.Args(), 2, move); err != nil {
+			return err
+		}
+		m := clientModel.CardMove{Before: *before, After: *after}
+		if *index >= 0 {
+			m.Index = index
+		}
+		cards, err := a.ordering.MoveCard(a.ctx, fs.Arg(0), fs.Arg(1), m)
+		if err != nil {
+			return err
+		}
+		for _, c := range cards {
+			if c.ID == fs.Arg(1) {
+				a.out.message("card %s moved to position %d", c.ID, c.Position)
+			}
+		}
+		return nil
+
+	case "shuffle":
+		fs := flag.NewFlagSet("cards shuffle", flag.ContinueOnError)
+		seed := fs.Int64("seed", 0, "seed giving the order, random when missing")
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl cards shuffle [-seed <n>] <deck-id>")
+		}
+		if err := expectArgs(fs.Args(), 1, "cards shuffle [-seed <n>] <deck-id>"); err != nil {
+			return err
+		}
+		var given *int64
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "seed" {
+				given = seed
+			}
+		})
+		_, used, err := a.ordering.ShuffleCards(a.ctx, fs.Arg(0), given)
+		if err != nil {
+			return err
+		}
+		a.out.message("deck %s shuffled with seed %d", fs.Arg(0), used)
+		return nil
+	}
+	return usageError("unknown cards command %q", args[0])
+}
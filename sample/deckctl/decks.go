@@ -0,0 +1,191 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+func (a *app) decksCmd(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: deckctl decks list|get|create|rename|delete|move|tree|clone|merge|split")
+	}
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("decks list", flag.ContinueOnError)
+		tags := fs.String("tags", "", "list the decks whose tags match this expression")
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl decks list [-tags <expr>]")
+		}
+		if err := expectArgs(fs.Args(), 0, "decks list [-tags <expr>]"); err != nil {
+			return err
+		}
+		decks, err := a.decks.GetDecksTagged(a.ctx, *tags)
+		if err != nil {
+			return err
+		}
+		if decks == nil {
+			decks = []clientModel.Deck{}
+		}
+		rows := [][]string{}
+		for _, d := range decks {
+			rows = append(rows, []string{d.ID, d.Name, strconv.Itoa(len(d.Cards)), strings.Join(d.Tags, ",")})
+		}
+		return a.out.print(decks, []string{"ID", "NAME", "CARDS", "TAGS"}, rows)
+
+	case "get":
+		if err := expectArgs(args[1:], 1, "decks get <deck-id>"); err != nil {
+			return err
+		}
+		d, err := a.decks.GetDeck(a.ctx, args[1])
+		if err != nil {
+			return err
+		}
+		return a.out.print(d, []string{"ID", "NAME", "CARDS", "TAGS"}, [][]string{{d.ID, d.Name, strconv.Itoa(len(d.Cards)), strings.Join(d.Tags, ",")}})
+
+	case "create":
+		if err := expectArgs(args[1:], 2, "decks create <deck-id> <name>"); err != nil {
+			return err
+		}
+		d := clientModel.Deck{ID: args[1], Name: args[2]}
+		if err := a.decks.PostDeck(a.ctx, mapper.FromClientDeck(d)); err != nil {
+			return err
+		}
+		a.out.message("deck %s created", d.ID)
+		return nil
+
+	case "rename":
+		if err := expectArgs(args[1:], 2, "decks rename <deck-id> <name>"); err != nil {
+			return err
+		}
+		d, err := a.decks.GetDeck(a.ctx, args[1])
+		if err != nil {
+			return err
+		}
+		d.Name = args[2]
+		if err := a.decks.PutDeck(a.ctx, d.ID, mapper.FromClientDeck(d)); err != nil {
+			return err
+		}
+		a.out.message("deck %s renamed", d.ID)
+		return nil
+
+	case "delete":
+		fs := flag.NewFlagSet("decks delete", flag.ContinueOnError)
+		children := fs.String("children", "", "delete the sub-decks too (cascade) or move them up (reparent)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl decks delete [-children cascade|reparent] <deck-id>")
+		}
+		if err := expectArgs(fs.Args(), 1, "decks delete [-children cascade|reparent] <deck-id>"); err != nil {
+			return err
+		}
+		if err := a.tree.DeleteSubtree(a.ctx, fs.Arg(0), *children); err != nil {
+			return err
+		}
+		a.out.message("deck %s deleted", fs.Arg(0))
+		return nil
+
+	case "move":
+		if len(args) != 2 && len(args) != 3 {
+			return usageError("usage: deckctl decks move <deck-id> [<parent-id>]")
+		}
+		var parent string
+		if len(args) == 3 {
+			parent = args[2]
+		}
+		if _, err := a.tree.MoveDeck(a.ctx, args[1], parent); err != nil {
+			return err
+		}
+		if parent == "" {
+			a.out.message("deck %s moved to the top level", args[1])
+			return nil
+		}
+		a.out.message("deck %s moved under %s", args[1], parent)
+		return nil
+
+	case "tree":
+		if err := expectArgs(args[1:], 1, "decks tree <deck-id>"); err != nil {
+			return err
+		}
+		t, err := a.tree.GetTree(a.ctx, args[1])
+		if err != nil {
+			return err
+		}
+		rows := [][]string{}
+		var walk func(n clientModel.DeckNode)
+		walk = func(n clientModel.DeckNode) {
+			rows = append(rows, []string{n.ID, n.Path, strconv.Itoa(n.CardCount), strconv.Itoa(n.TotalCardCount)})
+			for _, c := range n.Children {
+				walk(c)
+			}
+		}
+		walk(t)
+		return a.out.print(t, []string{"ID", "PATH", "CARDS", "TOTAL"}, rows)
+
+	case "clone":
+		if len(args) != 3 && len(args) != 4 {
+			return usageError("usage: deckctl decks clone <deck-id> <new-id> [<name>]")
+		}
+		clone := clientModel.DeckClone{ID: args[2]}
+		if len(args) == 4 {
+			clone.Name = args[3]
+		}
+		d, err := a.ops.CloneDeck(a.ctx, args[1], clone)
+		if err != nil {
+			return err
+		}
+		a.out.message("deck %s cloned into %s", args[1], d.ID)
+		return nil
+
+	case "merge":
+		fs := flag.NewFlagSet("decks merge", flag.ContinueOnError)
+		conflicts := fs.String("conflicts", clientModel.ConflictSkip, "cards sharing an ID: skip, rename or overwrite")
+		keep := fs.Bool("keep", false, "keep the merged decks")
+		name := fs.String("name", "", "name of the target deck")
+		const merge = "decks merge [-conflicts skip|rename|overwrite] [-keep] [-name <name>] <target-id> <deck-id>..."
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl %s", merge)
+		}
+		if fs.NArg() < 2 {
+			return usageError("usage: deckctl %s", merge)
+		}
+		d, err := a.ops.MergeDecks(a.ctx, clientModel.DeckMerge{
+			Sources:     fs.Args()[1:],
+			Target:      fs.Arg(0),
+			Name:        *name,
+			Conflicts:   *conflicts,
+			KeepSources: *keep,
+		})
+		if err != nil {
+			return err
+		}
+		a.out.message("decks merged into %s, %d cards", d.ID, len(d.Cards))
+		return nil
+
+	case "split":
+		fs := flag.NewFlagSet("decks split", flag.ContinueOnError)
+		tags := fs.String("tags", "", "move the cards whose tags match this expression")
+		name := fs.String("name", "", "name of the new deck")
+		const split = "decks split [-tags <expr>] [-name <name>] <deck-id> <new-id> [<card-id>...]"
+		if err := fs.Parse(args[1:]); err != nil {
+			return usageError("usage: deckctl %s", split)
+		}
+		if fs.NArg() < 2 {
+			return usageError("usage: deckctl %s", split)
+		}
+		_, d, err := a.ops.SplitDeck(a.ctx, fs.Arg(0), clientModel.DeckSplit{
+			ID:      fs.Arg(1),
+			Name:    *name,
+			CardIDs: fs.Args()[2:],
+			Tags:    *tags,
+		})
+		if err != nil {
+			return err
+		}
+		a.out.message("%d cards moved into deck %s", len(d.Cards), d.ID)
+		return nil
+	}
+	return usageError("unknown decks command %q", args[0])
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/client"
+	deckops "github.com/TangiFavennec/go-service-sample/sample/service/server/deckops"
+	media "github.com/TangiFavennec/go-service-sample/sample/service/server/media"
+	ordering "github.com/TangiFavennec/go-service-sample/sample/service/server/ordering"
+	quiz "github.com/TangiFavennec/go-service-sample/sample/service/server/quiz"
+	reviews "github.com/TangiFavennec/go-service-sample/sample/service/server/reviews"
+	stats "github.com/TangiFavennec/go-service-sample/sample/service/server/stats"
+	tree "github.com/TangiFavennec/go-service-sample/sample/service/server/tree"
+)
+
+const usage = `deckctl manages decks and studies them in the terminal.
+
+Usage:
+  deckctl [flags] decks list [-tags <expr>]
+  deckctl [flags] decks get <deck-id>
+  deckctl [flags] decks create <deck-id> <name>
+  deckctl [flags] decks rename <deck-id> <name>
+  deckctl [flags] decks delete [-children cascade|reparent] <deck-id>
+  deckctl [flags] decks move <deck-id> [<parent-id>]
+  deckctl [flags] decks tree <deck-id>
+  deckctl [flags] decks clone <deck-id> <new-id> [<name>]
+  deckctl [flags] decks merge [-conflicts skip|rename|overwrite] [-keep] [-name <name>] <target-id> <deck-id>...
+  deckctl [flags] decks split [-tags <expr>] [-name <name>] <deck-id> <new-id> [<card-id>...]
+  deckctl [flags] cards list [-tags <expr>] [-render html] <deck-id>
+  deckctl [flags] cards add [-format plain|markdown|html] <deck-id> <card-id> <front> <back>
+  deckctl [flags] cards rm <deck-id> <card-id>
+  deckctl [flags] cards move -before <card-id>|-after <card-id>|-index <n> <deck-id> <card-id>
+  deckctl [flags] cards shuffle [-seed <n>] <deck-id>
+  deckctl [flags] import [-format csv|json] <file>
+  deckctl [flags] export [-format csv|json] [-deck <deck-id>] [<file>]
+  deckctl [flags] media upload <file>
+  deckctl [flags] study [-tags <expr>] [-subtree] <deck-id>
+  deckctl [flags] quiz [-type multiple_choice|typing|matching] [-count <n>] [-tags <expr>] <deck-id>
+  deckctl [flags] stats [-days <n>] [<deck-id>]
+
+Tag expressions such as 'tag:verbs AND NOT tag:irregular' select the decks
+or cards by their tags, cards inheriting the tags of their deck.
+
+Cards show uploaded media by holding their ref, such as media:3a7bd3e2...,
+in a face.
+
+Flags:
+`
+
+// app holds what every subcommand needs.
+type app struct {
+	ctx      context.Context
+	decks    *client.Client
+	reviews  reviews.Endpoints
+	tree     tree.Endpoints
+	ops      deckops.Endpoints
+	ordering ordering.Endpoints
+	media    media.Endpoints
+	quiz     quiz.Endpoints
+	stats    stats.Endpoints
+	out      printer
+}
+
+func main() {
+	var (
+		addr   = flag.String("addr", "http://localhost:8080", "decksvc address")
+		token  = flag.String("token", os.Getenv("DECKCTL_TOKEN"), "bearer token (defaults to $DECKCTL_TOKEN)")
+		format = flag.String("o", "table", "output format: table, json or yaml")
+	)
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	out, err := newPrinter(*format, os.Stdout)
+	if err != nil {
+		fail(err)
+	}
+	decks, err := client.New(client.WithBaseURL(*addr), client.WithAuthToken(*token))
+	if err != nil {
+		fail(err)
+	}
+	var options []httptransport.ClientOption
+	if *token != "" {
+		options = append(options, httptransport.ClientBefore(func(ctx context.Context, r *http.Request) context.Context {
+			r.Header.Set("Authorization", "Bearer "+*token)
+			return ctx
+		}))
+	}
+	rev, err := reviews.MakeClientEndpoints(*addr, options...)
+	if err != nil {
+		fail(err)
+	}
+	tr, err := tree.MakeClientEndpoints(*addr, options...)
+	if err != nil {
+		fail(err)
+	}
+	ops, err := deckops.MakeClientEndpoints(*addr, options...)
+	if err != nil {
+		fail(err)
+	}
+	ord, err := ordering.MakeClientEndpoints(*addr, options...)
+	if err != nil {
+		fail(err)
+	}
+	med, err := media.MakeClientEndpoints(*addr, options...)
+	if err != nil {
+		fail(err)
+	}
+	qz, err := quiz.MakeClientEndpoints(*addr, options...)
+	if err != nil {
+		fail(err)
+	}
+	st, err := stats.MakeClientEndpoints(*addr, options...)
+	if err != nil {
+		fail(err)
+	}
+	a := &app{ctx: context.Background(), decks: decks, reviews: rev, tree: tr, ops: ops, ordering: ord, media: med, quiz: qz, stats: st, out: out}
+
+	args := flag.Args()
+	switch args[0] {
+	case "decks":
+		err = a.decksCmd(args[1:])
+	case "cards":
+		err = a.cardsCmd(args[1:])
+	case "import":
+		err = a.importCmd(args[1:])
+	case "export":
+		err = a.exportCmd(args[1:])
+	case "media":
+		err = a.mediaCmd(args[1:])
+	case "study":
+		err = a.studyCmd(args[1:])
+	case "quiz":
+		err = a.quizCmd(args[1:])
+	case "stats":
+		err = a.statsCmd(args[1:])
+	default:
+		err = usageError("unknown command %q", args[0])
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+type usageErr string
+
+func (e usageErr) Error() string { return string(e) }
+
+func usageError(format string, args ...interface{}) error {
+	return usageErr(fmt.Sprintf(format, args...))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "deckctl:", err)
+	if _, ok := err.(usageErr); ok {
+		fmt.Fprintln(os.Stderr, "run deckctl -h for usage")
+		os.Exit(2)
+	}
+	os.Exit(1)
+}
+
+// expectArgs checks the number of positional arguments of a subcommand.
+func expectArgs(args []string, n int, syntax string) error {
+	if len(args) != n {
+		return usageError("usage: deckctl %s", syntax)
+	}
+	return nil
+}
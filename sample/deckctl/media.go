@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+func (a *app) mediaCmd(args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: deckctl media upload")
+	}
+	switch args[0] {
+	case "upload":
+		if err := expectArgs(args[1:], 1, "media upload <file>"); err != nil {
+			return err
+		}
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		m, err := a.media.Upload(a.ctx, filepath.Base(args[1]), f)
+		if err != nil {
+			return err
+		}
+		return a.out.print(m, []string{"REF", "TYPE", "SIZE"}, [][]string{{m.Ref, m.ContentType, strconv.FormatInt(m.Size, 10)}})
+	}
+	return usageError("unknown media command %q", args[0])
+}
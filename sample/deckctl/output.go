@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// printer renders command results in the format chosen with -o.
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	switch format {
+	case "table", "json", "yaml":
+		return printer{format: format, w: w}, nil
+	}
+	return printer{}, usageError("unknown output format %q", format)
+}
+
+// print writes v as JSON or YAML, or the given header and rows as a table.
+func (p printer) print(v interface{}, header []string, rows [][]string) error {
+	switch p.format {
+	case "json":
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = p.w.Write(b)
+		return err
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	printRow(tw, header)
+	for _, r := range rows {
+		printRow(tw, r)
+	}
+	return tw.Flush()
+}
+
+// message writes a confirmation line, in table format only.
+func (p printer) message(format string, args ...interface{}) {
+	if p.format == "table" {
+		fmt.Fprintf(p.w, format+"\n", args...)
+	}
+}
+
+func printRow(w io.Writer, cols []string) {
+	for i, c := range cols {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, c)
+	}
+	fmt.Fprintln(w)
+}
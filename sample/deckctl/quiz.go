@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+func (a *app) quizCmd(args []string) error {
+	fs := flag.NewFlagSet("quiz", flag.ContinueOnError)
+	quizType := fs.String("type", clientModel.QuizMultipleChoice, "multiple_choice, typing or matching")
+	count := fs.Int("count", 0, "number of cards to ask (defaults to 10)")
+	tags := fs.String("tags", "", "ask the cards whose tags match this expression")
+	syntax := "quiz [-type multiple_choice|typing|matching] [-count <n>] [-tags <expr>] <deck-id>"
+	if err := fs.Parse(args); err != nil {
+		return usageError("usage: deckctl %s", syntax)
+	}
+	if err := expectArgs(fs.Args(), 1, syntax); err != nil {
+		return err
+	}
+	q, err := a.quiz.NewQuiz(a.ctx, fs.Arg(0), *quizType, *count, *tags)
+	if err != nil {
+		return err
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	var attempt clientModel.QuizAttempt
+	for i, question := range q.Questions {
+		answer := clientModel.QuizAnswer{QuestionID: question.ID}
+		fmt.Printf("\n[%d/%d] ", i+1, len(q.Questions))
+		switch q.Type {
+		case clientModel.QuizMultipleChoice:
+			fmt.Println(question.Prompt)
+			for j, c := range question.Choices {
+				fmt.Printf("  %d. %s\n", j+1, c)
+			}
+			fmt.Print("choice: ")
+		case clientModel.QuizTyping:
+			fmt.Println(question.Prompt)
+			fmt.Print("answer: ")
+		case clientModel.QuizMatching:
+			fmt.Println("match each prompt with a choice")
+			for j, p := range question.Prompts {
+				fmt.Printf("  %c. %s\n", 'A'+j, p)
+			}
+			for j, c := range question.Choices {
+				fmt.Printf("  %d. %s\n", j+1, c)
+			}
+			fmt.Printf("choices for A to %c, separated by spaces: ", 'A'+len(question.Prompts)-1)
+		}
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch q.Type {
+		case clientModel.QuizMultipleChoice:
+			if n, err := strconv.Atoi(line); err == nil {
+				n--
+				answer.Choice = &n
+			}
+		case clientModel.QuizTyping:
+			answer.Text = line
+		case clientModel.QuizMatching:
+			for _, f := range strings.Fields(line) {
+				n, _ := strconv.Atoi(f)
+				answer.Matches = append(answer.Matches, n-1)
+			}
+		}
+		attempt.Answers = append(attempt.Answers, answer)
+		if err == io.EOF {
+			break
+		}
+	}
+
+	res, err := a.quiz.ScoreQuiz(a.ctx, q.ID, attempt)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{}
+	for _, r := range res.Questions {
+		verdict := "wrong"
+		switch {
+		case r.Close:
+			verdict = "close"
+		case r.Correct:
+			verdict = "right"
+		}
+		rows = append(rows, []string{r.QuestionID, verdict, fmt.Sprintf("%d/%d", r.Score, r.MaxScore), strings.Join(r.Expected, ", ")})
+	}
+	fmt.Println()
+	if err := a.out.print(res, []string{"QUESTION", "RESULT", "SCORE", "EXPECTED"}, rows); err != nil {
+		return err
+	}
+	a.out.message("score %d/%d", res.Score, res.MaxScore)
+	return nil
+}
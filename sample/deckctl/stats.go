@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+func (a *app) statsCmd(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	days := fs.Int("days", 0, "days of activity and forecast (defaults to 30)")
+	syntax := "stats [-days <n>] [<deck-id>]"
+	if err := fs.Parse(args); err != nil {
+		return usageError("usage: deckctl %s", syntax)
+	}
+	if fs.NArg() > 1 {
+		return usageError("usage: deckctl %s", syntax)
+	}
+	var st clientModel.Stats
+	var err error
+	if fs.NArg() == 1 {
+		st, err = a.stats.GetDeckStats(a.ctx, fs.Arg(0), *days)
+	} else {
+		st, err = a.stats.GetStats(a.ctx, *days)
+	}
+	if err != nil {
+		return err
+	}
+	var due, studied int
+	var spent int64
+	for i := range st.Forecast {
+		due += st.Forecast[i].Due
+		studied += st.Days[i].Reviews
+		spent += st.Days[i].DurationMs
+	}
+	period := strconv.Itoa(len(st.Days)) + " days"
+	rows := [][]string{
+		{"cards", strconv.Itoa(st.Cards)},
+		{"new / learning / young / mature", fmt.Sprintf("%d / %d / %d / %d", st.Maturity.New, st.Maturity.Learning, st.Maturity.Young, st.Maturity.Mature)},
+		{"average ease", fmt.Sprintf("%.2f", st.AverageEase)},
+		{"average difficulty", fmt.Sprintf("%.2f", st.AverageDifficulty)},
+		{"due within " + period, strconv.Itoa(due)},
+		{"reviews over " + period, strconv.Itoa(studied)},
+		{"time spent over " + period, fmt.Sprintf("%dm", spent/60000)},
+		{"streak (longest)", fmt.Sprintf("%d (%d) days", st.Streak.Current, st.Streak.Longest)},
+	}
+	for _, b := range st.Retention {
+		ages := fmt.Sprintf("%d+ days", b.MinAgeDays)
+		if b.MaxAgeDays > 0 {
+			ages = fmt.Sprintf("%d-%d days", b.MinAgeDays, b.MaxAgeDays)
+		}
+		rows = append(rows, []string{"retention, cards of " + ages, fmt.Sprintf("%.0f%% of %d", 100*b.Retention, b.Reviews)})
+	}
+	return a.out.print(st, []string{"STAT", "VALUE"}, rows)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+var gradeKeys = map[byte]int{
+	'1': clientModel.GradeAgain,
+	'2': clientModel.GradeHard,
+	'3': clientModel.GradeGood,
+	'4': clientModel.GradeEasy,
+}
+
+var gradeNames = map[int]string{
+	clientModel.GradeAgain: "again",
+	clientModel.GradeHard:  "hard",
+	clientModel.GradeGood:  "good",
+	clientModel.GradeEasy:  "easy",
+}
+
+func (a *app) studyCmd(args []string) error {
+	fs := flag.NewFlagSet("study", flag.ContinueOnError)
+	tags := fs.String("tags", "", "study the cards whose tags match this expression")
+	subtree := fs.Bool("subtree", false, "study the cards of the sub-decks too")
+	if err := fs.Parse(args); err != nil {
+		return usageError("usage: deckctl study [-tags <expr>] [-subtree] <deck-id>")
+	}
+	if err := expectArgs(fs.Args(), 1, "study [-tags <expr>] [-subtree] <deck-id>"); err != nil {
+		return err
+	}
+	deckID := fs.Arg(0)
+	var cards []clientModel.StudyCard
+	if *subtree {
+		var err error
+		if cards, err = a.tree.GetStudyCards(a.ctx, deckID, *tags); err != nil {
+			return err
+		}
+	} else {
+		deckCards, err := a.decks.GetCardsTagged(a.ctx, deckID, *tags)
+		if err != nil {
+			return err
+		}
+		for _, c := range deckCards {
+			cards = append(cards, clientModel.StudyCard{DeckID: deckID, Card: c})
+		}
+	}
+	if len(cards) == 0 {
+		if *tags != "" {
+			fmt.Printf("deck %s has no cards matching %s\n", deckID, *tags)
+			return nil
+		}
+		fmt.Printf("deck %s has no cards\n", deckID)
+		return nil
+	}
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rnd.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+
+	keys := newKeyReader()
+	counts := map[int]int{}
+	studied := 0
+	for i, c := range cards {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(cards), c.Card.First)
+		fmt.Print("press any key to show the answer, q to stop ")
+		shown := time.Now()
+		k, err := keys.read()
+		if err != nil {
+			return err
+		}
+		if k == 'q' {
+			break
+		}
+		fmt.Printf("\n  %s\n", c.Card.Second)
+		fmt.Print("grade: 1 again, 2 hard, 3 good, 4 easy, q to stop ")
+		var grade int
+		for grade == 0 {
+			if k, err = keys.read(); err != nil {
+				return err
+			}
+			if k == 'q' {
+				break
+			}
+			grade = gradeKeys[k]
+		}
+		if grade == 0 {
+			break
+		}
+		fmt.Println(gradeNames[grade])
+		_, err = a.reviews.PostReview(a.ctx, clientModel.Review{
+			DeckID:     c.DeckID,
+			CardID:     c.Card.ID,
+			Grade:      grade,
+			DurationMs: int64(time.Since(shown) / time.Millisecond),
+		})
+		if err != nil {
+			return fmt.Errorf("recording review of card %s: %v", c.Card.ID, err)
+		}
+		counts[grade]++
+		studied++
+	}
+
+	fmt.Printf("\n%d cards studied:", studied)
+	for g := clientModel.GradeAgain; g <= clientModel.GradeEasy; g++ {
+		fmt.Printf(" %d %s", counts[g], gradeNames[g])
+	}
+	fmt.Println()
+	return nil
+}
+
+// keyReader reads single key presses from a terminal, or single non blank
+// characters when stdin is not a terminal.
+type keyReader struct {
+	fd  int
+	tty bool
+	in  *bufio.Reader
+}
+
+func newKeyReader() *keyReader {
+	fd := int(os.Stdin.Fd())
+	return &keyReader{fd: fd, tty: term.IsTerminal(fd), in: bufio.NewReader(os.Stdin)}
+}
+
+func (k *keyReader) read() (byte, error) {
+	if k.tty {
+		state, err := term.MakeRaw(k.fd)
+		if err != nil {
+			return 0, err
+		}
+		defer term.Restore(k.fd, state)
+		b := make([]byte, 1)
+		if _, err := os.Stdin.Read(b); err != nil {
+			return 0, err
+		}
+		if b[0] == 3 { // Ctrl-C is not turned into a signal in raw mode
+			return 'q', nil
+		}
+		return b[0], nil
+	}
+	for {
+		b, err := k.in.ReadByte()
+		if err == io.EOF {
+			return 'q', nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if b != '\n' && b != '\r' && b != ' ' && b != '\t' {
+			return b, nil
+		}
+	}
+}
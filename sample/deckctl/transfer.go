@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+// csvHeader is the layout of CSV imports and exports, one Card per row.
+var csvHeader = []string{"deck_id", "deck_name", "card_id", "front", "back"}
+
+// formatOf returns the explicit format, or guesses it from the file extension.
+func formatOf(format, file string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	}
+	switch format {
+	case "csv", "json":
+		return format, nil
+	}
+	return "", usageError("unknown file format %q, use -format csv|json", format)
+}
+
+func (a *app) importCmd(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "csv or json (defaults to the file extension)")
+	if err := fs.Parse(args); err != nil {
+		return usageError("usage: deckctl import [-format csv|json] <file>")
+	}
+	if err := expectArgs(fs.Args(), 1, "import [-format csv|json] <file>"); err != nil {
+		return err
+	}
+	file := fs.Arg(0)
+	f, err := formatOf(*format, file)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	var decks []clientModel.Deck
+	if f == "json" {
+		err = json.NewDecoder(in).Decode(&decks)
+	} else {
+		decks, err = readCSV(in)
+	}
+	if err != nil {
+		return err
+	}
+
+	parentsFirst(decks)
+	var created, added, skipped int
+	for _, d := range decks {
+		err := a.decks.PostDeck(a.ctx, mapper.FromClientDeck(d))
+		if err == nil {
+			created++
+			added += len(d.Cards)
+			continue
+		}
+		if err != data.ErrAlreadyExists {
+			return fmt.Errorf("deck %s: %v", d.ID, err)
+		}
+		// Merge into the existing Deck, keeping the Cards it already has.
+		for _, c := range d.Cards {
+			switch err := a.decks.PostCard(a.ctx, d.ID, mapper.FromClientCard(c)); err {
+			case nil:
+				added++
+			case data.ErrAlreadyExists:
+				skipped++
+			default:
+				return fmt.Errorf("deck %s, card %s: %v", d.ID, c.ID, err)
+			}
+		}
+	}
+	a.out.message("%d decks created, %d cards added, %d existing cards skipped", created, added, skipped)
+	return nil
+}
+
+func (a *app) exportCmd(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "", "csv or json (defaults to the file extension, json on stdout)")
+	deckID := fs.String("deck", "", "export a single deck")
+	if err := fs.Parse(args); err != nil {
+		return usageError("usage: deckctl export [-format csv|json] [-deck <deck-id>] [<file>]")
+	}
+	if fs.NArg() > 1 {
+		return usageError("usage: deckctl export [-format csv|json] [-deck <deck-id>] [<file>]")
+	}
+	file := fs.Arg(0)
+	if *format == "" && file == "" {
+		*format = "json"
+	}
+	f, err := formatOf(*format, file)
+	if err != nil {
+		return err
+	}
+
+	var decks []clientModel.Deck
+	if *deckID != "" {
+		d, err := a.decks.GetDeck(a.ctx, *deckID)
+		if err != nil {
+			return err
+		}
+		decks = []clientModel.Deck{d}
+	} else if decks, err = a.decks.GetDecks(a.ctx); err != nil {
+		return err
+	}
+	if decks == nil {
+		decks = []clientModel.Deck{}
+	}
+
+	var out io.Writer = os.Stdout
+	if file != "" {
+		o, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer o.Close()
+		out = o
+	}
+	if f == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(decks)
+	}
+	return writeCSV(out, decks)
+}
+
+func readCSV(r io.Reader) ([]clientModel.Deck, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 && records[0][0] == csvHeader[0] {
+		records = records[1:]
+	}
+	var decks []clientModel.Deck
+	index := map[string]int{}
+	for _, rec := range records {
+		i, ok := index[rec[0]]
+		if !ok {
+			i = len(decks)
+			index[rec[0]] = i
+			decks = append(decks, clientModel.Deck{ID: rec[0], Name: rec[1]})
+		}
+		if rec[2] == "" {
+			continue // Deck without Cards
+		}
+		decks[i].Cards = append(decks[i].Cards, clientModel.Card{ID: rec[2], First: rec[3], Second: rec[4]})
+	}
+	return decks, nil
+}
+
+func writeCSV(w io.Writer, decks []clientModel.Deck) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, d := range decks {
+		if len(d.Cards) == 0 {
+			cw.Write([]string{d.ID, d.Name, "", "", ""})
+		}
+		for _, c := range d.Cards {
+			cw.Write([]string{d.ID, d.Name, c.ID, c.First, c.Second})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// parentsFirst orders decks so that parents are created before their
+// sub-decks.
+func parentsFirst(decks []clientModel.Deck) {
+	parents := map[string]string{}
+	for _, d := range decks {
+		parents[d.ID] = d.Parent
+	}
+	depth := func(id string) int {
+		n := 0
+		for p := parents[id]; p != "" && n <= len(parents); p = parents[p] {
+			n++
+		}
+		return n
+	}
+	sort.SliceStable(decks, func(i, j int) bool { return depth(decks[i].ID) < depth(decks[j].ID) })
+}
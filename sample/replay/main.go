@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	eventsourcing "github.com/TangiFavennec/go-service-sample/sample/service/data/eventsourcing"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+// replay rebuilds the Deck state from an event stream file into another
+// repository implementation and prints the resulting Decks as JSON.
+func main() {
+	var (
+		eventsFile = flag.String("events.file", "events.log", "event stream file to replay")
+		target     = flag.String("target", "memory", "destination repository: memory")
+	)
+	flag.Parse()
+
+	store, err := eventsourcing.NewFileStore(*eventsFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *target != "memory" {
+		fmt.Fprintf(os.Stderr, "unknown target %q\n", *target)
+		os.Exit(1)
+	}
+	dst := inmem.NewInmemRepository()
+	ctx := context.Background()
+
+	n, err := eventsourcing.Replay(ctx, store, dst)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "replay stopped after %d events: %v\n", n, err)
+		os.Exit(1)
+	}
+	decks, err := dst.GetAllDecks(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "replayed %d events into %s repository\n", n, *target)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(mapper.ToClientDecks(decks))
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/circuitbreaker"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/ratelimit"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/sony/gobreaker"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	endpoints "github.com/TangiFavennec/go-service-sample/sample/service/server/endpoints"
+	idempotency "github.com/TangiFavennec/go-service-sample/sample/service/server/idempotency"
+)
+
+// Client is a decksvc client over HTTP. It implements server.SampleService:
+// business errors come back as the data package errors, other error answers
+// as *endpoints.HTTPError.
+type Client struct {
+	endpoints.Endpoints
+}
+
+var _ server.SampleService = (*Client)(nil)
+
+// New returns a Client configured by the given options.
+func New(opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	httpOptions := []httptransport.ClientOption{
+		httptransport.SetClient(o.httpClient),
+		httptransport.ClientBefore(idempotency.HTTPClientBefore),
+	}
+	if o.token != "" {
+		token := o.token
+		httpOptions = append(httpOptions, httptransport.ClientBefore(
+			func(ctx context.Context, r *http.Request) context.Context {
+				r.Header.Set("Authorization", "Bearer "+token)
+				return ctx
+			},
+		))
+	}
+	e, err := endpoints.MakeClientEndpoints(o.baseURL, httpOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	var breaker endpoint.Middleware
+	if o.breaker != nil {
+		settings := *o.breaker
+		if settings.IsSuccessful == nil {
+			settings.IsSuccessful = func(err error) bool { return err == nil || isBusinessError(err) }
+		}
+		breaker = circuitbreaker.Gobreaker(gobreaker.NewCircuitBreaker(settings))
+	}
+
+	wrap := func(next endpoint.Endpoint, idempotent bool) endpoint.Endpoint {
+		if o.timeout > 0 {
+			next = timeout(o.timeout)(next)
+		}
+		if o.limiter != nil {
+			next = ratelimit.NewDelayingLimiter(o.limiter)(next)
+		}
+		if breaker != nil {
+			next = breaker(next)
+		}
+		if idempotent && o.retries > 0 {
+			next = retry(o.retries, o.backoff)(next)
+		}
+		return next
+	}
+
+	// POST calls are made idempotent by sending the same Idempotency-Key
+	// with all of their attempts.
+	e.PostDeckEndpoint = withIdempotencyKey(wrap(e.PostDeckEndpoint, true))
+	e.GetDeckEndpoint = wrap(e.GetDeckEndpoint, true)
+	e.PutDeckEndpoint = wrap(e.PutDeckEndpoint, true)
+	e.GetDecksEndpoint = wrap(e.GetDecksEndpoint, true)
+	e.DeleteDeckEndpoint = wrap(e.DeleteDeckEndpoint, true)
+	e.GetCardsEndpoint = wrap(e.GetCardsEndpoint, true)
+	e.GetCardEndpoint = wrap(e.GetCardEndpoint, true)
+	e.PostCardEndpoint = withIdempotencyKey(wrap(e.PostCardEndpoint, true))
+	e.DeleteCardEndpoint = wrap(e.DeleteCardEndpoint, true)
+	return &Client{Endpoints: e}, nil
+}
+
+// timeout bounds every call of the endpoint.
+func timeout(d time.Duration) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
+
+// withIdempotencyKey gives every call a new idempotency key, unless the
+// caller context already carries one.
+func withIdempotencyKey(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		if idempotency.FromContext(ctx) == "" {
+			ctx = idempotency.NewContext(ctx, idempotency.NewKey())
+		}
+		return next(ctx, request)
+	}
+}
+
+// retry calls the endpoint again, after a jittered exponential backoff, as
+// long as the error is worth retrying and the caller context is alive.
+func retry(retries int, backoff time.Duration) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			for attempt := 0; ; attempt++ {
+				response, err = next(ctx, request)
+				if err == nil || attempt >= retries || !retryable(err) {
+					return response, err
+				}
+				ceiling := backoff << uint(attempt)
+				if ceiling <= 0 {
+					ceiling = backoff
+				}
+				delay := time.Duration(rand.Int63n(int64(ceiling) + 1))
+				select {
+				case <-ctx.Done():
+					return response, err
+				case <-time.After(delay):
+				}
+			}
+		}
+	}
+}
+
+func retryable(err error) bool {
+	switch err {
+	case context.Canceled, gobreaker.ErrOpenState, gobreaker.ErrTooManyRequests:
+		return false
+	}
+	var httpErr *endpoints.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	return !isBusinessError(err)
+}
+
+// isBusinessError tells whether the server answered, and refused the call.
+func isBusinessError(err error) bool {
+	if server.IsKnownError(err) {
+		return true
+	}
+	var httpErr *endpoints.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 400 && httpErr.StatusCode < 500 && httpErr.StatusCode != http.StatusTooManyRequests
+	}
+	return false
+}
@@ -2,8 +2,14 @@ package model
 
 // Card is a field of a user Deck.
 // ID should be unique within the Deck (at a minimum).
+// Format tells how First and Second are written: plain (the default),
+// markdown or html.
+// Position is the index of the Card in its Deck, from 0, set on reads only.
 type Card struct {
-	ID     string `json:"id"`
-	First  string `json:"first"`
-	Second string `json:"second"`
+	ID       string   `json:"id"`
+	First    string   `json:"first"`
+	Second   string   `json:"second"`
+	Tags     []string `json:"tags,omitempty"`
+	Format   string   `json:"format,omitempty"`
+	Position int      `json:"position"`
 }
@@ -0,0 +1,20 @@
+package model
+
+// CardMove gives a Card another position in its Deck: right before or
+// after another Card, or at Index. Exactly one of them is set.
+type CardMove struct {
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	Index  *int   `json:"index,omitempty"`
+}
+
+// CardOrder lists every Card of a Deck in its new order.
+type CardOrder struct {
+	CardIDs []string `json:"card_ids"`
+}
+
+// CardShuffle shuffles the Cards of a Deck. The same Seed always gives
+// the same order, a random one is picked when missing.
+type CardShuffle struct {
+	Seed *int64 `json:"seed,omitempty"`
+}
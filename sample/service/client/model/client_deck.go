@@ -1,9 +1,12 @@
 package model
 
 // Deck represents a single user Deck.
-// ID should be globally unique.
+// ID should be unique per Owner.
 type Deck struct {
-	ID    string `json:"id"`
-	Name  string `json:"name,omitempty"`
-	Cards []Card `json:"cards,omitempty"`
+	ID     string   `json:"id"`
+	Owner  string   `json:"owner,omitempty"`
+	Name   string   `json:"name,omitempty"`
+	Cards  []Card   `json:"cards,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+	Parent string   `json:"parent,omitempty"`
 }
@@ -0,0 +1,36 @@
+package model
+
+// DeckClone copies a Deck and its Cards into a new Deck of the caller. The
+// copy keeps the name of the Deck unless Name is set, CardIDs renames its
+// Cards (old ID to new ID).
+type DeckClone struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name,omitempty"`
+	CardIDs map[string]string `json:"card_ids,omitempty"`
+}
+
+// Strategies for the Cards of merged Decks sharing an ID.
+const (
+	ConflictSkip      = "skip"      // keep the first Card
+	ConflictRename    = "rename"    // give the next ones a new ID
+	ConflictOverwrite = "overwrite" // keep the last Card
+)
+
+// DeckMerge gathers the Cards of Sources into Target, created with Name
+// when missing. Sources are deleted unless KeepSources is set.
+type DeckMerge struct {
+	Sources     []string `json:"sources"`
+	Target      string   `json:"target"`
+	Name        string   `json:"name,omitempty"`
+	Conflicts   string   `json:"conflicts,omitempty"`
+	KeepSources bool     `json:"keep_sources,omitempty"`
+}
+
+// DeckSplit moves the Cards of a Deck listed in CardIDs and matching the
+// Tags expression into a new Deck of the caller.
+type DeckSplit struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	CardIDs []string `json:"card_ids,omitempty"`
+	Tags    string   `json:"tags,omitempty"`
+}
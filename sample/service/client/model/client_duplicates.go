@@ -0,0 +1,16 @@
+package model
+
+// DuplicateCard is a Card of a DuplicateGroup, along with its Deck.
+type DuplicateCard struct {
+	DeckID string `json:"deck_id"`
+	Card   Card   `json:"card"`
+}
+
+// DuplicateGroup gathers alike Cards. Exact groups hold Cards with
+// identical faces once normalized, Similarity is the lowest similarity of
+// the pairs of alike Cards of the group.
+type DuplicateGroup struct {
+	Exact      bool            `json:"exact"`
+	Similarity float64         `json:"similarity"`
+	Cards      []DuplicateCard `json:"cards"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// Media is a stored image or audio blob, named after the SHA-256 sum of its
+// content. Card faces reference it with Ref, and URL downloads it.
+type Media struct {
+	Hash        string `json:"hash"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Ref         string `json:"ref"`
+	URL         string `json:"url"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// Types of Quiz.
+const (
+	// QuizMultipleChoice asks for the Second face of a Card among others.
+	QuizMultipleChoice = "multiple_choice"
+	// QuizTyping asks to type the Second face of a Card.
+	QuizTyping = "typing"
+	// QuizMatching asks to pair the First faces of Cards with their Second
+	// faces.
+	QuizMatching = "matching"
+)
+
+// Quiz is a set of Questions drawn from the Cards of a Deck, answered once
+// before it expires.
+type Quiz struct {
+	ID        string         `json:"id"`
+	DeckID    string         `json:"deck_id"`
+	Type      string         `json:"type"`
+	Questions []QuizQuestion `json:"questions"`
+	Expires   time.Time      `json:"expires"`
+}
+
+// QuizQuestion asks for the Second face of the Card with Prompt as First
+// face, among Choices for multiple choice questions, or for the Choice
+// matching each of the Prompts of a matching question. Format is the one
+// of the faces.
+type QuizQuestion struct {
+	ID      string   `json:"id"`
+	Prompt  string   `json:"prompt,omitempty"`
+	Prompts []string `json:"prompts,omitempty"`
+	Choices []string `json:"choices,omitempty"`
+	Format  string   `json:"format,omitempty"`
+}
+
+// QuizAttempt answers the Questions of a Quiz, unanswered ones scoring 0.
+type QuizAttempt struct {
+	Answers []QuizAnswer `json:"answers"`
+}
+
+// QuizAnswer answers a Question: with the index of a Choice, the typed Text,
+// or the index of the Choice matching each Prompt, depending on the Quiz.
+type QuizAnswer struct {
+	QuestionID string `json:"question_id"`
+	Choice     *int   `json:"choice,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Matches    []int  `json:"matches,omitempty"`
+}
+
+// QuizResult scores an attempt: one point per right answer, or per right
+// pair of a matching question.
+type QuizResult struct {
+	QuizID    string               `json:"quiz_id"`
+	Score     int                  `json:"score"`
+	MaxScore  int                  `json:"max_score"`
+	Questions []QuizQuestionResult `json:"questions"`
+}
+
+// QuizQuestionResult scores the answer to a Question, giving the Cards asked
+// and their Expected Second faces, one per Prompt. Close answers were typed
+// with small typos.
+type QuizQuestionResult struct {
+	QuestionID string   `json:"question_id"`
+	Correct    bool     `json:"correct"`
+	Close      bool     `json:"close,omitempty"`
+	Score      int      `json:"score"`
+	MaxScore   int      `json:"max_score"`
+	CardIDs    []string `json:"card_ids"`
+	Expected   []string `json:"expected"`
+}
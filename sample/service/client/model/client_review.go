@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Grades of a Review, from worst to best recall.
+const (
+	GradeAgain = 1
+	GradeHard  = 2
+	GradeGood  = 3
+	GradeEasy  = 4
+)
+
+// Review records how well a Card was recalled during a study session.
+type Review struct {
+	DeckID     string    `json:"deck_id"`
+	CardID     string    `json:"card_id"`
+	Grade      int       `json:"grade"`
+	DurationMs int64     `json:"duration_ms,omitempty"`
+	Time       time.Time `json:"time"`
+}
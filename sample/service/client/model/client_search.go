@@ -0,0 +1,11 @@
+package model
+
+// SearchHit is a Deck (CardID empty) or a Card matching a search query.
+// Snippets holds the matching fields (name, first, second), HTML escaped,
+// with their matches in <mark> tags.
+type SearchHit struct {
+	DeckID   string            `json:"deck_id"`
+	CardID   string            `json:"card_id,omitempty"`
+	Score    float64           `json:"score"`
+	Snippets map[string]string `json:"snippets"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Roles granted to the members of a Deck, from the least to the most
+// privileged: viewers read, editors also change the Deck and its Cards,
+// admins also delete the Deck and manage its members and share links.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Member is a user granted a Role on a Deck.
+type Member struct {
+	User string `json:"user"`
+	Role string `json:"role"`
+}
+
+// ShareLink is a public read-only link to a Deck. Token is unguessable.
+type ShareLink struct {
+	Token     string    `json:"token"`
+	DeckID    string    `json:"deck_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Stats sums up the study of a Deck, or of all the Decks of the caller, as
+// replayed from their Reviews. Dates are UTC days, such as 2024-01-31.
+type Stats struct {
+	DeckID            string            `json:"deck_id,omitempty"`
+	Cards             int               `json:"cards"`
+	Reviews           int               `json:"reviews"`
+	Maturity          Maturity          `json:"maturity"`
+	AverageEase       float64           `json:"average_ease"`
+	AverageDifficulty float64           `json:"average_difficulty"`
+	Retention         []RetentionBucket `json:"retention"`
+	Forecast          []DueDay          `json:"forecast"`
+	Streak            Streak            `json:"streak"`
+	Days              []StudyDay        `json:"days"`
+	Computed          time.Time         `json:"computed"`
+}
+
+// Maturity counts Cards by how well they are known: never reviewed, being
+// learnt (due again within a day), young or mature (due again in 21 days or
+// more).
+type Maturity struct {
+	New      int `json:"new"`
+	Learning int `json:"learning"`
+	Young    int `json:"young"`
+	Mature   int `json:"mature"`
+}
+
+// RetentionBucket gives the share of Reviews recalled (graded better than
+// again) among those of Cards first reviewed MinAgeDays to MaxAgeDays
+// before, without upper bound when MaxAgeDays is 0.
+type RetentionBucket struct {
+	MinAgeDays int     `json:"min_age_days"`
+	MaxAgeDays int     `json:"max_age_days,omitempty"`
+	Reviews    int     `json:"reviews"`
+	Recalled   int     `json:"recalled"`
+	Retention  float64 `json:"retention"`
+}
+
+// DueDay counts the Cards due on Date, overdue ones being due today.
+type DueDay struct {
+	Date string `json:"date"`
+	Due  int    `json:"due"`
+}
+
+// Streak counts the days in a row with Reviews: up to today, or yesterday
+// while today has none yet, and the longest run ever.
+type Streak struct {
+	Current int `json:"current"`
+	Longest int `json:"longest"`
+}
+
+// StudyDay counts the Reviews of Date and the time spent on them.
+type StudyDay struct {
+	Date       string `json:"date"`
+	Reviews    int    `json:"reviews"`
+	DurationMs int64  `json:"duration_ms"`
+}
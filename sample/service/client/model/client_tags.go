@@ -0,0 +1,17 @@
+package model
+
+// TagChange adds and removes tags. Applied to the Cards of a Deck, it
+// targets the Cards of CardIDs matching the Filter tag expression (all the
+// Cards when both are empty).
+type TagChange struct {
+	CardIDs []string `json:"card_ids,omitempty"`
+	Filter  string   `json:"filter,omitempty"`
+	Add     []string `json:"add,omitempty"`
+	Remove  []string `json:"remove,omitempty"`
+}
+
+// TagCount is a tag along with the number of Decks and Cards carrying it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
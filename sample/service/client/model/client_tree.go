@@ -0,0 +1,24 @@
+package model
+
+// DeckMove moves a Deck under Parent, among the Decks of its owner, or to
+// the top level when empty.
+type DeckMove struct {
+	Parent string `json:"parent"`
+}
+
+// DeckNode is a Deck of a tree of Decks. CardCount counts its own Cards,
+// TotalCardCount those of its whole subtree.
+type DeckNode struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name,omitempty"`
+	Path           string     `json:"path"`
+	CardCount      int        `json:"card_count"`
+	TotalCardCount int        `json:"total_card_count"`
+	Children       []DeckNode `json:"children,omitempty"`
+}
+
+// StudyCard is a Card to study, along with its Deck.
+type StudyCard struct {
+	DeckID string `json:"deck_id"`
+	Card   Card   `json:"card"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Webhook is a subscription delivering change events to URL.
+// Secret is only returned when the subscription is created.
+type Webhook struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types,omitempty"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookAttempt is a single try of a WebhookDelivery.
+type WebhookAttempt struct {
+	Time       time.Time     `json:"time"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// WebhookDelivery is the delivery of one event to one Webhook.
+// Status is one of pending, succeeded or dead.
+type WebhookDelivery struct {
+	ID          string           `json:"id"`
+	WebhookID   string           `json:"webhook_id"`
+	EventID     uint64           `json:"event_id"`
+	EventType   string           `json:"event_type"`
+	Status      string           `json:"status"`
+	Attempts    []WebhookAttempt `json:"attempts,omitempty"`
+	NextAttempt *time.Time       `json:"next_attempt,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
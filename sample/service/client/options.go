@@ -0,0 +1,81 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"golang.org/x/time/rate"
+)
+
+// Option customizes a Client.
+type Option func(*options)
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	breaker    *gobreaker.Settings
+	limiter    *rate.Limiter
+}
+
+func defaultOptions() options {
+	return options{
+		baseURL:    "http://localhost:8080",
+		httpClient: http.DefaultClient,
+		timeout:    10 * time.Second,
+		retries:    2,
+		backoff:    100 * time.Millisecond,
+	}
+}
+
+// WithBaseURL sets the address of the decksvc instance.
+// Defaults to http://localhost:8080.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) { o.baseURL = baseURL }
+}
+
+// WithHTTPClient sets the http.Client sending the requests.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) { o.httpClient = c }
+}
+
+// WithAuthToken sends the token as a bearer Authorization header.
+func WithAuthToken(token string) Option {
+	return func(o *options) { o.token = token }
+}
+
+// WithTimeout bounds the duration of every attempt of every call, on top of
+// any deadline of the caller context. Zero disables it. Defaults to 10s.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) { o.timeout = d }
+}
+
+// WithRetry retries calls up to retries times on network errors, 429 and
+// 5xx answers. POST calls are retried too, as every attempt carries the same
+// Idempotency-Key header. The delay before retry n is drawn uniformly
+// between 0 and backoff*2^(n-1) (full jitter).
+// Zero retries disables it. Defaults to 2 retries with a 100ms backoff.
+func WithRetry(retries int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = retries
+		o.backoff = backoff
+	}
+}
+
+// WithCircuitBreaker stops calling the instance once it keeps failing, as
+// decided by the given settings. Business errors (not found, already exists,
+// other 4xx answers) never count as failures.
+func WithCircuitBreaker(settings gobreaker.Settings) Option {
+	return func(o *options) { o.breaker = &settings }
+}
+
+// WithRateLimit delays calls so that at most r calls per second are sent,
+// with bursts of up to burst calls.
+func WithRateLimit(r rate.Limit, burst int) Option {
+	return func(o *options) { o.limiter = rate.NewLimiter(r, burst) }
+}
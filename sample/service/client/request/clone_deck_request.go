@@ -0,0 +1,11 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// CloneDeck /decks/{Deck_id}:clone POST request
+type CloneDeck struct {
+	DeckID string
+	Clone  clientModel.DeckClone
+}
@@ -0,0 +1,4 @@
+package request
+
+// CollectMedia /media/gc POST request (admin)
+type CollectMedia struct{}
@@ -0,0 +1,7 @@
+package request
+
+// DeleteMember /decks/{Deck_id}/members/{user} DELETE request
+type DeleteMember struct {
+	DeckID string
+	User   string
+}
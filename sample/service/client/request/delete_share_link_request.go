@@ -0,0 +1,7 @@
+package request
+
+// DeleteShareLink /decks/{Deck_id}/links/{token} DELETE request
+type DeleteShareLink struct {
+	DeckID string
+	Token  string
+}
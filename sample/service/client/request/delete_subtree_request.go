@@ -0,0 +1,7 @@
+package request
+
+// DeleteSubtree /decks/{Deck_id}?children={mode} DELETE request
+type DeleteSubtree struct {
+	DeckID   string
+	Children string
+}
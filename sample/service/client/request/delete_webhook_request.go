@@ -0,0 +1,6 @@
+package request
+
+// DeleteWebhook /webhooks/{id} DELETE request
+type DeleteWebhook struct {
+	ID string
+}
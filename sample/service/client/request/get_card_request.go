@@ -1,7 +1,9 @@
 package request
 
-// GetCard /decks/{Deck_id}/cards/{Card_id} GET request
+// GetCard /decks/{Deck_id}/cards/{Card_id} GET request, faces being
+// rendered as Render asks when set
 type GetCard struct {
 	DeckID string
 	CardID string
+	Render string
 }
@@ -1,6 +1,9 @@
 package request
 
-// GetCards /decks GET request
+// GetCards /decks GET request, Cards being filtered by the Tags expression
+// when set, and faces rendered as Render asks when set
 type GetCards struct {
 	DeckID string
+	Tags   string
+	Render string
 }
@@ -0,0 +1,6 @@
+package request
+
+// GetChildren /decks/{Deck_id}/children GET request
+type GetChildren struct {
+	DeckID string
+}
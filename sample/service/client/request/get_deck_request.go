@@ -1,6 +1,8 @@
 package request
 
-// GetDeck /decks GET request
+// GetDeck /decks GET request, faces being rendered as Render asks when set
+// (see richtext.RenderHTML)
 type GetDeck struct {
-	ID string
+	ID     string
+	Render string
 }
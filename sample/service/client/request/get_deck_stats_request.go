@@ -0,0 +1,7 @@
+package request
+
+// GetDeckStats /decks/{Deck_id}/stats GET request
+type GetDeckStats struct {
+	DeckID string
+	Days   int
+}
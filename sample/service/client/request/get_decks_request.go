@@ -0,0 +1,8 @@
+package request
+
+// GetDecks /decks GET request, Decks being filtered by the Tags expression
+// when set, and faces rendered as Render asks when set
+type GetDecks struct {
+	Tags   string
+	Render string
+}
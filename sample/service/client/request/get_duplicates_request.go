@@ -0,0 +1,8 @@
+package request
+
+// GetDuplicates /decks/{Deck_id}/duplicates GET request, /duplicates across
+// Decks when DeckID is empty
+type GetDuplicates struct {
+	DeckID    string
+	Threshold float64
+}
@@ -0,0 +1,6 @@
+package request
+
+// GetMembers /decks/{Deck_id}/members GET request
+type GetMembers struct {
+	DeckID string
+}
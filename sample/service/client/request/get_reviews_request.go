@@ -0,0 +1,6 @@
+package request
+
+// GetReviews /reviews?deck={Deck_id} GET request
+type GetReviews struct {
+	DeckID string
+}
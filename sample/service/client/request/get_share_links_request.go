@@ -0,0 +1,6 @@
+package request
+
+// GetShareLinks /decks/{Deck_id}/links GET request
+type GetShareLinks struct {
+	DeckID string
+}
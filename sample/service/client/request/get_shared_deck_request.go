@@ -0,0 +1,6 @@
+package request
+
+// GetSharedDeck /shared/{token} GET request
+type GetSharedDeck struct {
+	Token string
+}
@@ -0,0 +1,6 @@
+package request
+
+// GetStats /stats GET request
+type GetStats struct {
+	Days int
+}
@@ -0,0 +1,8 @@
+package request
+
+// GetStudyCards /decks/{Deck_id}/study GET request, Cards being filtered by
+// the Tags expression when set
+type GetStudyCards struct {
+	DeckID string
+	Tags   string
+}
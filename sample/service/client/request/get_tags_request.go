@@ -0,0 +1,7 @@
+package request
+
+// GetTags /tags?prefix={prefix}&limit={limit} GET request
+type GetTags struct {
+	Prefix string
+	Limit  int
+}
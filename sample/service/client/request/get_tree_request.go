@@ -0,0 +1,6 @@
+package request
+
+// GetTree /decks/{Deck_id}/tree GET request
+type GetTree struct {
+	DeckID string
+}
@@ -0,0 +1,6 @@
+package request
+
+// GetWebhookDeliveries /webhooks/{id}/deliveries GET request
+type GetWebhookDeliveries struct {
+	ID string
+}
@@ -0,0 +1,6 @@
+package request
+
+// GetWebhook /webhooks/{id} GET request
+type GetWebhook struct {
+	ID string
+}
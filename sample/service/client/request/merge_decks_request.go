@@ -0,0 +1,10 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// MergeDecks /decks:merge POST request
+type MergeDecks struct {
+	Merge clientModel.DeckMerge
+}
@@ -0,0 +1,12 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// MoveCard /decks/{Deck_id}/cards/{Card_id}:move POST request
+type MoveCard struct {
+	DeckID string
+	CardID string
+	Move   clientModel.CardMove
+}
@@ -0,0 +1,7 @@
+package request
+
+// MoveDeck /decks/{Deck_id}:move POST request
+type MoveDeck struct {
+	DeckID string
+	Parent string
+}
@@ -0,0 +1,10 @@
+package request
+
+// NewQuiz /decks/{Deck_id}/quiz GET request, Cards being drawn among those
+// matching the Tags expression when set
+type NewQuiz struct {
+	DeckID string
+	Type   string
+	Count  int
+	Tags   string
+}
@@ -0,0 +1,11 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// PostMember /decks/{Deck_id}/members POST request
+type PostMember struct {
+	DeckID string
+	Member clientModel.Member
+}
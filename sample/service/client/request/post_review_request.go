@@ -0,0 +1,10 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// PostReview /reviews POST request
+type PostReview struct {
+	Review clientModel.Review
+}
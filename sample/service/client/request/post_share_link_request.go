@@ -0,0 +1,6 @@
+package request
+
+// PostShareLink /decks/{Deck_id}/links POST request
+type PostShareLink struct {
+	DeckID string
+}
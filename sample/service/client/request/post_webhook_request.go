@@ -0,0 +1,10 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// PostWebhook /webhooks POST request
+type PostWebhook struct {
+	Webhook clientModel.Webhook
+}
@@ -6,6 +6,6 @@ import (
 
 // PutDeck /decks PUT request
 type PutDeck struct {
-	ID      string
+	ID   string
 	Deck clientModel.Deck
 }
@@ -0,0 +1,7 @@
+package request
+
+// RedeliverWebhook /webhooks/{id}/deliveries/{deliveryID}/redeliver POST request
+type RedeliverWebhook struct {
+	ID         string
+	DeliveryID string
+}
@@ -0,0 +1,7 @@
+package request
+
+// ReorderCards /decks/{Deck_id}/cards:reorder POST request
+type ReorderCards struct {
+	DeckID  string
+	CardIDs []string
+}
@@ -0,0 +1,11 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// ScoreQuiz /quiz/{Quiz_id}/answers POST request
+type ScoreQuiz struct {
+	QuizID  string
+	Attempt clientModel.QuizAttempt
+}
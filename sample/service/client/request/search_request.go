@@ -0,0 +1,8 @@
+package request
+
+// Search /search?q={query}&tags={expression}&limit={limit} GET request
+type Search struct {
+	Query string
+	Tags  string
+	Limit int
+}
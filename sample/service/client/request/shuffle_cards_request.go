@@ -0,0 +1,7 @@
+package request
+
+// ShuffleCards /decks/{Deck_id}/cards:shuffle POST request
+type ShuffleCards struct {
+	DeckID string
+	Seed   *int64
+}
@@ -0,0 +1,11 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// SplitDeck /decks/{Deck_id}:split POST request
+type SplitDeck struct {
+	DeckID string
+	Split  clientModel.DeckSplit
+}
@@ -0,0 +1,11 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// TagCards /decks/{Deck_id}/cards/tags POST request
+type TagCards struct {
+	DeckID string
+	Change clientModel.TagChange
+}
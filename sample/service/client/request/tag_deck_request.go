@@ -0,0 +1,11 @@
+package request
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// TagDeck /decks/{Deck_id}/tags POST request
+type TagDeck struct {
+	DeckID string
+	Change clientModel.TagChange
+}
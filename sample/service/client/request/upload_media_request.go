@@ -0,0 +1,9 @@
+package request
+
+import "io"
+
+// UploadMedia /media POST request, sent as a multipart file
+type UploadMedia struct {
+	Filename string
+	Content  io.Reader
+}
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// CloneDeck /decks/{Deck_id}:clone POST response
+type CloneDeck struct {
+	Deck clientModel.Deck `json:"deck,omitempty"`
+	Err  error            `json:"err,omitempty"`
+}
+
+func (r CloneDeck) error() error { return r.Err }
@@ -0,0 +1,9 @@
+package response
+
+// CollectMedia /media/gc POST response (admin), counting the removed blobs
+type CollectMedia struct {
+	Removed int   `json:"removed"`
+	Err     error `json:"err,omitempty"`
+}
+
+func (r CollectMedia) error() error { return r.Err }
@@ -0,0 +1,8 @@
+package response
+
+// DeleteMember /decks/{Deck_id}/members/{user} DELETE response
+type DeleteMember struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r DeleteMember) error() error { return r.Err }
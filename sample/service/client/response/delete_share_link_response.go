@@ -0,0 +1,8 @@
+package response
+
+// DeleteShareLink /decks/{Deck_id}/links/{token} DELETE response
+type DeleteShareLink struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r DeleteShareLink) error() error { return r.Err }
@@ -0,0 +1,8 @@
+package response
+
+// DeleteSubtree /decks/{Deck_id}?children={mode} DELETE response
+type DeleteSubtree struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r DeleteSubtree) error() error { return r.Err }
@@ -0,0 +1,8 @@
+package response
+
+// DeleteWebhook /webhooks/{id} DELETE response
+type DeleteWebhook struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r DeleteWebhook) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetChildren /decks/{Deck_id}/children GET response
+type GetChildren struct {
+	Decks []clientModel.Deck `json:"decks,omitempty"`
+	Err   error              `json:"err,omitempty"`
+}
+
+func (r GetChildren) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetDeckStats /decks/{Deck_id}/stats GET response
+type GetDeckStats struct {
+	Stats clientModel.Stats `json:"stats"`
+	Err   error             `json:"err,omitempty"`
+}
+
+func (r GetDeckStats) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetDuplicates /decks/{Deck_id}/duplicates GET response
+type GetDuplicates struct {
+	Groups []clientModel.DuplicateGroup `json:"groups,omitempty"`
+	Err    error                        `json:"err,omitempty"`
+}
+
+func (r GetDuplicates) error() error { return r.Err }
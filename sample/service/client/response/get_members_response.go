@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetMembers /decks/{Deck_id}/members GET response
+type GetMembers struct {
+	Members []clientModel.Member `json:"members,omitempty"`
+	Err     error                `json:"err,omitempty"`
+}
+
+func (r GetMembers) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetReviews /reviews?deck={Deck_id} GET response
+type GetReviews struct {
+	Reviews []clientModel.Review `json:"reviews,omitempty"`
+	Err     error                `json:"err,omitempty"`
+}
+
+func (r GetReviews) error() error { return r.Err }
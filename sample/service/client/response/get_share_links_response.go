@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetShareLinks /decks/{Deck_id}/links GET response
+type GetShareLinks struct {
+	Links []clientModel.ShareLink `json:"links,omitempty"`
+	Err   error                   `json:"err,omitempty"`
+}
+
+func (r GetShareLinks) error() error { return r.Err }
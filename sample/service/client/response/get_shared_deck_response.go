@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetSharedDeck /shared/{token} GET response
+type GetSharedDeck struct {
+	Deck clientModel.Deck `json:"deck,omitempty"`
+	Err  error            `json:"err,omitempty"`
+}
+
+func (r GetSharedDeck) error() error { return r.Err }
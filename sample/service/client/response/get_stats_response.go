@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetStats /stats GET response
+type GetStats struct {
+	Stats clientModel.Stats `json:"stats"`
+	Err   error             `json:"err,omitempty"`
+}
+
+func (r GetStats) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetStudyCards /decks/{Deck_id}/study GET response
+type GetStudyCards struct {
+	Cards []clientModel.StudyCard `json:"cards,omitempty"`
+	Err   error                   `json:"err,omitempty"`
+}
+
+func (r GetStudyCards) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetTags /tags GET response
+type GetTags struct {
+	Tags []clientModel.TagCount `json:"tags,omitempty"`
+	Err  error                  `json:"err,omitempty"`
+}
+
+func (r GetTags) error() error { return r.Err }
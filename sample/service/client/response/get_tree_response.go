@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetTree /decks/{Deck_id}/tree GET response
+type GetTree struct {
+	Tree clientModel.DeckNode `json:"tree"`
+	Err  error                `json:"err,omitempty"`
+}
+
+func (r GetTree) error() error { return r.Err }
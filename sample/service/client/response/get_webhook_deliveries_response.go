@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetWebhookDeliveries /webhooks/{id}/deliveries and /webhooks/dead-letters GET response
+type GetWebhookDeliveries struct {
+	Deliveries []clientModel.WebhookDelivery `json:"deliveries,omitempty"`
+	Err        error                         `json:"err,omitempty"`
+}
+
+func (r GetWebhookDeliveries) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetWebhook /webhooks/{id} GET response
+type GetWebhook struct {
+	Webhook clientModel.Webhook `json:"webhook,omitempty"`
+	Err     error               `json:"err,omitempty"`
+}
+
+func (r GetWebhook) error() error { return r.Err }
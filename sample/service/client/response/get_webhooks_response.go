@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// GetWebhooks /webhooks GET response
+type GetWebhooks struct {
+	Webhooks []clientModel.Webhook `json:"webhooks,omitempty"`
+	Err      error                 `json:"err,omitempty"`
+}
+
+func (r GetWebhooks) error() error { return r.Err }
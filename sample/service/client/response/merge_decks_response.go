@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// MergeDecks /decks:merge POST response
+type MergeDecks struct {
+	Deck clientModel.Deck `json:"deck,omitempty"`
+	Err  error            `json:"err,omitempty"`
+}
+
+func (r MergeDecks) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// MoveCard /decks/{Deck_id}/cards/{Card_id}:move POST response
+type MoveCard struct {
+	Cards []clientModel.Card `json:"cards,omitempty"`
+	Err   error              `json:"err,omitempty"`
+}
+
+func (r MoveCard) error() error { return r.Err }
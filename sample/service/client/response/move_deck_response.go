@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// MoveDeck /decks/{Deck_id}:move POST response
+type MoveDeck struct {
+	Deck clientModel.Deck `json:"deck,omitempty"`
+	Err  error            `json:"err,omitempty"`
+}
+
+func (r MoveDeck) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// NewQuiz /decks/{Deck_id}/quiz GET response
+type NewQuiz struct {
+	Quiz clientModel.Quiz `json:"quiz"`
+	Err  error            `json:"err,omitempty"`
+}
+
+func (r NewQuiz) error() error { return r.Err }
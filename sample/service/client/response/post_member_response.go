@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// PostMember /decks/{Deck_id}/members POST response
+type PostMember struct {
+	Member clientModel.Member `json:"member,omitempty"`
+	Err    error              `json:"err,omitempty"`
+}
+
+func (r PostMember) error() error { return r.Err }
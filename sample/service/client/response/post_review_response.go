@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// PostReview /reviews POST response
+type PostReview struct {
+	Review clientModel.Review `json:"review,omitempty"`
+	Err    error              `json:"err,omitempty"`
+}
+
+func (r PostReview) error() error { return r.Err }
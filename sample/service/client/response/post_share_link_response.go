@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// PostShareLink /decks/{Deck_id}/links POST response
+type PostShareLink struct {
+	Link clientModel.ShareLink `json:"link,omitempty"`
+	Err  error                 `json:"err,omitempty"`
+}
+
+func (r PostShareLink) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// PostWebhook /webhooks POST response
+type PostWebhook struct {
+	Webhook clientModel.Webhook `json:"webhook,omitempty"`
+	Err     error               `json:"err,omitempty"`
+}
+
+func (r PostWebhook) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// RedeliverWebhook /webhooks/{id}/deliveries/{deliveryID}/redeliver POST response
+type RedeliverWebhook struct {
+	Delivery clientModel.WebhookDelivery `json:"delivery,omitempty"`
+	Err      error                       `json:"err,omitempty"`
+}
+
+func (r RedeliverWebhook) error() error { return r.Err }
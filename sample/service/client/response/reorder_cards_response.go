@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// ReorderCards /decks/{Deck_id}/cards:reorder POST response
+type ReorderCards struct {
+	Cards []clientModel.Card `json:"cards,omitempty"`
+	Err   error              `json:"err,omitempty"`
+}
+
+func (r ReorderCards) error() error { return r.Err }
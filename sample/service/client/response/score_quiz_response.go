@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// ScoreQuiz /quiz/{Quiz_id}/answers POST response
+type ScoreQuiz struct {
+	Result clientModel.QuizResult `json:"result"`
+	Err    error                  `json:"err,omitempty"`
+}
+
+func (r ScoreQuiz) error() error { return r.Err }
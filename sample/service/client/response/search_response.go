@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// Search /search?q={query}&limit={limit} GET response
+type Search struct {
+	Hits []clientModel.SearchHit `json:"hits,omitempty"`
+	Err  error                   `json:"err,omitempty"`
+}
+
+func (r Search) error() error { return r.Err }
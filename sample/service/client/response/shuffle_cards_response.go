@@ -0,0 +1,15 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// ShuffleCards /decks/{Deck_id}/cards:shuffle POST response, with the seed
+// giving this order back
+type ShuffleCards struct {
+	Cards []clientModel.Card `json:"cards,omitempty"`
+	Seed  int64              `json:"seed"`
+	Err   error              `json:"err,omitempty"`
+}
+
+func (r ShuffleCards) error() error { return r.Err }
@@ -0,0 +1,15 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// SplitDeck /decks/{Deck_id}:split POST response, holding the Deck split
+// and the new one
+type SplitDeck struct {
+	Source clientModel.Deck `json:"source,omitempty"`
+	Deck   clientModel.Deck `json:"deck,omitempty"`
+	Err    error            `json:"err,omitempty"`
+}
+
+func (r SplitDeck) error() error { return r.Err }
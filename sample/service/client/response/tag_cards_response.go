@@ -0,0 +1,14 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// TagCards /decks/{Deck_id}/cards/tags POST response, holding the changed
+// Cards
+type TagCards struct {
+	Cards []clientModel.Card `json:"cards,omitempty"`
+	Err   error              `json:"err,omitempty"`
+}
+
+func (r TagCards) error() error { return r.Err }
@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// TagDeck /decks/{Deck_id}/tags POST response
+type TagDeck struct {
+	Deck clientModel.Deck `json:"deck,omitempty"`
+	Err  error            `json:"err,omitempty"`
+}
+
+func (r TagDeck) error() error { return r.Err }
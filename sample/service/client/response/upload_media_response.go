@@ -0,0 +1,13 @@
+package response
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// UploadMedia /media POST response
+type UploadMedia struct {
+	Media clientModel.Media `json:"media,omitempty"`
+	Err   error             `json:"err,omitempty"`
+}
+
+func (r UploadMedia) error() error { return r.Err }
@@ -0,0 +1,40 @@
+package eventsourcing
+
+import (
+	"time"
+
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+// EventType identifies the kind of change carried by an Event.
+type EventType string
+
+const (
+	// DeckCreated : a new Deck was stored
+	DeckCreated EventType = "DeckCreated"
+	// DeckRenamed : an existing Deck only changed its name
+	DeckRenamed EventType = "DeckRenamed"
+	// DeckReplaced : an existing Deck was overwritten as a whole
+	DeckReplaced EventType = "DeckReplaced"
+	// DeckDeleted : a Deck was removed
+	DeckDeleted EventType = "DeckDeleted"
+	// CardAdded : a Card was appended to a Deck
+	CardAdded EventType = "CardAdded"
+	// CardRemoved : a Card was removed from a Deck
+	CardRemoved EventType = "CardRemoved"
+)
+
+// Event is a single domain event of the append-only stream.
+// Seq is assigned by the EventStore and is strictly increasing.
+// Only the fields relevant to Type are set, Owner scopes DeckID.
+type Event struct {
+	Seq    uint64      `json:"seq"`
+	Type   EventType   `json:"type"`
+	Time   time.Time   `json:"time"`
+	Owner  string      `json:"owner,omitempty"`
+	DeckID string      `json:"deck_id"`
+	Deck   *model.Deck `json:"deck,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Card   *model.Card `json:"card,omitempty"`
+	CardID string      `json:"card_id,omitempty"`
+}
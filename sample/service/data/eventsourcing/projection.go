@@ -0,0 +1,131 @@
+package eventsourcing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+var errMissingPayload = errors.New("event payload is missing")
+
+// Projection rebuilds the current Deck state from the event stream.
+// State is kept in an in memory SampleRepository so reads never touch the
+// EventStore.
+type Projection struct {
+	repo data.SampleRepository
+	seq  uint64
+}
+
+// NewProjection returns an empty Projection.
+func NewProjection() *Projection {
+	return &Projection{repo: inmem.NewInmemRepository()}
+}
+
+// Seq returns the sequence number of the last applied event.
+func (p *Projection) Seq() uint64 {
+	return p.seq
+}
+
+// Apply folds the given events into the projection, in order.
+// Events already applied (by Seq) are ignored.
+func (p *Projection) Apply(events ...Event) error {
+	for _, e := range events {
+		if e.Seq <= p.seq {
+			continue
+		}
+		if err := Apply(context.Background(), p.repo, e); err != nil {
+			return fmt.Errorf("apply event %d (%s): %v", e.Seq, e.Type, err)
+		}
+		p.seq = e.Seq
+	}
+	return nil
+}
+
+// Check applies the events to a copy of the Decks they touch, so that
+// events the projection would refuse are caught before being stored.
+func (p *Projection) Check(events ...Event) error {
+	ctx := context.Background()
+	scratch := inmem.NewInmemRepository()
+	copied := map[[2]string]bool{}
+	for _, e := range events {
+		k := [2]string{e.Owner, e.DeckID}
+		if copied[k] {
+			continue
+		}
+		copied[k] = true
+		if d, err := p.repo.GetDeck(ctx, e.Owner, e.DeckID); err == nil {
+			d.Cards = append([]model.Card(nil), d.Cards...)
+			if err := scratch.PostDeck(ctx, e.Owner, d); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range events {
+		if err := Apply(ctx, scratch, e); err != nil {
+			return fmt.Errorf("apply event (%s): %v", e.Type, err)
+		}
+	}
+	return nil
+}
+
+// Snapshot captures the projection state.
+func (p *Projection) Snapshot() (Snapshot, error) {
+	ctx := context.Background()
+	decks, err := p.repo.GetAllDecks(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Snapshot{Seq: p.seq, Decks: decks}, nil
+}
+
+// Restore resets the projection to the given snapshot.
+func (p *Projection) Restore(s Snapshot) error {
+	ctx := context.Background()
+	repo := inmem.NewInmemRepository()
+	for _, d := range s.Decks {
+		if err := repo.PostDeck(ctx, d.Owner, d); err != nil {
+			return err
+		}
+	}
+	p.repo = repo
+	p.seq = s.Seq
+	return nil
+}
+
+// Apply replays a single event against any SampleRepository implementation.
+func Apply(ctx context.Context, repo data.SampleRepository, e Event) error {
+	switch e.Type {
+	case DeckCreated:
+		if e.Deck == nil {
+			return errMissingPayload
+		}
+		return repo.PostDeck(ctx, e.Owner, *e.Deck)
+	case DeckRenamed:
+		d, err := repo.GetDeck(ctx, e.Owner, e.DeckID)
+		if err != nil {
+			return err
+		}
+		d.Name = e.Name
+		return repo.PutDeck(ctx, e.Owner, e.DeckID, d)
+	case DeckReplaced:
+		if e.Deck == nil {
+			return errMissingPayload
+		}
+		return repo.PutDeck(ctx, e.Owner, e.DeckID, *e.Deck)
+	case DeckDeleted:
+		return repo.DeleteDeck(ctx, e.Owner, e.DeckID)
+	case CardAdded:
+		if e.Card == nil {
+			return errMissingPayload
+		}
+		return repo.PostCard(ctx, e.Owner, e.DeckID, *e.Card)
+	case CardRemoved:
+		return repo.DeleteCard(ctx, e.Owner, e.DeckID, e.CardID)
+	default:
+		return fmt.Errorf("unknown event type %q", e.Type)
+	}
+}
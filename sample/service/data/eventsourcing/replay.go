@@ -0,0 +1,22 @@
+package eventsourcing
+
+import (
+	"context"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+)
+
+// Replay applies every event of the store, in order, to the destination
+// repository. It returns the number of events replayed.
+func Replay(ctx context.Context, store EventStore, dst data.SampleRepository) (int, error) {
+	events, err := store.Load(0)
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range events {
+		if err := Apply(ctx, dst, e); err != nil {
+			return i, err
+		}
+	}
+	return len(events), nil
+}
@@ -0,0 +1,252 @@
+package eventsourcing
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+type repository struct {
+	mtx           sync.RWMutex
+	store         EventStore
+	snapshots     SnapshotStore
+	snapshotEvery uint64
+	projection    *Projection
+}
+
+// NewEventSourcedRepository returns a SampleRepository whose source of truth
+// is the given EventStore. Reads are served from a projection rebuilt at
+// startup from the latest snapshot (if snapshots is not nil) plus the events
+// appended after it. A new snapshot is saved every snapshotEvery events;
+// zero disables snapshotting.
+func NewEventSourcedRepository(store EventStore, snapshots SnapshotStore, snapshotEvery uint64) (data.SampleRepository, error) {
+	p := NewProjection()
+	if snapshots != nil {
+		snap, err := snapshots.Load()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.Restore(snap); err != nil {
+			return nil, err
+		}
+	}
+	events, err := store.Load(p.Seq())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Apply(events...); err != nil {
+		return nil, err
+	}
+	return &repository{
+		store:         store,
+		snapshots:     snapshots,
+		snapshotEvery: snapshotEvery,
+		projection:    p,
+	}, nil
+}
+
+// record checks the events against the projection, appends them to the
+// store at once then folds them into the projection, so that the store never
+// holds events the projection refuses. Callers must hold the write lock.
+func (s *repository) record(events ...Event) error {
+	now := time.Now().UTC()
+	for i := range events {
+		events[i].Time = now
+	}
+	if err := s.projection.Check(events...); err != nil {
+		return err
+	}
+	before := s.projection.Seq()
+	events, err := s.store.Append(events...)
+	if err != nil {
+		return err
+	}
+	if err := s.projection.Apply(events...); err != nil {
+		return err
+	}
+	if s.snapshots != nil && s.snapshotEvery > 0 && s.projection.Seq()/s.snapshotEvery != before/s.snapshotEvery {
+		if snap, err := s.projection.Snapshot(); err == nil {
+			// A failed snapshot only slows down the next startup, the events
+			// themselves are already stored.
+			s.snapshots.Save(snap)
+		}
+	}
+	return nil
+}
+
+func (s *repository) PostDeck(ctx context.Context, owner string, p model.Deck) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, err := s.projection.repo.GetDeck(ctx, owner, p.ID); err == nil {
+		return data.ErrAlreadyExists
+	}
+	p.Owner = owner
+	return s.record(Event{Owner: owner, Type: DeckCreated, DeckID: p.ID, Deck: &p})
+}
+
+func (s *repository) GetDeck(ctx context.Context, owner string, id string) (model.Deck, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.projection.repo.GetDeck(ctx, owner, id)
+}
+
+func (s *repository) PutDeck(ctx context.Context, owner string, id string, p model.Deck) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if id != p.ID {
+		return data.ErrInconsistentIDs
+	}
+	e, err := putEvent(ctx, s.projection.repo, owner, p)
+	if err != nil || e == nil {
+		return err
+	}
+	return s.record(*e)
+}
+
+// putEvent returns the event storing p as Deck p.ID of owner in view, nil
+// when nothing changes.
+func putEvent(ctx context.Context, view deckReader, owner string, p model.Deck) (*Event, error) {
+	p.Owner = owner
+	current, err := view.GetDeck(ctx, owner, p.ID)
+	switch {
+	case err == data.ErrNotFound:
+		return &Event{Owner: owner, Type: DeckCreated, DeckID: p.ID, Deck: &p}, nil
+	case err != nil:
+		return nil, err
+	case sameContent(current, p):
+		if current.Name == p.Name {
+			return nil, nil // nothing changed, keep the stream free of no-op events
+		}
+		return &Event{Owner: owner, Type: DeckRenamed, DeckID: p.ID, Name: p.Name}, nil
+	default:
+		return &Event{Owner: owner, Type: DeckReplaced, DeckID: p.ID, Deck: &p}, nil
+	}
+}
+
+// deckReader reads Decks from the projection, or an Overlay of it.
+type deckReader interface {
+	GetDeck(ctx context.Context, owner string, id string) (model.Deck, error)
+}
+
+func (s *repository) GetDecks(ctx context.Context, owner string) ([]model.Deck, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.projection.repo.GetDecks(ctx, owner)
+}
+
+func (s *repository) GetAllDecks(ctx context.Context) ([]model.Deck, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.projection.repo.GetAllDecks(ctx)
+}
+
+func (s *repository) DeleteDeck(ctx context.Context, owner string, id string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, err := s.projection.repo.GetDeck(ctx, owner, id); err != nil {
+		return err
+	}
+	return s.record(Event{Owner: owner, Type: DeckDeleted, DeckID: id})
+}
+
+func (s *repository) GetCards(ctx context.Context, owner string, DeckID string) ([]model.Card, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.projection.repo.GetCards(ctx, owner, DeckID)
+}
+
+func (s *repository) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (model.Card, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.projection.repo.GetCard(ctx, owner, DeckID, CardID)
+}
+
+func (s *repository) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, err := s.projection.repo.GetDeck(ctx, owner, DeckID); err != nil {
+		return err
+	}
+	if _, err := s.projection.repo.GetCard(ctx, owner, DeckID, a.ID); err == nil {
+		return data.ErrAlreadyExists
+	}
+	return s.record(Event{Owner: owner, Type: CardAdded, DeckID: DeckID, Card: &a})
+}
+
+func (s *repository) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, err := s.projection.repo.GetCard(ctx, owner, DeckID, CardID); err != nil {
+		return err
+	}
+	return s.record(Event{Owner: owner, Type: CardRemoved, DeckID: DeckID, CardID: CardID})
+}
+
+func (s *repository) Apply(ctx context.Context, batch []data.Write) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	o := data.NewOverlay(s.projection.repo)
+	var events []Event
+	for _, w := range batch {
+		// Events are computed against the Decks the previous Writes leave.
+		var e *Event
+		var err error
+		switch w.Kind {
+		case data.WritePostDeck:
+			p := w.Deck
+			p.Owner = w.Owner
+			e = &Event{Owner: w.Owner, Type: DeckCreated, DeckID: w.ID, Deck: &p}
+		case data.WritePutDeck:
+			if w.Deck.ID == w.ID {
+				e, err = putEvent(ctx, o, w.Owner, w.Deck)
+			}
+		case data.WriteDeleteDeck:
+			e = &Event{Owner: w.Owner, Type: DeckDeleted, DeckID: w.ID}
+		}
+		if err != nil {
+			return err
+		}
+		if err := o.Stage(ctx, w); err != nil {
+			return err
+		}
+		if e != nil {
+			events = append(events, *e)
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	return s.record(events...)
+}
+
+// sameContent tells whether Decks a and b only differ by their names. Every
+// field counts, those added later included: empty and nil lists are the only
+// differences ignored.
+func sameContent(a, b model.Deck) bool {
+	a.Name = b.Name
+	return reflect.DeepEqual(normalized(a), normalized(b))
+}
+
+func normalized(d model.Deck) model.Deck {
+	if len(d.Tags) == 0 {
+		d.Tags = nil
+	}
+	if len(d.Cards) == 0 {
+		d.Cards = nil
+	}
+	cards := make([]model.Card, len(d.Cards))
+	for i, c := range d.Cards {
+		if len(c.Tags) == 0 {
+			c.Tags = nil
+		}
+		cards[i] = c
+	}
+	if d.Cards != nil {
+		d.Cards = cards
+	}
+	return d
+}
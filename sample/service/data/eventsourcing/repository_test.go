@@ -0,0 +1,181 @@
+package eventsourcing
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+// write stores a few Decks and Cards of alice and bob through repo.
+func write(t *testing.T, repo data.SampleRepository) {
+	t.Helper()
+	ctx := context.Background()
+	for _, step := range []error{
+		repo.PostDeck(ctx, "alice", model.Deck{ID: "d1", Name: "Verbs", Cards: []model.Card{{ID: "c1", First: "to be", Second: "être"}}}),
+		repo.PostDeck(ctx, "alice", model.Deck{ID: "d2", Name: "Nouns", Tags: []string{"fr"}}),
+		repo.PostDeck(ctx, "bob", model.Deck{ID: "d1", Name: "Bob's"}),
+		repo.PostCard(ctx, "alice", "d1", model.Card{ID: "c2", First: "to have", Second: "avoir"}),
+		repo.PutDeck(ctx, "alice", "d2", model.Deck{ID: "d2", Name: "Names", Tags: []string{"fr"}}),
+		repo.DeleteCard(ctx, "alice", "d1", "c1"),
+		repo.DeleteDeck(ctx, "bob", "d1"),
+	} {
+		if step != nil {
+			t.Fatal(step)
+		}
+	}
+}
+
+func allDecks(t *testing.T, repo data.SampleRepository) []model.Deck {
+	t.Helper()
+	decks, err := repo.GetAllDecks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(decks, func(i, j int) bool { return decks[i].Owner+decks[i].ID < decks[j].Owner+decks[j].ID })
+	return decks
+}
+
+func TestReplay(t *testing.T) {
+	store := NewMemoryStore()
+	repo, err := NewEventSourcedRepository(store, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(t, repo)
+
+	dst := inmem.NewInmemRepository()
+	n, err := Replay(context.Background(), store, dst)
+	if err != nil || n != 7 {
+		t.Fatalf("replayed %d events (%v), want 7", n, err)
+	}
+	if got, want := allDecks(t, dst), allDecks(t, repo); !reflect.DeepEqual(got, want) {
+		t.Fatalf("replayed %+v, want %+v", got, want)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(filepath.Join(dir, "events.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshots := NewFileSnapshotStore(filepath.Join(dir, "snapshot.json"))
+	repo, err := NewEventSourcedRepository(store, snapshots, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(t, repo)
+	snap, err := snapshots.Load()
+	if err != nil || snap.Seq != 6 {
+		t.Fatalf("snapshot at %d (%v), want 6", snap.Seq, err)
+	}
+
+	// Restarting restores the snapshot then replays the events after it.
+	store, err = NewFileStore(filepath.Join(dir, "events.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted, err := NewEventSourcedRepository(store, snapshots, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := allDecks(t, restarted), allDecks(t, repo); !reflect.DeepEqual(got, want) {
+		t.Fatalf("restored %+v, want %+v", got, want)
+	}
+	if err := restarted.PostDeck(context.Background(), "bob", model.Deck{ID: "d3"}); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := store.Load(7); len(events) != 1 || events[0].Seq != 8 {
+		t.Fatalf("events after restart %+v, want seq 8", events)
+	}
+}
+
+func TestPutEvent(t *testing.T) {
+	ctx := context.Background()
+	view := inmem.NewInmemRepository()
+	current := model.Deck{ID: "d", Owner: "alice", Name: "Verbs", Tags: []string{"fr"},
+		Cards: []model.Card{{ID: "c", First: "to be", Second: "être"}}}
+	view.PostDeck(ctx, "alice", current)
+
+	for name, tc := range map[string]struct {
+		deck model.Deck
+		want EventType // empty for no event
+	}{
+		"unchanged": {current, ""},
+		"empty lists": {model.Deck{ID: "d", Name: "Verbs", Tags: []string{"fr"},
+			Cards: []model.Card{{ID: "c", First: "to be", Second: "être", Tags: []string{}}}}, ""},
+		"renamed": {model.Deck{ID: "d", Name: "Verbes", Tags: []string{"fr"}, Cards: current.Cards}, DeckRenamed},
+		"missing": {model.Deck{ID: "other"}, DeckCreated},
+	} {
+		e, err := putEvent(ctx, view, "alice", tc.deck)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		switch {
+		case tc.want == "" && e != nil:
+			t.Errorf("%s: %s event, want none", name, e.Type)
+		case tc.want != "" && (e == nil || e.Type != tc.want):
+			t.Errorf("%s: %+v, want a %s event", name, e, tc.want)
+		}
+	}
+
+	// Changing any other field of the Deck or of its Cards replaces it.
+	for i := 0; i < reflect.TypeOf(current).NumField(); i++ {
+		f := reflect.TypeOf(current).Field(i)
+		if f.Name == "ID" || f.Name == "Owner" || f.Name == "Name" {
+			continue
+		}
+		p := current
+		change(reflect.ValueOf(&p).Elem().Field(i))
+		if e, _ := putEvent(ctx, view, "alice", p); e == nil || e.Type != DeckReplaced {
+			t.Errorf("changing Deck.%s: %+v, want a %s event", f.Name, e, DeckReplaced)
+		}
+	}
+	for i := 0; i < reflect.TypeOf(model.Card{}).NumField(); i++ {
+		p := current
+		p.Cards = []model.Card{current.Cards[0]}
+		change(reflect.ValueOf(&p.Cards[0]).Elem().Field(i))
+		if e, _ := putEvent(ctx, view, "alice", p); e == nil || e.Type != DeckReplaced {
+			t.Errorf("changing Card.%s: %+v, want a %s event", reflect.TypeOf(model.Card{}).Field(i).Name, e, DeckReplaced)
+		}
+	}
+}
+
+// change sets v to a value differing from the fields of TestPutEvent.
+func change(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(v.String() + "-changed")
+	case reflect.Slice:
+		v.Set(reflect.Append(reflect.MakeSlice(v.Type(), 0, 1), reflect.New(v.Type().Elem()).Elem()))
+	default:
+		panic("unexpected field kind " + v.Kind().String())
+	}
+}
+
+func TestRefusedEventsAreNotStored(t *testing.T) {
+	store := NewMemoryStore()
+	repo, err := NewEventSourcedRepository(store, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := repo.(*repository)
+	if err := s.record(Event{Owner: "alice", Type: CardAdded, DeckID: "missing", Card: &model.Card{ID: "c"}}); err == nil {
+		t.Fatal("recorded a card of a missing deck")
+	}
+	if events, _ := store.Load(0); len(events) != 0 {
+		t.Fatalf("stored %+v", events)
+	}
+	if err := repo.PostDeck(context.Background(), "alice", model.Deck{ID: "d"}); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := store.Load(0); len(events) != 1 || s.projection.Seq() != 1 {
+		t.Fatalf("stored %+v, projection at %d", events, s.projection.Seq())
+	}
+}
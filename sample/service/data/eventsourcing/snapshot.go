@@ -0,0 +1,71 @@
+package eventsourcing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+// Snapshot is the projected Deck state as of event Seq.
+type Snapshot struct {
+	Seq   uint64       `json:"seq"`
+	Decks []model.Deck `json:"decks"`
+}
+
+// SnapshotStore keeps the latest Snapshot so startup only replays the tail
+// of the event stream.
+type SnapshotStore interface {
+	Save(s Snapshot) error
+	// Load returns the latest snapshot, or an empty one if none was saved.
+	Load() (Snapshot, error)
+}
+
+type fileSnapshotStore struct {
+	path string
+}
+
+// NewFileSnapshotStore returns a SnapshotStore keeping the latest snapshot as
+// a JSON document at path.
+func NewFileSnapshotStore(path string) SnapshotStore {
+	return &fileSnapshotStore{path: path}
+}
+
+func (s *fileSnapshotStore) Save(snap Snapshot) error {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	// Write then rename so a crash never leaves a truncated snapshot behind.
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileSnapshotStore) Load() (Snapshot, error) {
+	b, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return Snapshot{}, nil
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return Snapshot{}, err
+	}
+	return snap, nil
+}
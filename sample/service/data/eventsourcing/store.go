@@ -0,0 +1,131 @@
+package eventsourcing
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// EventStore is an append-only stream of domain events.
+type EventStore interface {
+	// Append assigns sequence numbers to the events and persists them.
+	Append(events ...Event) ([]Event, error)
+	// Load returns every event whose Seq is strictly greater than after.
+	Load(after uint64) ([]Event, error)
+}
+
+type memoryStore struct {
+	mtx    sync.RWMutex
+	events []Event
+}
+
+// NewMemoryStore In Memory EventStore Constructor
+func NewMemoryStore() EventStore {
+	return &memoryStore{}
+}
+
+func (s *memoryStore) Append(events ...Event) ([]Event, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	next := uint64(len(s.events))
+	for i := range events {
+		next++
+		events[i].Seq = next
+	}
+	s.events = append(s.events, events...)
+	return events, nil
+}
+
+func (s *memoryStore) Load(after uint64) ([]Event, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if after >= uint64(len(s.events)) {
+		return []Event{}, nil
+	}
+	res := make([]Event, len(s.events)-int(after))
+	copy(res, s.events[after:])
+	return res, nil
+}
+
+type fileStore struct {
+	mtx  sync.Mutex
+	path string
+	last uint64
+}
+
+// NewFileStore returns an EventStore writing one JSON encoded event per line
+// to the file at path. The file is created if it does not exist yet.
+func NewFileStore(path string) (EventStore, error) {
+	s := &fileStore{path: path}
+	events, err := s.Load(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		s.last = events[len(events)-1].Seq
+	}
+	return s, nil
+}
+
+func (s *fileStore) Append(events ...Event) ([]Event, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	// The whole batch is encoded first and written at once, so that a failed
+	// Append leaves neither part of it on disk nor its sequence numbers used.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	appended := make([]Event, len(events))
+	next := s.last
+	for i, e := range events {
+		next++
+		e.Seq = next
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+		appended[i] = e
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Truncate(info.Size())
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Truncate(info.Size())
+		return nil, err
+	}
+	s.last = next
+	copy(events, appended)
+	return events, nil
+}
+
+func (s *fileStore) Load(after uint64) ([]Event, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return []Event{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events := []Event{}
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			return nil, err
+		}
+		if e.Seq > after {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
@@ -1,71 +1,176 @@
 package inmemory
 
 import (
+	"context"
+	"sync"
+
 	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
 	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
 )
 
+// repository guards a store, so that it may be used concurrently.
 type repository struct {
-	m map[string]model.Deck
+	mtx   sync.RWMutex
+	store *store
+}
+
+type store struct {
+	m map[string]map[string]model.Deck // owner -> Deck ID -> Deck
 }
 
-// NewInmemRepository In Memory Service Constructor
+// NewInmemRepository In Memory Service Constructor. The repository is safe
+// for concurrent use.
 func NewInmemRepository() data.SampleRepository {
 	return &repository{
-		m: map[string]model.Deck{},
+		store: &store{
+			m: map[string]map[string]model.Deck{},
+		},
 	}
 }
 
-func (s *repository) PostDeck(p model.Deck) error {
-	if _, ok := s.m[p.ID]; ok {
+func (r *repository) PostDeck(ctx context.Context, owner string, p model.Deck) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.store.PostDeck(ctx, owner, p)
+}
+
+func (r *repository) GetDeck(ctx context.Context, owner string, id string) (model.Deck, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.store.GetDeck(ctx, owner, id)
+}
+
+func (r *repository) PutDeck(ctx context.Context, owner string, id string, p model.Deck) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.store.PutDeck(ctx, owner, id, p)
+}
+
+func (r *repository) GetDecks(ctx context.Context, owner string) ([]model.Deck, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.store.GetDecks(ctx, owner)
+}
+
+func (r *repository) GetAllDecks(ctx context.Context) ([]model.Deck, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.store.GetAllDecks(ctx)
+}
+
+func (r *repository) DeleteDeck(ctx context.Context, owner string, id string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.store.DeleteDeck(ctx, owner, id)
+}
+
+func (r *repository) GetCards(ctx context.Context, owner string, DeckID string) ([]model.Card, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.store.GetCards(ctx, owner, DeckID)
+}
+
+func (r *repository) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (model.Card, error) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.store.GetCard(ctx, owner, DeckID, CardID)
+}
+
+func (r *repository) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.store.PostCard(ctx, owner, DeckID, a)
+}
+
+func (r *repository) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.store.DeleteCard(ctx, owner, DeckID, CardID)
+}
+
+func (r *repository) Apply(ctx context.Context, batch []data.Write) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.store.Apply(ctx, batch)
+}
+
+// decks returns the Decks of owner, creating its namespace if asked to.
+func (s *store) decks(owner string, create bool) map[string]model.Deck {
+	decks, ok := s.m[owner]
+	if !ok && create {
+		decks = map[string]model.Deck{}
+		s.m[owner] = decks
+	}
+	return decks
+}
+
+func (s *store) PostDeck(ctx context.Context, owner string, p model.Deck) error {
+	decks := s.decks(owner, true)
+	if _, ok := decks[p.ID]; ok {
 		return data.ErrAlreadyExists // POST = create, don't overwrite
 	}
-	s.m[p.ID] = p
+	p.Owner = owner
+	decks[p.ID] = p
 	return nil
 }
 
-func (s *repository) GetDeck(id string) (model.Deck, error) {
-	p, ok := s.m[id]
+func (s *store) GetDeck(ctx context.Context, owner string, id string) (model.Deck, error) {
+	p, ok := s.decks(owner, false)[id]
 	if !ok {
 		return model.Deck{}, data.ErrNotFound
 	}
 	return p, nil
 }
 
-func (s *repository) PutDeck(id string, p model.Deck) error {
+func (s *store) PutDeck(ctx context.Context, owner string, id string, p model.Deck) error {
 	if id != p.ID {
 		return data.ErrInconsistentIDs
 	}
-	s.m[id] = p // PUT = create or update
+	p.Owner = owner
+	s.decks(owner, true)[id] = p // PUT = create or update
 	return nil
 }
 
-func (s *repository) GetDecks() ([]model.Deck, error) {
+func (s *store) GetDecks(ctx context.Context, owner string) ([]model.Deck, error) {
 	decks := []model.Deck{}
-    for _, val := range s.m {
-        decks = append(decks, val)
-    }
+	for _, val := range s.decks(owner, false) {
+		decks = append(decks, val)
+	}
 	return decks, nil
 }
 
-func (s *repository) DeleteDeck(id string) error {
-	if _, ok := s.m[id]; !ok {
+func (s *store) GetAllDecks(ctx context.Context) ([]model.Deck, error) {
+	decks := []model.Deck{}
+	for _, owned := range s.m {
+		for _, val := range owned {
+			decks = append(decks, val)
+		}
+	}
+	return decks, nil
+}
+
+func (s *store) DeleteDeck(ctx context.Context, owner string, id string) error {
+	decks := s.decks(owner, false)
+	if _, ok := decks[id]; !ok {
 		return data.ErrNotFound
 	}
-	delete(s.m, id)
+	delete(decks, id)
+	if len(decks) == 0 {
+		delete(s.m, owner)
+	}
 	return nil
 }
 
-func (s *repository) GetCards(DeckID string) ([]model.Card, error) {
-	p, ok := s.m[DeckID]
+func (s *store) GetCards(ctx context.Context, owner string, DeckID string) ([]model.Card, error) {
+	p, ok := s.decks(owner, false)[DeckID]
 	if !ok {
 		return []model.Card{}, data.ErrNotFound
 	}
 	return p.Cards, nil
 }
 
-func (s *repository) GetCard(DeckID string, CardID string) (model.Card, error) {
-	p, ok := s.m[DeckID]
+func (s *store) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (model.Card, error) {
+	p, ok := s.decks(owner, false)[DeckID]
 	if !ok {
 		return model.Card{}, data.ErrNotFound
 	}
@@ -77,8 +182,9 @@ func (s *repository) GetCard(DeckID string, CardID string) (model.Card, error) {
 	return model.Card{}, data.ErrNotFound
 }
 
-func (s *repository) PostCard(DeckID string, a model.Card) error {
-	p, ok := s.m[DeckID]
+func (s *store) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) error {
+	decks := s.decks(owner, false)
+	p, ok := decks[DeckID]
 	if !ok {
 		return data.ErrNotFound
 	}
@@ -88,12 +194,13 @@ func (s *repository) PostCard(DeckID string, a model.Card) error {
 		}
 	}
 	p.Cards = append(p.Cards, a)
-	s.m[DeckID] = p
+	decks[DeckID] = p
 	return nil
 }
 
-func (s *repository) DeleteCard(DeckID string, CardID string) error {
-	p, ok := s.m[DeckID]
+func (s *store) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) error {
+	decks := s.decks(owner, false)
+	p, ok := decks[DeckID]
 	if !ok {
 		return data.ErrNotFound
 	}
@@ -108,6 +215,27 @@ func (s *repository) DeleteCard(DeckID string, CardID string) error {
 		return data.ErrNotFound
 	}
 	p.Cards = newCards
-	s.m[DeckID] = p
+	decks[DeckID] = p
+	return nil
+}
+
+func (s *store) Apply(ctx context.Context, batch []data.Write) error {
+	o := data.NewOverlay(s)
+	for _, w := range batch {
+		if err := o.Stage(ctx, w); err != nil {
+			return err
+		}
+	}
+	// Staged Writes cannot fail any more.
+	for _, w := range batch {
+		switch w.Kind {
+		case data.WritePostDeck:
+			s.PostDeck(ctx, w.Owner, w.Deck)
+		case data.WritePutDeck:
+			s.PutDeck(ctx, w.Owner, w.ID, w.Deck)
+		case data.WriteDeleteDeck:
+			s.DeleteDeck(ctx, w.Owner, w.ID)
+		}
+	}
 	return nil
 }
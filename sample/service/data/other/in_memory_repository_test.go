@@ -0,0 +1,45 @@
+package inmemory
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+// TestConcurrentUse is meant for go test -race: readers such as the sharing
+// ACL or the media collector run alongside writes.
+func TestConcurrentUse(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInmemRepository()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		owner := "user" + strconv.Itoa(w)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				id := strconv.Itoa(i)
+				repo.PostDeck(ctx, owner, model.Deck{ID: id})
+				repo.PostCard(ctx, owner, id, model.Card{ID: "c"})
+				repo.PutDeck(ctx, owner, id, model.Deck{ID: id, Name: "renamed"})
+				repo.Apply(ctx, []data.Write{{Kind: data.WriteDeleteDeck, Owner: owner, ID: id}})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				repo.GetDeck(ctx, owner, strconv.Itoa(i))
+				repo.GetDecks(ctx, owner)
+				repo.GetAllDecks(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+	if decks, _ := repo.GetAllDecks(ctx); len(decks) != 0 {
+		t.Fatalf("%d decks left", len(decks))
+	}
+}
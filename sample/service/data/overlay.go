@@ -0,0 +1,90 @@
+package data
+
+import (
+	"context"
+
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+// Overlay stages Writes over the Decks of a SampleRepository without storing
+// them, so that a batch can be checked as a whole before any of its Writes
+// is.
+type Overlay struct {
+	repo   SampleRepository
+	staged map[deckKey]*model.Deck // nil once deleted
+}
+
+type deckKey struct {
+	owner string
+	id    string
+}
+
+// NewOverlay returns an Overlay of repo with no Writes staged.
+func NewOverlay(repo SampleRepository) *Overlay {
+	return &Overlay{
+		repo:   repo,
+		staged: map[deckKey]*model.Deck{},
+	}
+}
+
+// GetDeck returns the Deck as the staged Writes leave it.
+func (o *Overlay) GetDeck(ctx context.Context, owner string, id string) (model.Deck, error) {
+	if d, ok := o.staged[deckKey{owner, id}]; ok {
+		if d == nil {
+			return model.Deck{}, ErrNotFound
+		}
+		return *d, nil
+	}
+	return o.repo.GetDeck(ctx, owner, id)
+}
+
+// GetDecks returns the Decks of owner as the staged Writes leave them.
+func (o *Overlay) GetDecks(ctx context.Context, owner string) ([]model.Deck, error) {
+	stored, err := o.repo.GetDecks(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	decks := []model.Deck{}
+	for _, d := range stored {
+		if _, ok := o.staged[deckKey{owner, d.ID}]; !ok {
+			decks = append(decks, d)
+		}
+	}
+	for k, d := range o.staged {
+		if k.owner == owner && d != nil {
+			decks = append(decks, *d)
+		}
+	}
+	return decks, nil
+}
+
+// Stage checks w against the Decks as the staged Writes leave them, the way
+// the method of its kind would, then stages it.
+func (o *Overlay) Stage(ctx context.Context, w Write) error {
+	_, err := o.GetDeck(ctx, w.Owner, w.ID)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	exists := err == nil
+	k := deckKey{w.Owner, w.ID}
+	switch w.Kind {
+	case WritePostDeck, WritePutDeck:
+		if w.Deck.ID != w.ID {
+			return ErrInconsistentIDs
+		}
+		if exists && w.Kind == WritePostDeck {
+			return ErrAlreadyExists
+		}
+		d := w.Deck
+		d.Owner = w.Owner
+		o.staged[k] = &d
+	case WriteDeleteDeck:
+		if !exists {
+			return ErrNotFound
+		}
+		o.staged[k] = nil
+	default:
+		return ErrInvalidWrite
+	}
+	return nil
+}
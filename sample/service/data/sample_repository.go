@@ -1,22 +1,47 @@
 package data
 
 import (
+	"context"
 	"errors"
 
 	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
 )
 
 // SampleRepository is a simple CRUD interface for user Decks.
+// Decks are scoped by owner: IDs are unique per owner and every method but
+// GetAllDecks only sees the Decks of the given owner. Stored Decks carry
+// their owner in Deck.Owner. Implementations are safe for concurrent use:
+// services such as sharing and media read them alongside SampleService.
 type SampleRepository interface {
-	PostDeck(p model.Deck) error
-	GetDeck(id string) (model.Deck, error)
-	PutDeck(id string, p model.Deck) error
-	GetDecks() ([]model.Deck, error)
-	DeleteDeck(id string) error
-	GetCards(DeckID string) ([]model.Card, error)
-	GetCard(DeckID string, CardID string) (model.Card, error)
-	PostCard(DeckID string, a model.Card) error
-	DeleteCard(DeckID string, CardID string) error
+	PostDeck(ctx context.Context, owner string, p model.Deck) error
+	GetDeck(ctx context.Context, owner string, id string) (model.Deck, error)
+	PutDeck(ctx context.Context, owner string, id string, p model.Deck) error
+	GetDecks(ctx context.Context, owner string) ([]model.Deck, error)
+	GetAllDecks(ctx context.Context) ([]model.Deck, error)
+	DeleteDeck(ctx context.Context, owner string, id string) error
+	GetCards(ctx context.Context, owner string, DeckID string) ([]model.Card, error)
+	GetCard(ctx context.Context, owner string, DeckID string, CardID string) (model.Card, error)
+	PostCard(ctx context.Context, owner string, DeckID string, a model.Card) error
+	DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) error
+	// Apply stores the Writes of batch, in order, as a whole: when one of
+	// them fails, none is stored and its error is returned.
+	Apply(ctx context.Context, batch []Write) error
+}
+
+// Write kinds, each checked as the method of the same name.
+const (
+	WritePostDeck   = "PostDeck"
+	WritePutDeck    = "PutDeck"
+	WriteDeleteDeck = "DeleteDeck"
+)
+
+// Write is a single change of a batch given to Apply: Deck ID of Owner is
+// created or replaced by Deck, or deleted.
+type Write struct {
+	Kind  string
+	Owner string
+	ID    string
+	Deck  model.Deck
 }
 
 var (
@@ -26,4 +51,6 @@ var (
 	ErrAlreadyExists = errors.New("already exists")
 	// ErrNotFound : Item not found
 	ErrNotFound = errors.New("not found")
+	// ErrInvalidWrite : Write of an unknown kind
+	ErrInvalidWrite = errors.New("invalid write")
 )
@@ -1,47 +1,364 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	eventsourcing "github.com/TangiFavennec/go-service-sample/sample/service/data/eventsourcing"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/pb"
 	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	auth "github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	deckops "github.com/TangiFavennec/go-service-sample/sample/service/server/deckops"
+	duplicates "github.com/TangiFavennec/go-service-sample/sample/service/server/duplicates"
 	endpoints "github.com/TangiFavennec/go-service-sample/sample/service/server/endpoints"
+	feed "github.com/TangiFavennec/go-service-sample/sample/service/server/feed"
+	graphql "github.com/TangiFavennec/go-service-sample/sample/service/server/graphql"
+	idempotency "github.com/TangiFavennec/go-service-sample/sample/service/server/idempotency"
+	logging "github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+	media "github.com/TangiFavennec/go-service-sample/sample/service/server/media"
 	middlewares "github.com/TangiFavennec/go-service-sample/sample/service/server/middlewares"
+	ordering "github.com/TangiFavennec/go-service-sample/sample/service/server/ordering"
+	quiz "github.com/TangiFavennec/go-service-sample/sample/service/server/quiz"
+	ratelimit "github.com/TangiFavennec/go-service-sample/sample/service/server/ratelimit"
+	requestid "github.com/TangiFavennec/go-service-sample/sample/service/server/requestid"
+	reviews "github.com/TangiFavennec/go-service-sample/sample/service/server/reviews"
+	search "github.com/TangiFavennec/go-service-sample/sample/service/server/search"
+	sharing "github.com/TangiFavennec/go-service-sample/sample/service/server/sharing"
+	stats "github.com/TangiFavennec/go-service-sample/sample/service/server/stats"
+	tagging "github.com/TangiFavennec/go-service-sample/sample/service/server/tagging"
+	tracing "github.com/TangiFavennec/go-service-sample/sample/service/server/tracing"
+	tree "github.com/TangiFavennec/go-service-sample/sample/service/server/tree"
+	webhooks "github.com/TangiFavennec/go-service-sample/sample/service/server/webhooks"
 
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/gorilla/mux"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 )
 
 func main() {
 	var (
-		httpAddr = flag.String("http.addr", ":8080", "HTTP listen cards")
+		httpAddr      = flag.String("http.addr", ":8080", "HTTP listen cards")
+		grpcAddr      = flag.String("grpc.addr", ":8082", "gRPC listen address")
+		repoKind      = flag.String("repo", "memory", "storage backend: memory or events")
+		eventsFile    = flag.String("events.file", "events.log", "event stream file (events backend)")
+		snapshotFile  = flag.String("events.snapshot", "", "snapshot file, empty disables snapshots (events backend)")
+		snapshotEvery = flag.Uint64("events.snapshot-every", 100, "events between two snapshots (events backend)")
+		feedBuffer    = flag.Int("feed.buffer", 1024, "change feed events kept for Last-Event-ID resumption")
+		hookWorkers   = flag.Int("webhooks.workers", 4, "concurrent webhook deliveries")
+		hookAttempts  = flag.Int("webhooks.max-attempts", webhooks.DefaultPolicy.MaxAttempts, "webhook delivery attempts before dead-lettering")
+		adminAddr     = flag.String("admin.addr", ":8081", "admin listen address serving /metrics")
+		traceExporter = flag.String("trace.exporter", "none", "span exporter: none, stdout or file")
+		traceFile     = flag.String("trace.file", "traces.json", "span file (file exporter)")
+		logLevel      = flag.String("log.level", "info", "minimum log level: debug, info, warn or error")
+		logFormat     = flag.String("log.format", "logfmt", "log output format: logfmt or json")
+		logSampleGets = flag.Int("log.sample-gets", 1, "log one successful GET out of this many")
+		apiKeysFile   = flag.String("auth.api-keys", "", "API key file, one \"subject sha256-hex\" pair per line")
+		jwksFile      = flag.String("auth.jwks", "", "JWKS file holding the keys verifying JWT bearer tokens")
+		jwtIssuer     = flag.String("auth.jwt-issuer", "", "required iss claim of JWT bearer tokens")
+		jwtAudience   = flag.String("auth.jwt-audience", "", "required aud claim of JWT bearer tokens")
+		rateRoutes    = flag.String("ratelimit.routes", "", "comma separated \"[METHOD ]PREFIX=RATE:BURST\" rate limits per client, empty disables rate limiting")
+		rateProxies   = flag.String("ratelimit.trusted-proxies", "", "comma separated addresses or networks of the proxies whose X-Forwarded-For tells anonymous clients apart")
+		quotaDecks    = flag.Int("quota.decks", 0, "maximum decks per user, 0 for unlimited")
+		quotaCards    = flag.Int("quota.cards", 0, "maximum cards per deck, 0 for unlimited")
+		quotaPayload  = flag.Int("quota.payload", 0, "maximum JSON size in bytes of a stored deck or card, 0 for unlimited")
+		dupCheck      = flag.String("duplicates.check", duplicates.CheckOff, "check of posted cards against their deck: off, warn or reject")
+		dupThreshold  = flag.Float64("duplicates.threshold", duplicates.DefaultThreshold, "similarity from which cards are near-duplicates")
+		idemTTL       = flag.Duration("idempotency.ttl", 24*time.Hour, "retention of the responses to Idempotency-Key requests, 0 disables them")
+		idemMaxBody   = flag.Int64("idempotency.max-body", 1<<20, "maximum size in bytes of the body of Idempotency-Key requests, media uploads aside")
+		mediaDir      = flag.String("media.dir", "media", "directory of the uploaded images and audio")
+		mediaMaxSize  = flag.Int64("media.max-size", 10<<20, "maximum size in bytes of an uploaded image or audio file")
+		mediaGC       = flag.Duration("media.gc-interval", time.Hour, "period of the removal of media no card references, 0 disables it")
+		statsTTL      = flag.Duration("stats.cache-ttl", 10*time.Minute, "how long learning statistics are cached while decks are not reviewed, 0 disables the cache")
 	)
 	flag.Parse()
 
 	var logger log.Logger
 	{
-		logger = log.NewLogfmtLogger(os.Stderr)
+		var err error
+		logger, err = logging.NewLogger(os.Stderr, *logFormat, *logLevel)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 		logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 		logger = log.With(logger, "caller", log.DefaultCaller)
 	}
 
+	var tracer trace.Tracer
+	{
+		exporter, err := tracing.NewExporter(*traceExporter, *traceFile)
+		if err != nil {
+			logger.Log("exit", err)
+			os.Exit(1)
+		}
+		tp := tracing.NewTracerProvider("decksvc", exporter)
+		defer tp.Shutdown(context.Background())
+		tracing.Register(tp)
+		tracer = tp.Tracer(tracing.InstrumentationName)
+	}
+
+	var authenticator *auth.Authenticator
+	if *apiKeysFile != "" || *jwksFile != "" {
+		authenticator = &auth.Authenticator{}
+		if *apiKeysFile != "" {
+			keys, err := auth.LoadAPIKeys(*apiKeysFile)
+			if err != nil {
+				logger.Log("exit", err)
+				os.Exit(1)
+			}
+			authenticator.APIKeys = keys
+		}
+		if *jwksFile != "" {
+			validator, err := auth.LoadJWKS(*jwksFile, *jwtIssuer, *jwtAudience)
+			if err != nil {
+				logger.Log("exit", err)
+				os.Exit(1)
+			}
+			authenticator.JWT = validator
+		}
+	} else {
+		level.Warn(logger).Log("msg", "authentication disabled, set -auth.api-keys or -auth.jwks")
+	}
+
+	switch *dupCheck {
+	case duplicates.CheckOff, duplicates.CheckWarn, duplicates.CheckReject:
+	default:
+		logger.Log("exit", fmt.Sprintf("unknown duplicates check %q", *dupCheck))
+		os.Exit(1)
+	}
+
+	var limiter *ratelimit.Limiter
+	var proxies ratelimit.Proxies
+	if *rateRoutes != "" {
+		routes, err := ratelimit.ParseRoutes(*rateRoutes)
+		if err != nil {
+			logger.Log("exit", err)
+			os.Exit(1)
+		}
+		limiter = ratelimit.NewLimiter(routes)
+		if proxies, err = ratelimit.ParseProxies(*rateProxies); err != nil {
+			logger.Log("exit", err)
+			os.Exit(1)
+		}
+	}
+
+	var repo data.SampleRepository
+	{
+		switch *repoKind {
+		case "memory":
+			repo = inmem.NewInmemRepository()
+		case "events":
+			store, err := eventsourcing.NewFileStore(*eventsFile)
+			if err != nil {
+				logger.Log("exit", err)
+				os.Exit(1)
+			}
+			var snapshots eventsourcing.SnapshotStore
+			if *snapshotFile != "" {
+				snapshots = eventsourcing.NewFileSnapshotStore(*snapshotFile)
+			}
+			repo, err = eventsourcing.NewEventSourcedRepository(store, snapshots, *snapshotEvery)
+			if err != nil {
+				logger.Log("exit", err)
+				os.Exit(1)
+			}
+		default:
+			logger.Log("exit", fmt.Sprintf("unknown repo %q", *repoKind))
+			os.Exit(1)
+		}
+	}
+
+	repo = middlewares.RepositoryInstrumentingMiddleware(
+		kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: "sample",
+			Subsystem: "deck_service",
+			Name:      "decks",
+			Help:      "Number of stored decks.",
+		}, []string{}),
+		kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: "sample",
+			Subsystem: "deck_service",
+			Name:      "cards",
+			Help:      "Number of stored cards.",
+		}, []string{}),
+	)(repo)
+	index := search.NewIndex()
+	repo = middlewares.RepositoryIndexingMiddleware(index)(repo)
+	repo = middlewares.RepositoryTreeMiddleware()(repo)
+	repo = tracing.NewRepository(tracer, repo)
+	repo = middlewares.RepositoryLoggingMiddleware(log.With(logger, "component", "repository"))(repo)
+
+	broker := feed.NewBroker(*feedBuffer)
+
+	var dispatcher *webhooks.Dispatcher
+	{
+		policy := webhooks.DefaultPolicy
+		policy.MaxAttempts = *hookAttempts
+		dispatcher = webhooks.NewDispatcher(webhooks.NewClient(), policy, log.With(logger, "component", "webhooks"))
+		dispatcher.Start(broker, *hookWorkers)
+	}
+
+	acl := sharing.NewACL(repo)
+
+	var ms media.Service
+	{
+		store, err := media.NewStore(*mediaDir, *mediaMaxSize)
+		if err != nil {
+			logger.Log("exit", err)
+			os.Exit(1)
+		}
+		ms = media.NewService(store, repo)
+		if *mediaGC > 0 {
+			go func() {
+				for range time.Tick(*mediaGC) {
+					removed, err := ms.Collect(context.Background())
+					if err != nil {
+						level.Error(logger).Log("component", "media", "msg", "garbage collection failed", "err", err)
+						continue
+					}
+					logger.Log("component", "media", "msg", "garbage collection", "removed", removed)
+				}
+			}()
+		}
+	}
+
 	var s server.SampleService
 	{
-		s = server.NewdefaultService()
-		s = middlewares.LoggingMiddleware(logger)(s)
+		s = server.NewService(repo)
+		s = middlewares.QuotaMiddleware(middlewares.Quotas{
+			MaxDecks:       *quotaDecks,
+			MaxCards:       *quotaCards,
+			MaxPayloadSize: *quotaPayload,
+		}, repo)(s)
+		s = middlewares.DuplicateCheckMiddleware(*dupCheck, *dupThreshold)(s)
+		s = middlewares.AuthorizationMiddleware(acl)(s)
+		s = middlewares.EventsMiddleware(broker, acl)(s)
+		s = middlewares.InstrumentingMiddleware(
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "sample",
+				Subsystem: "deck_service",
+				Name:      "requests_total",
+				Help:      "Number of requests received.",
+			}, []string{"method", "error"}),
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "sample",
+				Subsystem: "deck_service",
+				Name:      "errors_total",
+				Help:      "Number of failed requests by error kind.",
+			}, []string{"method", "kind"}),
+			kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+				Namespace: "sample",
+				Subsystem: "deck_service",
+				Name:      "request_duration_seconds",
+				Help:      "Duration of requests in seconds.",
+				Buckets:   stdprometheus.DefBuckets,
+			}, []string{"method"}),
+		)(s)
+		s = middlewares.TracingMiddleware(tracer)(s)
+		s = middlewares.LoggingMiddleware(logger, logging.NewSampler(*logSampleGets))(s)
 	}
 
 	var h http.Handler
 	{
-		h = endpoints.MakeHTTPHandler(s, log.With(logger, "component", "HTTP"))
+		f := feed.MakeHTTPHandler(broker, log.With(logger, "component", "feed"))
+		g, err := graphql.MakeHTTPHandler(s, log.With(logger, "component", "GraphQL"))
+		if err != nil {
+			logger.Log("exit", err)
+			os.Exit(1)
+		}
+		m := http.NewServeMux()
+		m.Handle("/graphql", g)
+		sc := stats.NewCache(*statsTTL)
+		rs := stats.ReviewsMiddleware(sc)(reviews.NewInmemService(s))
+		m.Handle("/reviews", reviews.MakeHTTPHandler(rs, log.With(logger, "component", "reviews")))
+		sth := stats.MakeHTTPHandler(stats.NewService(s, rs, sc), log.With(logger, "component", "stats"))
+		m.Handle("/stats", sth)
+		m.Handle("/search", search.MakeHTTPHandler(search.NewService(index, acl), log.With(logger, "component", "search")))
+		m.Handle("/events", f)
+		m.Handle("/events/", f)
+		w := webhooks.MakeHTTPHandler(dispatcher, log.With(logger, "component", "webhooks"))
+		m.Handle("/webhooks", w)
+		m.Handle("/webhooks/", w)
+		mh := media.MakeHTTPHandler(ms, log.With(logger, "component", "media"))
+		m.Handle("/media", mh)
+		m.Handle("/media/", mh)
+		sh := sharing.MakeHTTPHandler(acl, log.With(logger, "component", "sharing"))
+		d := mux.NewRouter()
+		d.PathPrefix("/decks/{id}/members").Handler(sh)
+		d.PathPrefix("/decks/{id}/links").Handler(sh)
+		dh := duplicates.MakeHTTPHandler(duplicates.NewService(s, *dupThreshold), log.With(logger, "component", "duplicates"))
+		d.Path("/decks/{id}/duplicates").Handler(dh)
+		d.Path("/duplicates").Handler(dh)
+		th := tagging.MakeHTTPHandler(tagging.NewService(s), log.With(logger, "component", "tagging"))
+		d.Methods("POST").Path("/decks/{id}/tags").Handler(th)
+		d.Methods("POST").Path("/decks/{id}/cards/tags").Handler(th)
+		d.Path("/tags").Handler(th)
+		trh := tree.MakeHTTPHandler(tree.NewService(s), log.With(logger, "component", "tree"))
+		d.Methods("POST").Path("/decks/{id}:move").Handler(trh)
+		d.Methods("GET").Path("/decks/{id}/children").Handler(trh)
+		d.Methods("GET").Path("/decks/{id}/tree").Handler(trh)
+		d.Methods("GET").Path("/decks/{id}/study").Handler(trh)
+		d.Methods("DELETE").Path("/decks/{id}").Queries("children", "{children}").Handler(trh)
+		oh := deckops.MakeHTTPHandler(deckops.NewService(s), log.With(logger, "component", "deckops"))
+		d.Methods("POST").Path("/decks/{id}:clone").Handler(oh)
+		d.Methods("POST").Path("/decks:merge").Handler(oh)
+		d.Methods("POST").Path("/decks/{id}:split").Handler(oh)
+		orh := ordering.MakeHTTPHandler(ordering.NewService(s), log.With(logger, "component", "ordering"))
+		d.Methods("POST").Path("/decks/{id}/cards/{cardID}:move").Handler(orh)
+		d.Methods("POST").Path("/decks/{id}/cards:reorder").Handler(orh)
+		d.Methods("POST").Path("/decks/{id}/cards:shuffle").Handler(orh)
+		qh := quiz.MakeHTTPHandler(quiz.NewInmemService(s), log.With(logger, "component", "quiz"))
+		d.Methods("GET").Path("/decks/{id}/quiz").Handler(qh)
+		d.Methods("GET").Path("/decks/{id}/stats").Handler(sth)
+		m.Handle("/quiz/", qh)
+		d.PathPrefix("/").Handler(endpoints.MakeHTTPHandler(s, log.With(logger, "component", "HTTP")))
+		m.Handle("/", d)
+		h = m
+		if *idemTTL > 0 {
+			h = idempotency.HTTPHandler(idempotency.NewStore(*idemTTL), func(r *http.Request) int64 {
+				if r.URL.Path == "/media" {
+					return *mediaMaxSize + 64<<10 // room for the multipart headers
+				}
+				return *idemMaxBody
+			}, h)
+		}
+		var shared http.Handler = sh
+		if authenticator != nil {
+			h = auth.HTTPHandler(authenticator, h)
+		}
+		// Rate limits apply before authentication, to failed attempts too.
+		if limiter != nil {
+			h = ratelimit.HTTPHandler(limiter, authenticator, proxies, h)
+			shared = ratelimit.HTTPHandler(limiter, nil, proxies, shared)
+		}
+		// Share links are public.
+		public := http.NewServeMux()
+		public.Handle("/shared/", shared)
+		public.Handle("/", h)
+		h = public
+		h = instrumentHandler(h)
+		h = logging.AccessLog(log.With(logger, "component", "access"), logging.NewSampler(*logSampleGets), h)
+		h = requestid.HTTPHandler(h)
 	}
 
 	errs := make(chan error)
 	go func() {
-		c := make(chan os.Signal)
+		c := make(chan os.Signal, 1)
 		signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
 		errs <- fmt.Errorf("%s", <-c)
 	}()
@@ -51,5 +368,68 @@ func main() {
 		errs <- http.ListenAndServe(*httpAddr, h)
 	}()
 
+	go func() {
+		admin := http.NewServeMux()
+		admin.Handle("/metrics", promhttp.Handler())
+		admin.Handle("/media/gc", media.MakeAdminHandler(ms, log.With(logger, "component", "media")))
+		logger.Log("transport", "HTTP", "admin", true, "addr", *adminAddr)
+		errs <- http.ListenAndServe(*adminAddr, admin)
+	}()
+
+	go func() {
+		grpcListener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			errs <- err
+			return
+		}
+		var interceptors []grpc.UnaryServerInterceptor
+		if limiter != nil {
+			interceptors = append(interceptors, ratelimit.UnaryServerInterceptor(limiter, authenticator, endpoints.GRPCRoute))
+		}
+		if authenticator != nil {
+			interceptors = append(interceptors, auth.UnaryServerInterceptor(authenticator))
+		}
+		baseServer := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+		pb.RegisterSampleServer(baseServer, endpoints.MakeGRPCServer(s, log.With(logger, "component", "gRPC")))
+		logger.Log("transport", "gRPC", "addr", *grpcAddr)
+		errs <- baseServer.Serve(grpcListener)
+	}()
+
 	logger.Log("exit", <-errs)
 }
+
+// instrumentHandler records HTTP-level metrics (status codes, in-flight
+// requests, durations and response sizes) around h.
+func instrumentHandler(h http.Handler) http.Handler {
+	inFlight := promauto.NewGauge(stdprometheus.GaugeOpts{
+		Namespace: "sample",
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests being served.",
+	})
+	requests := promauto.NewCounterVec(stdprometheus.CounterOpts{
+		Namespace: "sample",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by status code and method.",
+	}, []string{"code", "method"})
+	duration := promauto.NewHistogramVec(stdprometheus.HistogramOpts{
+		Namespace: "sample",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests in seconds.",
+		Buckets:   stdprometheus.DefBuckets,
+	}, []string{"code", "method"})
+	sizes := promauto.NewHistogramVec(stdprometheus.HistogramOpts{
+		Namespace: "sample",
+		Subsystem: "http",
+		Name:      "response_size_bytes",
+		Help:      "Size of HTTP responses in bytes.",
+		Buckets:   stdprometheus.ExponentialBuckets(100, 4, 8),
+	}, []string{})
+
+	return promhttp.InstrumentHandlerInFlight(inFlight,
+		promhttp.InstrumentHandlerCounter(requests,
+			promhttp.InstrumentHandlerDuration(duration,
+				promhttp.InstrumentHandlerResponseSize(sizes, h))))
+}
@@ -6,4 +6,6 @@ type Card struct {
 	ID     string
 	First  string
 	Second string
+	Tags   []string
+	Format string
 }
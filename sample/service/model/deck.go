@@ -1,9 +1,12 @@
 package model
 
 // Deck represents a single user Deck.
-// ID should be globally unique.
+// ID should be unique per Owner.
 type Deck struct {
-	ID        string    
-	Name      string    
-	Cards []Card 
-}
\ No newline at end of file
+	ID     string
+	Owner  string
+	Name   string
+	Cards  []Card
+	Tags   []string
+	Parent string
+}
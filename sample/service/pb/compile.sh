@@ -0,0 +1,11 @@
+#!/usr/bin/env sh
+
+# Install protoc (https://github.com/protocolbuffers/protobuf/releases) and the
+# Go plugins, then run this script from its directory:
+#
+#   go get google.golang.org/protobuf/cmd/protoc-gen-go
+#   go get google.golang.org/grpc/cmd/protoc-gen-go-grpc
+
+protoc sample.proto \
+	--go_out=. --go_opt=paths=source_relative \
+	--go-grpc_out=. --go-grpc_opt=paths=source_relative
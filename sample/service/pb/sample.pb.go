@@ -0,0 +1,1140 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: sample.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Card is a field of a user Deck.
+// format tells how first and second are written: plain (the default),
+// markdown or html.
+type Card struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	First         string                 `protobuf:"bytes,2,opt,name=first,proto3" json:"first,omitempty"`
+	Second        string                 `protobuf:"bytes,3,opt,name=second,proto3" json:"second,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Format        string                 `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Card) Reset() {
+	*x = Card{}
+	mi := &file_sample_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Card) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Card) ProtoMessage() {}
+
+func (x *Card) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Card.ProtoReflect.Descriptor instead.
+func (*Card) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Card) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Card) GetFirst() string {
+	if x != nil {
+		return x.First
+	}
+	return ""
+}
+
+func (x *Card) GetSecond() string {
+	if x != nil {
+		return x.Second
+	}
+	return ""
+}
+
+func (x *Card) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Card) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// Deck represents a single user Deck.
+type Deck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Cards         []*Card                `protobuf:"bytes,3,rep,name=cards,proto3" json:"cards,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Parent        string                 `protobuf:"bytes,5,opt,name=parent,proto3" json:"parent,omitempty"`
+	Owner         string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Deck) Reset() {
+	*x = Deck{}
+	mi := &file_sample_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Deck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deck) ProtoMessage() {}
+
+func (x *Deck) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deck.ProtoReflect.Descriptor instead.
+func (*Deck) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Deck) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Deck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Deck) GetCards() []*Card {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+func (x *Deck) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Deck) GetParent() string {
+	if x != nil {
+		return x.Parent
+	}
+	return ""
+}
+
+func (x *Deck) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+type PostDeckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deck          *Deck                  `protobuf:"bytes,1,opt,name=deck,proto3" json:"deck,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostDeckRequest) Reset() {
+	*x = PostDeckRequest{}
+	mi := &file_sample_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostDeckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostDeckRequest) ProtoMessage() {}
+
+func (x *PostDeckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostDeckRequest.ProtoReflect.Descriptor instead.
+func (*PostDeckRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PostDeckRequest) GetDeck() *Deck {
+	if x != nil {
+		return x.Deck
+	}
+	return nil
+}
+
+type PostDeckReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostDeckReply) Reset() {
+	*x = PostDeckReply{}
+	mi := &file_sample_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostDeckReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostDeckReply) ProtoMessage() {}
+
+func (x *PostDeckReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostDeckReply.ProtoReflect.Descriptor instead.
+func (*PostDeckReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{3}
+}
+
+type GetDeckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeckRequest) Reset() {
+	*x = GetDeckRequest{}
+	mi := &file_sample_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeckRequest) ProtoMessage() {}
+
+func (x *GetDeckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeckRequest.ProtoReflect.Descriptor instead.
+func (*GetDeckRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetDeckRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetDeckReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deck          *Deck                  `protobuf:"bytes,1,opt,name=deck,proto3" json:"deck,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeckReply) Reset() {
+	*x = GetDeckReply{}
+	mi := &file_sample_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeckReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeckReply) ProtoMessage() {}
+
+func (x *GetDeckReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeckReply.ProtoReflect.Descriptor instead.
+func (*GetDeckReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetDeckReply) GetDeck() *Deck {
+	if x != nil {
+		return x.Deck
+	}
+	return nil
+}
+
+type PutDeckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Deck          *Deck                  `protobuf:"bytes,2,opt,name=deck,proto3" json:"deck,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutDeckRequest) Reset() {
+	*x = PutDeckRequest{}
+	mi := &file_sample_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutDeckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutDeckRequest) ProtoMessage() {}
+
+func (x *PutDeckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutDeckRequest.ProtoReflect.Descriptor instead.
+func (*PutDeckRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PutDeckRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PutDeckRequest) GetDeck() *Deck {
+	if x != nil {
+		return x.Deck
+	}
+	return nil
+}
+
+type PutDeckReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutDeckReply) Reset() {
+	*x = PutDeckReply{}
+	mi := &file_sample_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutDeckReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutDeckReply) ProtoMessage() {}
+
+func (x *PutDeckReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutDeckReply.ProtoReflect.Descriptor instead.
+func (*PutDeckReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{7}
+}
+
+// tags filters the Decks, as the tags query parameter of the HTTP API.
+type GetDecksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          string                 `protobuf:"bytes,1,opt,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDecksRequest) Reset() {
+	*x = GetDecksRequest{}
+	mi := &file_sample_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDecksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDecksRequest) ProtoMessage() {}
+
+func (x *GetDecksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDecksRequest.ProtoReflect.Descriptor instead.
+func (*GetDecksRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetDecksRequest) GetTags() string {
+	if x != nil {
+		return x.Tags
+	}
+	return ""
+}
+
+type GetDecksReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Decks         []*Deck                `protobuf:"bytes,1,rep,name=decks,proto3" json:"decks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDecksReply) Reset() {
+	*x = GetDecksReply{}
+	mi := &file_sample_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDecksReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDecksReply) ProtoMessage() {}
+
+func (x *GetDecksReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDecksReply.ProtoReflect.Descriptor instead.
+func (*GetDecksReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetDecksReply) GetDecks() []*Deck {
+	if x != nil {
+		return x.Decks
+	}
+	return nil
+}
+
+type DeleteDeckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeckRequest) Reset() {
+	*x = DeleteDeckRequest{}
+	mi := &file_sample_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeckRequest) ProtoMessage() {}
+
+func (x *DeleteDeckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeckRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDeckRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteDeckRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteDeckReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDeckReply) Reset() {
+	*x = DeleteDeckReply{}
+	mi := &file_sample_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDeckReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDeckReply) ProtoMessage() {}
+
+func (x *DeleteDeckReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDeckReply.ProtoReflect.Descriptor instead.
+func (*DeleteDeckReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{11}
+}
+
+// tags filters the Cards, as the tags query parameter of the HTTP API.
+type GetCardsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeckId        string                 `protobuf:"bytes,1,opt,name=deck_id,json=deckId,proto3" json:"deck_id,omitempty"`
+	Tags          string                 `protobuf:"bytes,2,opt,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCardsRequest) Reset() {
+	*x = GetCardsRequest{}
+	mi := &file_sample_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCardsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCardsRequest) ProtoMessage() {}
+
+func (x *GetCardsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCardsRequest.ProtoReflect.Descriptor instead.
+func (*GetCardsRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetCardsRequest) GetDeckId() string {
+	if x != nil {
+		return x.DeckId
+	}
+	return ""
+}
+
+func (x *GetCardsRequest) GetTags() string {
+	if x != nil {
+		return x.Tags
+	}
+	return ""
+}
+
+type GetCardsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cards         []*Card                `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCardsReply) Reset() {
+	*x = GetCardsReply{}
+	mi := &file_sample_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCardsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCardsReply) ProtoMessage() {}
+
+func (x *GetCardsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCardsReply.ProtoReflect.Descriptor instead.
+func (*GetCardsReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetCardsReply) GetCards() []*Card {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+type GetCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeckId        string                 `protobuf:"bytes,1,opt,name=deck_id,json=deckId,proto3" json:"deck_id,omitempty"`
+	CardId        string                 `protobuf:"bytes,2,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCardRequest) Reset() {
+	*x = GetCardRequest{}
+	mi := &file_sample_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCardRequest) ProtoMessage() {}
+
+func (x *GetCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCardRequest.ProtoReflect.Descriptor instead.
+func (*GetCardRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCardRequest) GetDeckId() string {
+	if x != nil {
+		return x.DeckId
+	}
+	return ""
+}
+
+func (x *GetCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+type GetCardReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Card          *Card                  `protobuf:"bytes,1,opt,name=card,proto3" json:"card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCardReply) Reset() {
+	*x = GetCardReply{}
+	mi := &file_sample_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCardReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCardReply) ProtoMessage() {}
+
+func (x *GetCardReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCardReply.ProtoReflect.Descriptor instead.
+func (*GetCardReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetCardReply) GetCard() *Card {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+type PostCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeckId        string                 `protobuf:"bytes,1,opt,name=deck_id,json=deckId,proto3" json:"deck_id,omitempty"`
+	Card          *Card                  `protobuf:"bytes,2,opt,name=card,proto3" json:"card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostCardRequest) Reset() {
+	*x = PostCardRequest{}
+	mi := &file_sample_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostCardRequest) ProtoMessage() {}
+
+func (x *PostCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostCardRequest.ProtoReflect.Descriptor instead.
+func (*PostCardRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PostCardRequest) GetDeckId() string {
+	if x != nil {
+		return x.DeckId
+	}
+	return ""
+}
+
+func (x *PostCardRequest) GetCard() *Card {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+type PostCardReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostCardReply) Reset() {
+	*x = PostCardReply{}
+	mi := &file_sample_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostCardReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostCardReply) ProtoMessage() {}
+
+func (x *PostCardReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostCardReply.ProtoReflect.Descriptor instead.
+func (*PostCardReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{17}
+}
+
+type DeleteCardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeckId        string                 `protobuf:"bytes,1,opt,name=deck_id,json=deckId,proto3" json:"deck_id,omitempty"`
+	CardId        string                 `protobuf:"bytes,2,opt,name=card_id,json=cardId,proto3" json:"card_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCardRequest) Reset() {
+	*x = DeleteCardRequest{}
+	mi := &file_sample_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCardRequest) ProtoMessage() {}
+
+func (x *DeleteCardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCardRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCardRequest) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteCardRequest) GetDeckId() string {
+	if x != nil {
+		return x.DeckId
+	}
+	return ""
+}
+
+func (x *DeleteCardRequest) GetCardId() string {
+	if x != nil {
+		return x.CardId
+	}
+	return ""
+}
+
+type DeleteCardReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCardReply) Reset() {
+	*x = DeleteCardReply{}
+	mi := &file_sample_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCardReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCardReply) ProtoMessage() {}
+
+func (x *DeleteCardReply) ProtoReflect() protoreflect.Message {
+	mi := &file_sample_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCardReply.ProtoReflect.Descriptor instead.
+func (*DeleteCardReply) Descriptor() ([]byte, []int) {
+	return file_sample_proto_rawDescGZIP(), []int{19}
+}
+
+var File_sample_proto protoreflect.FileDescriptor
+
+const file_sample_proto_rawDesc = "" +
+	"\n" +
+	"\fsample.proto\x12\x02pb\"p\n" +
+	"\x04Card\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05first\x18\x02 \x01(\tR\x05first\x12\x16\n" +
+	"\x06second\x18\x03 \x01(\tR\x06second\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06format\x18\x05 \x01(\tR\x06format\"\x8c\x01\n" +
+	"\x04Deck\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
+	"\x05cards\x18\x03 \x03(\v2\b.pb.CardR\x05cards\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06parent\x18\x05 \x01(\tR\x06parent\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\"/\n" +
+	"\x0fPostDeckRequest\x12\x1c\n" +
+	"\x04deck\x18\x01 \x01(\v2\b.pb.DeckR\x04deck\"\x0f\n" +
+	"\rPostDeckReply\" \n" +
+	"\x0eGetDeckRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\",\n" +
+	"\fGetDeckReply\x12\x1c\n" +
+	"\x04deck\x18\x01 \x01(\v2\b.pb.DeckR\x04deck\">\n" +
+	"\x0ePutDeckRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\x04deck\x18\x02 \x01(\v2\b.pb.DeckR\x04deck\"\x0e\n" +
+	"\fPutDeckReply\"%\n" +
+	"\x0fGetDecksRequest\x12\x12\n" +
+	"\x04tags\x18\x01 \x01(\tR\x04tags\"/\n" +
+	"\rGetDecksReply\x12\x1e\n" +
+	"\x05decks\x18\x01 \x03(\v2\b.pb.DeckR\x05decks\"#\n" +
+	"\x11DeleteDeckRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x11\n" +
+	"\x0fDeleteDeckReply\">\n" +
+	"\x0fGetCardsRequest\x12\x17\n" +
+	"\adeck_id\x18\x01 \x01(\tR\x06deckId\x12\x12\n" +
+	"\x04tags\x18\x02 \x01(\tR\x04tags\"/\n" +
+	"\rGetCardsReply\x12\x1e\n" +
+	"\x05cards\x18\x01 \x03(\v2\b.pb.CardR\x05cards\"B\n" +
+	"\x0eGetCardRequest\x12\x17\n" +
+	"\adeck_id\x18\x01 \x01(\tR\x06deckId\x12\x17\n" +
+	"\acard_id\x18\x02 \x01(\tR\x06cardId\",\n" +
+	"\fGetCardReply\x12\x1c\n" +
+	"\x04card\x18\x01 \x01(\v2\b.pb.CardR\x04card\"H\n" +
+	"\x0fPostCardRequest\x12\x17\n" +
+	"\adeck_id\x18\x01 \x01(\tR\x06deckId\x12\x1c\n" +
+	"\x04card\x18\x02 \x01(\v2\b.pb.CardR\x04card\"\x0f\n" +
+	"\rPostCardReply\"E\n" +
+	"\x11DeleteCardRequest\x12\x17\n" +
+	"\adeck_id\x18\x01 \x01(\tR\x06deckId\x12\x17\n" +
+	"\acard_id\x18\x02 \x01(\tR\x06cardId\"\x11\n" +
+	"\x0fDeleteCardReply2\xf1\x03\n" +
+	"\x06Sample\x124\n" +
+	"\bPostDeck\x12\x13.pb.PostDeckRequest\x1a\x11.pb.PostDeckReply\"\x00\x121\n" +
+	"\aGetDeck\x12\x12.pb.GetDeckRequest\x1a\x10.pb.GetDeckReply\"\x00\x121\n" +
+	"\aPutDeck\x12\x12.pb.PutDeckRequest\x1a\x10.pb.PutDeckReply\"\x00\x124\n" +
+	"\bGetDecks\x12\x13.pb.GetDecksRequest\x1a\x11.pb.GetDecksReply\"\x00\x12:\n" +
+	"\n" +
+	"DeleteDeck\x12\x15.pb.DeleteDeckRequest\x1a\x13.pb.DeleteDeckReply\"\x00\x124\n" +
+	"\bGetCards\x12\x13.pb.GetCardsRequest\x1a\x11.pb.GetCardsReply\"\x00\x121\n" +
+	"\aGetCard\x12\x12.pb.GetCardRequest\x1a\x10.pb.GetCardReply\"\x00\x124\n" +
+	"\bPostCard\x12\x13.pb.PostCardRequest\x1a\x11.pb.PostCardReply\"\x00\x12:\n" +
+	"\n" +
+	"DeleteCard\x12\x15.pb.DeleteCardRequest\x1a\x13.pb.DeleteCardReply\"\x00B>Z<github.com/TangiFavennec/go-service-sample/sample/service/pbb\x06proto3"
+
+var (
+	file_sample_proto_rawDescOnce sync.Once
+	file_sample_proto_rawDescData []byte
+)
+
+func file_sample_proto_rawDescGZIP() []byte {
+	file_sample_proto_rawDescOnce.Do(func() {
+		file_sample_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_sample_proto_rawDesc), len(file_sample_proto_rawDesc)))
+	})
+	return file_sample_proto_rawDescData
+}
+
+var file_sample_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_sample_proto_goTypes = []any{
+	(*Card)(nil),              // 0: pb.Card
+	(*Deck)(nil),              // 1: pb.Deck
+	(*PostDeckRequest)(nil),   // 2: pb.PostDeckRequest
+	(*PostDeckReply)(nil),     // 3: pb.PostDeckReply
+	(*GetDeckRequest)(nil),    // 4: pb.GetDeckRequest
+	(*GetDeckReply)(nil),      // 5: pb.GetDeckReply
+	(*PutDeckRequest)(nil),    // 6: pb.PutDeckRequest
+	(*PutDeckReply)(nil),      // 7: pb.PutDeckReply
+	(*GetDecksRequest)(nil),   // 8: pb.GetDecksRequest
+	(*GetDecksReply)(nil),     // 9: pb.GetDecksReply
+	(*DeleteDeckRequest)(nil), // 10: pb.DeleteDeckRequest
+	(*DeleteDeckReply)(nil),   // 11: pb.DeleteDeckReply
+	(*GetCardsRequest)(nil),   // 12: pb.GetCardsRequest
+	(*GetCardsReply)(nil),     // 13: pb.GetCardsReply
+	(*GetCardRequest)(nil),    // 14: pb.GetCardRequest
+	(*GetCardReply)(nil),      // 15: pb.GetCardReply
+	(*PostCardRequest)(nil),   // 16: pb.PostCardRequest
+	(*PostCardReply)(nil),     // 17: pb.PostCardReply
+	(*DeleteCardRequest)(nil), // 18: pb.DeleteCardRequest
+	(*DeleteCardReply)(nil),   // 19: pb.DeleteCardReply
+}
+var file_sample_proto_depIdxs = []int32{
+	0,  // 0: pb.Deck.cards:type_name -> pb.Card
+	1,  // 1: pb.PostDeckRequest.deck:type_name -> pb.Deck
+	1,  // 2: pb.GetDeckReply.deck:type_name -> pb.Deck
+	1,  // 3: pb.PutDeckRequest.deck:type_name -> pb.Deck
+	1,  // 4: pb.GetDecksReply.decks:type_name -> pb.Deck
+	0,  // 5: pb.GetCardsReply.cards:type_name -> pb.Card
+	0,  // 6: pb.GetCardReply.card:type_name -> pb.Card
+	0,  // 7: pb.PostCardRequest.card:type_name -> pb.Card
+	2,  // 8: pb.Sample.PostDeck:input_type -> pb.PostDeckRequest
+	4,  // 9: pb.Sample.GetDeck:input_type -> pb.GetDeckRequest
+	6,  // 10: pb.Sample.PutDeck:input_type -> pb.PutDeckRequest
+	8,  // 11: pb.Sample.GetDecks:input_type -> pb.GetDecksRequest
+	10, // 12: pb.Sample.DeleteDeck:input_type -> pb.DeleteDeckRequest
+	12, // 13: pb.Sample.GetCards:input_type -> pb.GetCardsRequest
+	14, // 14: pb.Sample.GetCard:input_type -> pb.GetCardRequest
+	16, // 15: pb.Sample.PostCard:input_type -> pb.PostCardRequest
+	18, // 16: pb.Sample.DeleteCard:input_type -> pb.DeleteCardRequest
+	3,  // 17: pb.Sample.PostDeck:output_type -> pb.PostDeckReply
+	5,  // 18: pb.Sample.GetDeck:output_type -> pb.GetDeckReply
+	7,  // 19: pb.Sample.PutDeck:output_type -> pb.PutDeckReply
+	9,  // 20: pb.Sample.GetDecks:output_type -> pb.GetDecksReply
+	11, // 21: pb.Sample.DeleteDeck:output_type -> pb.DeleteDeckReply
+	13, // 22: pb.Sample.GetCards:output_type -> pb.GetCardsReply
+	15, // 23: pb.Sample.GetCard:output_type -> pb.GetCardReply
+	17, // 24: pb.Sample.PostCard:output_type -> pb.PostCardReply
+	19, // 25: pb.Sample.DeleteCard:output_type -> pb.DeleteCardReply
+	17, // [17:26] is the sub-list for method output_type
+	8,  // [8:17] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_sample_proto_init() }
+func file_sample_proto_init() {
+	if File_sample_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_sample_proto_rawDesc), len(file_sample_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sample_proto_goTypes,
+		DependencyIndexes: file_sample_proto_depIdxs,
+		MessageInfos:      file_sample_proto_msgTypes,
+	}.Build()
+	File_sample_proto = out.File
+	file_sample_proto_goTypes = nil
+	file_sample_proto_depIdxs = nil
+}
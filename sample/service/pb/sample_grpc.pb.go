@@ -0,0 +1,405 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: sample.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Sample_PostDeck_FullMethodName   = "/pb.Sample/PostDeck"
+	Sample_GetDeck_FullMethodName    = "/pb.Sample/GetDeck"
+	Sample_PutDeck_FullMethodName    = "/pb.Sample/PutDeck"
+	Sample_GetDecks_FullMethodName   = "/pb.Sample/GetDecks"
+	Sample_DeleteDeck_FullMethodName = "/pb.Sample/DeleteDeck"
+	Sample_GetCards_FullMethodName   = "/pb.Sample/GetCards"
+	Sample_GetCard_FullMethodName    = "/pb.Sample/GetCard"
+	Sample_PostCard_FullMethodName   = "/pb.Sample/PostCard"
+	Sample_DeleteCard_FullMethodName = "/pb.Sample/DeleteCard"
+)
+
+// SampleClient is the client API for Sample service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SampleClient interface {
+	PostDeck(ctx context.Context, in *PostDeckRequest, opts ...grpc.CallOption) (*PostDeckReply, error)
+	GetDeck(ctx context.Context, in *GetDeckRequest, opts ...grpc.CallOption) (*GetDeckReply, error)
+	PutDeck(ctx context.Context, in *PutDeckRequest, opts ...grpc.CallOption) (*PutDeckReply, error)
+	GetDecks(ctx context.Context, in *GetDecksRequest, opts ...grpc.CallOption) (*GetDecksReply, error)
+	DeleteDeck(ctx context.Context, in *DeleteDeckRequest, opts ...grpc.CallOption) (*DeleteDeckReply, error)
+	GetCards(ctx context.Context, in *GetCardsRequest, opts ...grpc.CallOption) (*GetCardsReply, error)
+	GetCard(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*GetCardReply, error)
+	PostCard(ctx context.Context, in *PostCardRequest, opts ...grpc.CallOption) (*PostCardReply, error)
+	DeleteCard(ctx context.Context, in *DeleteCardRequest, opts ...grpc.CallOption) (*DeleteCardReply, error)
+}
+
+type sampleClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSampleClient(cc grpc.ClientConnInterface) SampleClient {
+	return &sampleClient{cc}
+}
+
+func (c *sampleClient) PostDeck(ctx context.Context, in *PostDeckRequest, opts ...grpc.CallOption) (*PostDeckReply, error) {
+	out := new(PostDeckReply)
+	err := c.cc.Invoke(ctx, Sample_PostDeck_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) GetDeck(ctx context.Context, in *GetDeckRequest, opts ...grpc.CallOption) (*GetDeckReply, error) {
+	out := new(GetDeckReply)
+	err := c.cc.Invoke(ctx, Sample_GetDeck_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) PutDeck(ctx context.Context, in *PutDeckRequest, opts ...grpc.CallOption) (*PutDeckReply, error) {
+	out := new(PutDeckReply)
+	err := c.cc.Invoke(ctx, Sample_PutDeck_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) GetDecks(ctx context.Context, in *GetDecksRequest, opts ...grpc.CallOption) (*GetDecksReply, error) {
+	out := new(GetDecksReply)
+	err := c.cc.Invoke(ctx, Sample_GetDecks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) DeleteDeck(ctx context.Context, in *DeleteDeckRequest, opts ...grpc.CallOption) (*DeleteDeckReply, error) {
+	out := new(DeleteDeckReply)
+	err := c.cc.Invoke(ctx, Sample_DeleteDeck_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) GetCards(ctx context.Context, in *GetCardsRequest, opts ...grpc.CallOption) (*GetCardsReply, error) {
+	out := new(GetCardsReply)
+	err := c.cc.Invoke(ctx, Sample_GetCards_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) GetCard(ctx context.Context, in *GetCardRequest, opts ...grpc.CallOption) (*GetCardReply, error) {
+	out := new(GetCardReply)
+	err := c.cc.Invoke(ctx, Sample_GetCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) PostCard(ctx context.Context, in *PostCardRequest, opts ...grpc.CallOption) (*PostCardReply, error) {
+	out := new(PostCardReply)
+	err := c.cc.Invoke(ctx, Sample_PostCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sampleClient) DeleteCard(ctx context.Context, in *DeleteCardRequest, opts ...grpc.CallOption) (*DeleteCardReply, error) {
+	out := new(DeleteCardReply)
+	err := c.cc.Invoke(ctx, Sample_DeleteCard_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SampleServer is the server API for Sample service.
+// All implementations must embed UnimplementedSampleServer
+// for forward compatibility
+type SampleServer interface {
+	PostDeck(context.Context, *PostDeckRequest) (*PostDeckReply, error)
+	GetDeck(context.Context, *GetDeckRequest) (*GetDeckReply, error)
+	PutDeck(context.Context, *PutDeckRequest) (*PutDeckReply, error)
+	GetDecks(context.Context, *GetDecksRequest) (*GetDecksReply, error)
+	DeleteDeck(context.Context, *DeleteDeckRequest) (*DeleteDeckReply, error)
+	GetCards(context.Context, *GetCardsRequest) (*GetCardsReply, error)
+	GetCard(context.Context, *GetCardRequest) (*GetCardReply, error)
+	PostCard(context.Context, *PostCardRequest) (*PostCardReply, error)
+	DeleteCard(context.Context, *DeleteCardRequest) (*DeleteCardReply, error)
+	mustEmbedUnimplementedSampleServer()
+}
+
+// UnimplementedSampleServer must be embedded to have forward compatible implementations.
+type UnimplementedSampleServer struct {
+}
+
+func (UnimplementedSampleServer) PostDeck(context.Context, *PostDeckRequest) (*PostDeckReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostDeck not implemented")
+}
+func (UnimplementedSampleServer) GetDeck(context.Context, *GetDeckRequest) (*GetDeckReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeck not implemented")
+}
+func (UnimplementedSampleServer) PutDeck(context.Context, *PutDeckRequest) (*PutDeckReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutDeck not implemented")
+}
+func (UnimplementedSampleServer) GetDecks(context.Context, *GetDecksRequest) (*GetDecksReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDecks not implemented")
+}
+func (UnimplementedSampleServer) DeleteDeck(context.Context, *DeleteDeckRequest) (*DeleteDeckReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeck not implemented")
+}
+func (UnimplementedSampleServer) GetCards(context.Context, *GetCardsRequest) (*GetCardsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCards not implemented")
+}
+func (UnimplementedSampleServer) GetCard(context.Context, *GetCardRequest) (*GetCardReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCard not implemented")
+}
+func (UnimplementedSampleServer) PostCard(context.Context, *PostCardRequest) (*PostCardReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostCard not implemented")
+}
+func (UnimplementedSampleServer) DeleteCard(context.Context, *DeleteCardRequest) (*DeleteCardReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCard not implemented")
+}
+func (UnimplementedSampleServer) mustEmbedUnimplementedSampleServer() {}
+
+// UnsafeSampleServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SampleServer will
+// result in compilation errors.
+type UnsafeSampleServer interface {
+	mustEmbedUnimplementedSampleServer()
+}
+
+func RegisterSampleServer(s grpc.ServiceRegistrar, srv SampleServer) {
+	s.RegisterService(&Sample_ServiceDesc, srv)
+}
+
+func _Sample_PostDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).PostDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_PostDeck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).PostDeck(ctx, req.(*PostDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_GetDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).GetDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_GetDeck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).GetDeck(ctx, req.(*GetDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_PutDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).PutDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_PutDeck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).PutDeck(ctx, req.(*PutDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_GetDecks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDecksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).GetDecks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_GetDecks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).GetDecks(ctx, req.(*GetDecksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_DeleteDeck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).DeleteDeck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_DeleteDeck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).DeleteDeck(ctx, req.(*DeleteDeckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_GetCards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).GetCards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_GetCards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).GetCards(ctx, req.(*GetCardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_GetCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).GetCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_GetCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).GetCard(ctx, req.(*GetCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_PostCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).PostCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_PostCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).PostCard(ctx, req.(*PostCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sample_DeleteCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SampleServer).DeleteCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sample_DeleteCard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SampleServer).DeleteCard(ctx, req.(*DeleteCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Sample_ServiceDesc is the grpc.ServiceDesc for Sample service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Sample_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Sample",
+	HandlerType: (*SampleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PostDeck",
+			Handler:    _Sample_PostDeck_Handler,
+		},
+		{
+			MethodName: "GetDeck",
+			Handler:    _Sample_GetDeck_Handler,
+		},
+		{
+			MethodName: "PutDeck",
+			Handler:    _Sample_PutDeck_Handler,
+		},
+		{
+			MethodName: "GetDecks",
+			Handler:    _Sample_GetDecks_Handler,
+		},
+		{
+			MethodName: "DeleteDeck",
+			Handler:    _Sample_DeleteDeck_Handler,
+		},
+		{
+			MethodName: "GetCards",
+			Handler:    _Sample_GetCards_Handler,
+		},
+		{
+			MethodName: "GetCard",
+			Handler:    _Sample_GetCard_Handler,
+		},
+		{
+			MethodName: "PostCard",
+			Handler:    _Sample_PostCard_Handler,
+		},
+		{
+			MethodName: "DeleteCard",
+			Handler:    _Sample_DeleteCard_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "sample.proto",
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIKeys authenticates static API keys. Only the SHA-256 hashes of the keys
+// are kept.
+type APIKeys struct {
+	subjects map[string]string // hex hash -> subject
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of key, as expected in an
+// API key file.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadAPIKeys reads an API key file: one "subject sha256-hex" pair per line,
+// blank lines and lines starting with # are ignored.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys := &APIKeys{subjects: map[string]string{}}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want \"subject sha256-hex\"", path, n)
+		}
+		hash := strings.ToLower(fields[1])
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("%s:%d: invalid SHA-256 hash", path, n)
+		}
+		keys.subjects[hash] = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Authenticate returns the principal owning key.
+func (k *APIKeys) Authenticate(key string) (Principal, error) {
+	subject, ok := k.subjects[HashAPIKey(key)]
+	if !ok {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return Principal{Subject: subject, Method: MethodAPIKey}, nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrMissingCredentials is returned when a request carries neither an
+	// API key nor a bearer token.
+	ErrMissingCredentials = errors.New("missing credentials")
+	// ErrInvalidCredentials is returned for unknown API keys and invalid,
+	// expired or unverifiable tokens.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator authenticates API keys and JWT bearer tokens. Either source
+// may be nil to disable it.
+type Authenticator struct {
+	APIKeys *APIKeys
+	JWT     *JWTValidator
+}
+
+// Authenticate returns the principal identified by the X-API-Key header
+// value apiKey or by the Authorization header value authorization. Bearer
+// tokens shaped like a JWT are validated as such, other bearer tokens are
+// looked up as API keys.
+func (a *Authenticator) Authenticate(authorization, apiKey string) (Principal, error) {
+	if apiKey != "" {
+		return a.apiKey(apiKey)
+	}
+	if authorization == "" {
+		return Principal{}, ErrMissingCredentials
+	}
+	const prefix = "bearer "
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return Principal{}, ErrInvalidCredentials
+	}
+	token := strings.TrimSpace(authorization[len(prefix):])
+	if strings.Count(token, ".") == 2 {
+		if a.JWT == nil {
+			return Principal{}, ErrInvalidCredentials
+		}
+		return a.JWT.Authenticate(token)
+	}
+	return a.apiKey(token)
+}
+
+func (a *Authenticator) apiKey(key string) (Principal, error) {
+	if a.APIKeys == nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return a.APIKeys.Authenticate(key)
+}
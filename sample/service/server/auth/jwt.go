@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var errUnknownKey = errors.New("unknown signing key")
+
+// jwk is a single JSON Web Key. Only the fields needed to verify HMAC, RSA
+// and ECDSA signatures are decoded.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// verificationKey is a decoded JWK.
+type verificationKey struct {
+	alg string
+	key interface{}
+}
+
+// JWTValidator validates bearer tokens against the keys of a JWKS file.
+type JWTValidator struct {
+	keys     map[string]verificationKey // by kid
+	issuer   string
+	audience string
+}
+
+// LoadJWKS reads a JSON Web Key Set file holding "oct" (HMAC), "RSA" and
+// "EC" keys. Tokens must carry the kid of one of the keys, unless the set
+// holds a single key. Non empty issuer and audience are required in the
+// iss and aud claims.
+func LoadJWKS(path, issuer, audience string) (*JWTValidator, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	v := &JWTValidator{
+		keys:     map[string]verificationKey{},
+		issuer:   issuer,
+		audience: audience,
+	}
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.decode()
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %d: %v", path, i, err)
+		}
+		v.keys[k.Kid] = verificationKey{alg: k.Alg, key: key}
+	}
+	if len(v.keys) == 0 {
+		return nil, fmt.Errorf("%s: no signing key", path)
+	}
+	return v, nil
+}
+
+func (k jwk) decode() (interface{}, error) {
+	switch k.Kty {
+	case "oct":
+		return decodeSegment(k.K)
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("missing key material")
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := decodeSegment(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// Authenticate validates the signature, expiry, issuer and audience of
+// token and returns the principal named by its sub claim.
+func (v *JWTValidator) Authenticate(token string) (Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+	claims := jwt.RegisteredClaims{}
+	if _, err := jwt.ParseWithClaims(token, &claims, v.keyFor, options...); err != nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+	if claims.Subject == "" {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return Principal{Subject: claims.Subject, Method: MethodJWT}, nil
+}
+
+// keyFor picks the verification key of t, checking that the signing method
+// matches the key type so an RSA public key is never used as an HMAC secret.
+func (v *JWTValidator) keyFor(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	k, ok := v.keys[kid]
+	if !ok && kid == "" && len(v.keys) == 1 {
+		for _, only := range v.keys {
+			k, ok = only, true
+		}
+	}
+	if !ok {
+		return nil, errUnknownKey
+	}
+	if k.alg != "" && k.alg != t.Method.Alg() {
+		return nil, errUnknownKey
+	}
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if _, ok := k.key.([]byte); ok {
+			return k.key, nil
+		}
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if _, ok := k.key.(*rsa.PublicKey); ok {
+			return k.key, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if _, ok := k.key.(*ecdsa.PublicKey); ok {
+			return k.key, nil
+		}
+	}
+	return nil, errUnknownKey
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader carries API keys, as an alternative to bearer tokens.
+const APIKeyHeader = "X-API-Key"
+
+// HTTPHandler answers 401 Unauthorized to requests without a valid API key
+// or bearer token, and passes the others to next with their principal in
+// their context.
+func HTTPHandler(a *Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(r.Header.Get("Authorization"), r.Header.Get(APIKeyHeader))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="decksvc"`)
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+	})
+}
+
+// UnaryServerInterceptor authenticates gRPC calls from their authorization
+// or x-api-key metadata, the same way HTTPHandler does.
+func UnaryServerInterceptor(a *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		p, err := a.Authenticate(first(md.Get("authorization")), first(md.Get("x-api-key")))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(NewContext(ctx, p), req)
+	}
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+// DeckRefSeparator separates the owner from the ID in references to Decks
+// of other users, such as "alice~spanish". Deck IDs cannot contain it.
+const DeckRefSeparator = "~"
+
+var (
+	// ErrInvalidDeckID : Deck ID containing DeckRefSeparator
+	ErrInvalidDeckID = errors.New("invalid deck ID")
+	// ErrForbidden : the caller's role on the Deck does not allow the call
+	ErrForbidden = errors.New("forbidden")
+)
+
+// ParseDeckRef returns the owner and the ID of the referenced Deck. Plain
+// IDs reference the Decks of the caller.
+func ParseDeckRef(ctx context.Context, ref string) (owner string, id string) {
+	if i := strings.LastIndex(ref, DeckRefSeparator); i >= 0 {
+		return ref[:i], ref[i+len(DeckRefSeparator):]
+	}
+	return auth.Subject(ctx), ref
+}
+
+// DeckRef returns the reference to the Deck id of owner, as seen by the
+// caller: a plain ID for its own Decks, owner~id otherwise.
+func DeckRef(ctx context.Context, owner string, id string) string {
+	if owner == auth.Subject(ctx) {
+		return id
+	}
+	return owner + DeckRefSeparator + id
+}
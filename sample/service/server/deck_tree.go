@@ -0,0 +1,15 @@
+package server
+
+import "errors"
+
+// DeckPathSeparator separates the names of the ancestors of a Deck in its
+// path, such as "Languages::Spanish::Verbs".
+const DeckPathSeparator = "::"
+
+var (
+	// ErrInvalidParent : parent Deck unknown, of another owner, or being the
+	// Deck itself or one of its sub-decks
+	ErrInvalidParent = errors.New("invalid parent deck")
+	// ErrHasChildren : Deck deleted while it still has sub-decks
+	ErrHasChildren = errors.New("deck has sub-decks")
+)
@@ -0,0 +1,121 @@
+package deckops
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the deck operations API
+type Endpoints struct {
+	CloneDeckEndpoint  endpoint.Endpoint
+	MergeDecksEndpoint endpoint.Endpoint
+	SplitDeckEndpoint  endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		CloneDeckEndpoint:  MakeCloneDeckEndpoint(s),
+		MergeDecksEndpoint: MakeMergeDecksEndpoint(s),
+		SplitDeckEndpoint:  MakeSplitDeckEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		CloneDeckEndpoint:  httptransport.NewClient("POST", tgt, encodeCloneDeckRequest, decodeCloneDeckResponse, options...).Endpoint(),
+		MergeDecksEndpoint: httptransport.NewClient("POST", tgt, encodeMergeDecksRequest, decodeMergeDecksResponse, options...).Endpoint(),
+		SplitDeckEndpoint:  httptransport.NewClient("POST", tgt, encodeSplitDeckRequest, decodeSplitDeckResponse, options...).Endpoint(),
+	}, nil
+}
+
+// CloneDeck implements Service. Primarily useful in a client.
+func (e Endpoints) CloneDeck(ctx context.Context, deckID string, clone clientModel.DeckClone) (clientModel.Deck, error) {
+	response, err := e.CloneDeckEndpoint(ctx, clientRequest.CloneDeck{DeckID: deckID, Clone: clone})
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	resp, ok := response.(clientResponse.CloneDeck)
+	if !ok {
+		return clientModel.Deck{}, ErrUnexpectedResponse
+	}
+	return resp.Deck, resp.Err
+}
+
+// MergeDecks implements Service. Primarily useful in a client.
+func (e Endpoints) MergeDecks(ctx context.Context, merge clientModel.DeckMerge) (clientModel.Deck, error) {
+	response, err := e.MergeDecksEndpoint(ctx, clientRequest.MergeDecks{Merge: merge})
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	resp, ok := response.(clientResponse.MergeDecks)
+	if !ok {
+		return clientModel.Deck{}, ErrUnexpectedResponse
+	}
+	return resp.Deck, resp.Err
+}
+
+// SplitDeck implements Service. Primarily useful in a client.
+func (e Endpoints) SplitDeck(ctx context.Context, deckID string, split clientModel.DeckSplit) (clientModel.Deck, clientModel.Deck, error) {
+	response, err := e.SplitDeckEndpoint(ctx, clientRequest.SplitDeck{DeckID: deckID, Split: split})
+	if err != nil {
+		return clientModel.Deck{}, clientModel.Deck{}, err
+	}
+	resp, ok := response.(clientResponse.SplitDeck)
+	if !ok {
+		return clientModel.Deck{}, clientModel.Deck{}, ErrUnexpectedResponse
+	}
+	return resp.Source, resp.Deck, resp.Err
+}
+
+// MakeCloneDeckEndpoint returns an endpoint via the passed service.
+func MakeCloneDeckEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.CloneDeck)
+		d, e := s.CloneDeck(ctx, req.DeckID, req.Clone)
+		return clientResponse.CloneDeck{Deck: d, Err: e}, e
+	}
+}
+
+// MakeMergeDecksEndpoint returns an endpoint via the passed service.
+func MakeMergeDecksEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.MergeDecks)
+		d, e := s.MergeDecks(ctx, req.Merge)
+		return clientResponse.MergeDecks{Deck: d, Err: e}, e
+	}
+}
+
+// MakeSplitDeckEndpoint returns an endpoint via the passed service.
+func MakeSplitDeckEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.SplitDeck)
+		src, d, e := s.SplitDeck(ctx, req.DeckID, req.Split)
+		return clientResponse.SplitDeck{Source: src, Deck: d, Err: e}, e
+	}
+}
@@ -0,0 +1,295 @@
+package deckops
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+// Service restructures Decks: copying, merging and splitting them. The
+// writes of each operation are stored in one server.SampleService
+// ApplyDecks batch, so that the repository holds either all of its changes
+// or none. Like PUT, they overwrite changes made to the Decks since they
+// were read.
+type Service interface {
+	// CloneDeck copies a Deck the caller can see into a new Deck of theirs.
+	CloneDeck(ctx context.Context, deckID string, clone clientModel.DeckClone) (clientModel.Deck, error)
+	// MergeDecks gathers the Cards of several Decks into one, created when
+	// missing, and deletes the merged Decks unless asked to keep them.
+	MergeDecks(ctx context.Context, merge clientModel.DeckMerge) (clientModel.Deck, error)
+	// SplitDeck moves the selected Cards of a Deck into a new Deck, and
+	// returns both.
+	SplitDeck(ctx context.Context, deckID string, split clientModel.DeckSplit) (clientModel.Deck, clientModel.Deck, error)
+}
+
+var (
+	// ErrMissingID : no ID given for the resulting Deck
+	ErrMissingID = errors.New("missing deck ID")
+	// ErrNoSources : no Decks to merge
+	ErrNoSources = errors.New("no decks to merge")
+	// ErrInvalidConflicts : unknown strategy for conflicting Card IDs
+	ErrInvalidConflicts = errors.New("invalid conflicts strategy, use skip, rename or overwrite")
+	// ErrInvalidCardIDs : Card ID remapping naming unknown Cards, or giving
+	// Cards a blank or shared ID
+	ErrInvalidCardIDs = errors.New("invalid card ID remapping")
+	// ErrNoCards : no Cards selected to split a Deck
+	ErrNoCards = errors.New("no cards selected")
+)
+
+type deckOpsService struct {
+	decks server.SampleService
+}
+
+// NewService Service Constructor. Decks are read and written through
+// decks.
+func NewService(decks server.SampleService) Service {
+	return &deckOpsService{
+		decks: decks,
+	}
+}
+
+// local returns d ready to be written as a Deck of the caller.
+func local(d clientModel.Deck, id string) clientModel.Deck {
+	d.ID = id
+	d.Owner = ""
+	d.Cards = append([]clientModel.Card(nil), d.Cards...)
+	return d
+}
+
+// parentOf returns the parent the Deck derived from src keeps: its own as
+// long as src belongs to the caller, none otherwise.
+func parentOf(ctx context.Context, src clientModel.Deck) string {
+	if owner, _ := server.ParseDeckRef(ctx, src.ID); owner == auth.Subject(ctx) {
+		return src.Parent
+	}
+	return ""
+}
+
+func (s *deckOpsService) CloneDeck(ctx context.Context, deckID string, clone clientModel.DeckClone) (clientModel.Deck, error) {
+	if clone.ID == "" {
+		return clientModel.Deck{}, ErrMissingID
+	}
+	src, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	d := local(src, clone.ID)
+	d.Parent = parentOf(ctx, src)
+	if clone.Name != "" {
+		d.Name = clone.Name
+	}
+	known := map[string]bool{}
+	for _, c := range d.Cards {
+		known[c.ID] = true
+	}
+	for old, id := range clone.CardIDs {
+		if !known[old] || id == "" {
+			return clientModel.Deck{}, ErrInvalidCardIDs
+		}
+	}
+	seen := map[string]bool{}
+	for i, c := range d.Cards {
+		if id, ok := clone.CardIDs[c.ID]; ok {
+			d.Cards[i].ID = id
+		}
+		if seen[d.Cards[i].ID] {
+			return clientModel.Deck{}, ErrInvalidCardIDs
+		}
+		seen[d.Cards[i].ID] = true
+	}
+	if err := s.decks.PostDeck(ctx, mapper.FromClientDeck(d)); err != nil {
+		return clientModel.Deck{}, err
+	}
+	return s.decks.GetDeck(ctx, d.ID)
+}
+
+// merger appends Cards to a Deck, solving conflicting IDs with strategy.
+type merger struct {
+	strategy string
+	cards    []clientModel.Card
+	index    map[string]int
+}
+
+func newMerger(strategy string, cards []clientModel.Card) *merger {
+	m := &merger{strategy: strategy, index: map[string]int{}}
+	for _, c := range cards {
+		m.add(c)
+	}
+	return m
+}
+
+func (m *merger) add(c clientModel.Card) {
+	i, ok := m.index[c.ID]
+	switch {
+	case !ok:
+	case m.strategy == clientModel.ConflictOverwrite:
+		m.cards[i] = c
+		return
+	case m.strategy == clientModel.ConflictRename:
+		base := c.ID
+		for n := 2; ok; n++ {
+			c.ID = base + "-" + strconv.Itoa(n)
+			_, ok = m.index[c.ID]
+		}
+	default:
+		return
+	}
+	m.index[c.ID] = len(m.cards)
+	m.cards = append(m.cards, c)
+}
+
+func (s *deckOpsService) MergeDecks(ctx context.Context, merge clientModel.DeckMerge) (clientModel.Deck, error) {
+	switch merge.Conflicts {
+	case "":
+		merge.Conflicts = clientModel.ConflictSkip
+	case clientModel.ConflictSkip, clientModel.ConflictRename, clientModel.ConflictOverwrite:
+	default:
+		return clientModel.Deck{}, ErrInvalidConflicts
+	}
+	if merge.Target == "" {
+		return clientModel.Deck{}, ErrMissingID
+	}
+
+	// Sources are read first, skipping the target and repeated ones.
+	type ref struct{ owner, id string }
+	refOf := func(id string) ref {
+		owner, id := server.ParseDeckRef(ctx, id)
+		return ref{owner, id}
+	}
+	target := refOf(merge.Target)
+	seen := map[ref]bool{target: true}
+	var sources []clientModel.Deck
+	for _, id := range merge.Sources {
+		if seen[refOf(id)] {
+			continue
+		}
+		seen[refOf(id)] = true
+		src, err := s.decks.GetDeck(ctx, id)
+		if err != nil {
+			return clientModel.Deck{}, err
+		}
+		sources = append(sources, src)
+	}
+	if len(sources) == 0 {
+		return clientModel.Deck{}, ErrNoSources
+	}
+	if !merge.KeepSources {
+		// Only Decks of the caller are deleted.
+		for _, src := range sources {
+			if refOf(src.ID).owner != auth.Subject(ctx) {
+				return clientModel.Deck{}, server.ErrForbidden
+			}
+		}
+	}
+
+	current, err := s.decks.GetDeck(ctx, merge.Target)
+	// Missing targets are created among the Decks of the caller only.
+	created := err == data.ErrNotFound && target.owner == auth.Subject(ctx)
+	if err != nil && !created {
+		return clientModel.Deck{}, err
+	}
+	d := local(current, target.id)
+	if created {
+		d = clientModel.Deck{ID: target.id, Name: target.id}
+	}
+	if merge.Name != "" {
+		d.Name = merge.Name
+	}
+	m := newMerger(merge.Conflicts, d.Cards)
+	for _, src := range sources {
+		// Cards keep the tags they inherited from their Deck.
+		for _, c := range src.Cards {
+			c.Tags = tags.Normalize(append(append([]string(nil), c.Tags...), src.Tags...))
+			m.add(c)
+		}
+	}
+	d.Cards = m.cards
+
+	writes := []server.DeckWrite{{Kind: data.WritePutDeck, ID: merge.Target, Deck: mapper.FromClientDeck(d)}}
+	if created {
+		writes[0] = server.DeckWrite{Kind: data.WritePostDeck, ID: d.ID, Deck: mapper.FromClientDeck(d)}
+	}
+	if !merge.KeepSources {
+		for _, src := range sources {
+			writes = append(writes, server.DeckWrite{Kind: data.WriteDeleteDeck, ID: src.ID})
+		}
+	}
+	if err := s.decks.ApplyDecks(ctx, writes); err != nil {
+		return clientModel.Deck{}, err
+	}
+	return s.decks.GetDeck(ctx, merge.Target)
+}
+
+func (s *deckOpsService) SplitDeck(ctx context.Context, deckID string, split clientModel.DeckSplit) (clientModel.Deck, clientModel.Deck, error) {
+	if split.ID == "" {
+		return clientModel.Deck{}, clientModel.Deck{}, ErrMissingID
+	}
+	if len(split.CardIDs) == 0 && split.Tags == "" {
+		return clientModel.Deck{}, clientModel.Deck{}, ErrNoCards
+	}
+	expr, err := tags.Parse(split.Tags)
+	if err != nil {
+		return clientModel.Deck{}, clientModel.Deck{}, err
+	}
+	src, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return clientModel.Deck{}, clientModel.Deck{}, err
+	}
+
+	// Cards are selected when listed, if any are, and matching the filter.
+	selected := map[string]bool{}
+	for _, c := range tags.FilterCards(src.Cards, src.Tags, expr) {
+		selected[c.ID] = true
+	}
+	if len(split.CardIDs) > 0 {
+		listed := map[string]bool{}
+		for _, id := range split.CardIDs {
+			listed[id] = true
+		}
+		for _, c := range src.Cards {
+			if !listed[c.ID] {
+				delete(selected, c.ID)
+			}
+			delete(listed, c.ID)
+		}
+		if len(listed) > 0 {
+			return clientModel.Deck{}, clientModel.Deck{}, data.ErrNotFound
+		}
+	}
+	if len(selected) == 0 {
+		return clientModel.Deck{}, clientModel.Deck{}, ErrNoCards
+	}
+
+	d := clientModel.Deck{ID: split.ID, Name: split.Name, Tags: src.Tags, Parent: parentOf(ctx, src)}
+	if d.Name == "" {
+		d.Name = split.ID
+	}
+	rest := local(src, src.ID)
+	rest.Cards = nil
+	for _, c := range src.Cards {
+		if selected[c.ID] {
+			d.Cards = append(d.Cards, c)
+		} else {
+			rest.Cards = append(rest.Cards, c)
+		}
+	}
+
+	if err := s.decks.ApplyDecks(ctx, []server.DeckWrite{
+		{Kind: data.WritePostDeck, ID: d.ID, Deck: mapper.FromClientDeck(d)},
+		{Kind: data.WritePutDeck, ID: deckID, Deck: mapper.FromClientDeck(rest)},
+	}); err != nil {
+		return clientModel.Deck{}, clientModel.Deck{}, err
+	}
+	if src, err = s.decks.GetDeck(ctx, deckID); err != nil {
+		return clientModel.Deck{}, clientModel.Deck{}, err
+	}
+	d, err = s.decks.GetDeck(ctx, d.ID)
+	return src, d, err
+}
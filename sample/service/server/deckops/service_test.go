@@ -0,0 +1,158 @@
+package deckops
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	middlewares "github.com/TangiFavennec/go-service-sample/sample/service/server/middlewares"
+)
+
+// newDecks returns a Service over the Decks of alice, kept a forest as in
+// main, along with the Decks it goes through and the context of alice.
+func newDecks(t *testing.T, decks ...model.Deck) (Service, server.SampleService, context.Context) {
+	t.Helper()
+	ctx := auth.NewContext(context.Background(), auth.Principal{Subject: "alice"})
+	s := server.NewService(middlewares.RepositoryTreeMiddleware()(inmem.NewInmemRepository()))
+	for _, d := range decks {
+		if err := s.PostDeck(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewService(s), s, ctx
+}
+
+func cardIDs(d clientModel.Deck) []string {
+	var ids []string
+	for _, c := range d.Cards {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+var (
+	verbs = model.Deck{ID: "verbs", Name: "Verbs", Tags: []string{"fr"}, Cards: []model.Card{
+		{ID: "a", First: "être", Second: "to be"},
+		{ID: "b", First: "avoir", Second: "to have", Tags: []string{"irregular"}},
+	}}
+	more = model.Deck{ID: "more", Name: "More", Cards: []model.Card{
+		{ID: "a", First: "aller", Second: "to go"},
+		{ID: "c", First: "faire", Second: "to do"},
+	}}
+)
+
+func TestMergeDecks(t *testing.T) {
+	for _, tc := range []struct {
+		conflicts string
+		want      []string
+		first     string // First face of the Card a
+	}{
+		{"", []string{"a", "b", "c"}, "être"},
+		{clientModel.ConflictRename, []string{"a", "b", "a-2", "c"}, "être"},
+		{clientModel.ConflictOverwrite, []string{"a", "b", "c"}, "aller"},
+	} {
+		s, decks, ctx := newDecks(t, verbs, more)
+		d, err := s.MergeDecks(ctx, clientModel.DeckMerge{Sources: []string{"verbs", "more", "verbs"}, Target: "all", Name: "All", Conflicts: tc.conflicts})
+		if err != nil {
+			t.Fatalf("%q: %v", tc.conflicts, err)
+		}
+		if !reflect.DeepEqual(cardIDs(d), tc.want) || d.Name != "All" || d.Cards[0].First != tc.first {
+			t.Errorf("%q: merged %+v", tc.conflicts, d)
+		}
+		// Cards keep the tags of their Deck, sources are deleted.
+		if !reflect.DeepEqual(d.Cards[1].Tags, []string{"irregular", "fr"}) {
+			t.Errorf("%q: tags %q", tc.conflicts, d.Cards[1].Tags)
+		}
+		if all, _ := decks.GetDecks(ctx); len(all) != 1 {
+			t.Errorf("%q: left %+v", tc.conflicts, all)
+		}
+	}
+}
+
+func TestMergeDecksIsOneBatch(t *testing.T) {
+	// The sub-deck of more forbids its deletion, the last write of the
+	// batch: the target is not created either.
+	child := model.Deck{ID: "child", Parent: "more"}
+	s, decks, ctx := newDecks(t, verbs, more, child)
+	if _, err := s.MergeDecks(ctx, clientModel.DeckMerge{Sources: []string{"verbs", "more"}, Target: "all"}); err != server.ErrHasChildren {
+		t.Fatalf("merge: %v, want %v", err, server.ErrHasChildren)
+	}
+	if all, _ := decks.GetDecks(ctx); len(all) != 3 {
+		t.Fatalf("left %+v", all)
+	}
+
+	// Sources may be kept, then nothing is deleted.
+	d, err := s.MergeDecks(ctx, clientModel.DeckMerge{Sources: []string{"more"}, Target: "verbs", KeepSources: true})
+	if err != nil || len(d.Cards) != 3 {
+		t.Fatalf("merged %+v (%v)", d, err)
+	}
+	if _, err := decks.GetDeck(ctx, "more"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		merge clientModel.DeckMerge
+		err   error
+	}{
+		{clientModel.DeckMerge{Sources: []string{"verbs"}}, ErrMissingID},
+		{clientModel.DeckMerge{Sources: []string{"verbs"}, Target: "verbs"}, ErrNoSources},
+		{clientModel.DeckMerge{Sources: []string{"verbs"}, Target: "x", Conflicts: "keep"}, ErrInvalidConflicts},
+		{clientModel.DeckMerge{Sources: []string{"missing"}, Target: "x"}, data.ErrNotFound},
+	} {
+		if _, err := s.MergeDecks(ctx, tc.merge); err != tc.err {
+			t.Errorf("%+v: %v, want %v", tc.merge, err, tc.err)
+		}
+	}
+}
+
+func TestSplitDeck(t *testing.T) {
+	s, decks, ctx := newDecks(t, verbs, more)
+	src, d, err := s.SplitDeck(ctx, "verbs", clientModel.DeckSplit{ID: "irregular", Tags: "irregular"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cardIDs(src), []string{"a"}) || !reflect.DeepEqual(cardIDs(d), []string{"b"}) {
+		t.Fatalf("split into %v and %v", cardIDs(src), cardIDs(d))
+	}
+	if d.Name != "irregular" || !reflect.DeepEqual(d.Tags, []string{"fr"}) {
+		t.Fatalf("new deck %+v", d)
+	}
+
+	// Failing splits change nothing.
+	for _, tc := range []struct {
+		split clientModel.DeckSplit
+		err   error
+	}{
+		{clientModel.DeckSplit{CardIDs: []string{"a"}}, ErrMissingID},
+		{clientModel.DeckSplit{ID: "x"}, ErrNoCards},
+		{clientModel.DeckSplit{ID: "x", CardIDs: []string{"a"}, Tags: "nope"}, ErrNoCards},
+		{clientModel.DeckSplit{ID: "x", CardIDs: []string{"z"}}, data.ErrNotFound},
+		{clientModel.DeckSplit{ID: "more", CardIDs: []string{"a"}}, data.ErrAlreadyExists},
+	} {
+		if _, _, err := s.SplitDeck(ctx, "verbs", tc.split); err != tc.err {
+			t.Errorf("%+v: %v, want %v", tc.split, err, tc.err)
+		}
+	}
+	if d, _ := decks.GetDeck(ctx, "verbs"); !reflect.DeepEqual(cardIDs(d), []string{"a"}) {
+		t.Fatalf("verbs holds %v", cardIDs(d))
+	}
+}
+
+func TestCloneDeck(t *testing.T) {
+	s, _, ctx := newDecks(t, verbs)
+	d, err := s.CloneDeck(ctx, "verbs", clientModel.DeckClone{ID: "copy", CardIDs: map[string]string{"a": "x"}})
+	if err != nil || d.Name != "Verbs" || !reflect.DeepEqual(cardIDs(d), []string{"x", "b"}) {
+		t.Fatalf("cloned %+v (%v)", d, err)
+	}
+	for _, ids := range []map[string]string{{"z": "y"}, {"a": ""}, {"a": "b"}} {
+		if _, err := s.CloneDeck(ctx, "verbs", clientModel.DeckClone{ID: "other", CardIDs: ids}); err != ErrInvalidCardIDs {
+			t.Errorf("%v: %v, want %v", ids, err, ErrInvalidCardIDs)
+		}
+	}
+}
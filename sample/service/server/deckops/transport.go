@@ -0,0 +1,195 @@
+package deckops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the deck operations endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /decks/:id:clone                copies a Deck into a new one
+	// POST    /decks:merge                    gathers the Cards of several Decks into one
+	// POST    /decks/:id:split                moves Cards of a Deck into a new one
+
+	r.Methods("POST").Path("/decks/{id}:clone").Handler(httptransport.NewServer(
+		e.CloneDeckEndpoint,
+		decodeCloneDeckRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/decks:merge").Handler(httptransport.NewServer(
+		e.MergeDecksEndpoint,
+		decodeMergeDecksRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/decks/{id}:split").Handler(httptransport.NewServer(
+		e.SplitDeckEndpoint,
+		decodeSplitDeckRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeCloneDeckRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	req := clientRequest.CloneDeck{DeckID: id}
+	if e := json.NewDecoder(r.Body).Decode(&req.Clone); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeMergeDecksRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req clientRequest.MergeDecks
+	if e := json.NewDecoder(r.Body).Decode(&req.Merge); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeSplitDeckRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	req := clientRequest.SplitDeck{DeckID: id}
+	if e := json.NewDecoder(r.Body).Decode(&req.Split); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func encodeCloneDeckRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}:clone")
+	r := request.(clientRequest.CloneDeck)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + ":clone"
+	return encodeBody(req, r.Clone)
+}
+
+func encodeMergeDecksRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks:merge")
+	r := request.(clientRequest.MergeDecks)
+	req.URL.Path = "/decks:merge"
+	return encodeBody(req, r.Merge)
+}
+
+func encodeSplitDeckRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}:split")
+	r := request.(clientRequest.SplitDeck)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + ":split"
+	return encodeBody(req, r.Split)
+}
+
+func encodeBody(req *http.Request, body interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func decodeCloneDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.CloneDeck
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeMergeDecksResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.MergeDecks
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeSplitDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.SplitDeck
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrMissingID, ErrNoSources, ErrInvalidConflicts, ErrInvalidCardIDs, ErrNoCards} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrMissingID, ErrNoSources, ErrInvalidConflicts, ErrInvalidCardIDs, ErrNoCards:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
@@ -2,13 +2,17 @@ package server
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	client "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
 	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
 	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
 	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
 	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/richtext"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
 )
 
 type defaultService struct {
@@ -18,65 +22,160 @@ type defaultService struct {
 
 // NewdefaultService In Memory Service Constructor
 func NewdefaultService() SampleService {
+	return NewService(inmem.NewInmemRepository())
+}
+
+// NewService Service Constructor backed by the given repository
+// Decks are owned by the authenticated caller of each method.
+func NewService(repo data.SampleRepository) SampleService {
 	return &defaultService{
-		repo: inmem.NewInmemRepository(),
+		repo: repo,
 	}
 }
 
 func (s *defaultService) PostDeck(ctx context.Context, p model.Deck) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
-	return s.repo.PostDeck(p)
+	if strings.Contains(p.ID, DeckRefSeparator) {
+		return ErrInvalidDeckID
+	}
+	p, err := normalizeDeck(p)
+	if err != nil {
+		return err
+	}
+	return s.repo.PostDeck(ctx, auth.Subject(ctx), p)
 }
 
 func (s *defaultService) GetDeck(ctx context.Context, id string) (client.Deck, error) {
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
-	p, ok := s.repo.GetDeck(id)
-	return mapper.ToClientDeck(p), ok
+	owner, deckID := ParseDeckRef(ctx, id)
+	p, ok := s.repo.GetDeck(ctx, owner, deckID)
+	d := mapper.ToClientDeck(p)
+	if ok == nil {
+		d.ID = DeckRef(ctx, owner, deckID)
+	}
+	return d, ok
 }
 
 func (s *defaultService) PutDeck(ctx context.Context, id string, p model.Deck) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
-	return s.repo.PutDeck(id, p)
+	owner, deckID := ParseDeckRef(ctx, id)
+	if p.ID == id {
+		p.ID = deckID // the body may carry the reference too
+	}
+	p, err := normalizeDeck(p)
+	if err != nil {
+		return err
+	}
+	return s.repo.PutDeck(ctx, owner, deckID, p)
 }
 
 func (s *defaultService) GetDecks(ctx context.Context) ([]client.Deck, error) {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
-	d, ok := s.repo.GetDecks()
+	d, ok := s.repo.GetDecks(ctx, auth.Subject(ctx))
 	return mapper.ToClientDecks(d), ok
 }
 
 func (s *defaultService) DeleteDeck(ctx context.Context, id string) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
-	return s.repo.DeleteDeck(id)
+	owner, deckID := ParseDeckRef(ctx, id)
+	return s.repo.DeleteDeck(ctx, owner, deckID)
 }
 
 func (s *defaultService) GetCards(ctx context.Context, DeckID string) ([]client.Card, error) {
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
-	p, ok := s.repo.GetCards(DeckID)
+	owner, deckID := ParseDeckRef(ctx, DeckID)
+	p, ok := s.repo.GetCards(ctx, owner, deckID)
 	return mapper.ToClientCards(p), ok
 }
 
 func (s *defaultService) GetCard(ctx context.Context, DeckID string, CardID string) (client.Card, error) {
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
-	a, ok := s.repo.GetCard(DeckID, CardID)
-	return mapper.ToClientCard(a), ok
+	owner, deckID := ParseDeckRef(ctx, DeckID)
+	cards, err := s.repo.GetCards(ctx, owner, deckID)
+	if err != nil {
+		return mapper.ToClientCard(model.Card{}), err
+	}
+	for i, c := range cards {
+		if c.ID == CardID {
+			a := mapper.ToClientCard(c)
+			a.Position = i
+			return a, nil
+		}
+	}
+	return mapper.ToClientCard(model.Card{}), data.ErrNotFound
 }
 
 func (s *defaultService) PostCard(ctx context.Context, DeckID string, a model.Card) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
-	return s.repo.PostCard(DeckID, a)
+	owner, deckID := ParseDeckRef(ctx, DeckID)
+	a.Tags = tags.Normalize(a.Tags)
+	format, err := richtext.NormalizeFormat(a.Format)
+	if err != nil {
+		return err
+	}
+	a.Format = format
+	return s.repo.PostCard(ctx, owner, deckID, a)
 }
 
 func (s *defaultService) DeleteCard(ctx context.Context, DeckID string, CardID string) error {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
-	return s.repo.DeleteCard(DeckID, CardID)
+	owner, deckID := ParseDeckRef(ctx, DeckID)
+	return s.repo.DeleteCard(ctx, owner, deckID, CardID)
+}
+
+func (s *defaultService) ApplyDecks(ctx context.Context, writes []DeckWrite) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	batch := make([]data.Write, len(writes))
+	for i, w := range writes {
+		owner, deckID := ParseDeckRef(ctx, w.ID)
+		p := w.Deck
+		switch w.Kind {
+		case data.WritePostDeck:
+			if strings.Contains(w.ID, DeckRefSeparator) {
+				return ErrInvalidDeckID
+			}
+		case data.WritePutDeck:
+			if p.ID == w.ID {
+				p.ID = deckID
+			}
+		}
+		if w.Kind != data.WriteDeleteDeck {
+			var err error
+			if p, err = normalizeDeck(p); err != nil {
+				return err
+			}
+		}
+		batch[i] = data.Write{Kind: w.Kind, Owner: owner, ID: deckID, Deck: p}
+	}
+	return s.repo.Apply(ctx, batch)
+}
+
+// normalizeDeck trims and deduplicates the tags of the Deck and its Cards,
+// and checks the formats of the Cards.
+func normalizeDeck(p model.Deck) (model.Deck, error) {
+	p.Tags = tags.Normalize(p.Tags)
+	if p.Cards != nil {
+		cards := make([]model.Card, len(p.Cards))
+		for i, c := range p.Cards {
+			c.Tags = tags.Normalize(c.Tags)
+			format, err := richtext.NormalizeFormat(c.Format)
+			if err != nil {
+				return p, err
+			}
+			c.Format = format
+			cards[i] = c
+		}
+		p.Cards = cards
+	}
+	return p, nil
 }
@@ -0,0 +1,6 @@
+package server
+
+import "errors"
+
+// ErrDuplicateCard : the Deck already holds a near-duplicate of the Card
+var ErrDuplicateCard = errors.New("duplicate card")
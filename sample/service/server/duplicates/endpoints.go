@@ -0,0 +1,71 @@
+package duplicates
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the duplicates API
+type Endpoints struct {
+	GetDuplicatesEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		GetDuplicatesEndpoint: MakeGetDuplicatesEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		GetDuplicatesEndpoint: httptransport.NewClient("GET", tgt, encodeGetDuplicatesRequest, decodeGetDuplicatesResponse, options...).Endpoint(),
+	}, nil
+}
+
+// GetDuplicates implements Service. Primarily useful in a client.
+func (e Endpoints) GetDuplicates(ctx context.Context, deckID string, threshold float64) ([]clientModel.DuplicateGroup, error) {
+	response, err := e.GetDuplicatesEndpoint(ctx, clientRequest.GetDuplicates{DeckID: deckID, Threshold: threshold})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.GetDuplicates)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Groups, resp.Err
+}
+
+// MakeGetDuplicatesEndpoint returns an endpoint via the passed service.
+func MakeGetDuplicatesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetDuplicates)
+		g, e := s.GetDuplicates(ctx, req.DeckID, req.Threshold)
+		return clientResponse.GetDuplicates{Groups: g, Err: e}, e
+	}
+}
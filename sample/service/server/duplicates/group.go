@@ -0,0 +1,116 @@
+package duplicates
+
+import (
+	"sort"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// group returns the groups of cards linked by pairs at least threshold
+// similar. Only the pairs sharing a trigram are compared.
+func group(cards []card, threshold float64) []clientModel.DuplicateGroup {
+	parent := make([]int, len(cards))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parent[i] != i {
+			parent[i] = root(parent[i])
+		}
+		return parent[i]
+	}
+	lowest := map[int]float64{} // per card, lowest similarity of its pairs
+
+	postings := map[string][]int{}
+	for i, c := range cards {
+		shared := map[int]bool{}
+		for _, t := range trigramsOf(c) {
+			for _, j := range postings[t] {
+				shared[j] = true
+			}
+			postings[t] = append(postings[t], i)
+		}
+		for j := range shared {
+			s := similarity(cards[j], c)
+			if s < threshold {
+				continue
+			}
+			for _, k := range []int{i, j} {
+				if l, ok := lowest[k]; !ok || s < l {
+					lowest[k] = s
+				}
+			}
+			parent[root(i)] = root(j)
+		}
+	}
+
+	members := map[int][]int{}
+	for i := range cards {
+		if _, ok := lowest[i]; ok {
+			members[root(i)] = append(members[root(i)], i)
+		}
+	}
+	groups := []clientModel.DuplicateGroup{}
+	for _, m := range members {
+		g := clientModel.DuplicateGroup{Exact: true, Similarity: 1}
+		for _, i := range m {
+			g.Exact = g.Exact && cards[i].key() == cards[m[0]].key()
+			if lowest[i] < g.Similarity {
+				g.Similarity = lowest[i]
+			}
+			g.Cards = append(g.Cards, clientModel.DuplicateCard{DeckID: cards[i].deckID, Card: cards[i].card})
+		}
+		sort.Slice(g.Cards, func(i, j int) bool {
+			if g.Cards[i].DeckID != g.Cards[j].DeckID {
+				return g.Cards[i].DeckID < g.Cards[j].DeckID
+			}
+			return g.Cards[i].Card.ID < g.Cards[j].Card.ID
+		})
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Similarity != groups[j].Similarity {
+			return groups[i].Similarity > groups[j].Similarity
+		}
+		if len(groups[i].Cards) != len(groups[j].Cards) {
+			return len(groups[i].Cards) > len(groups[j].Cards)
+		}
+		a, b := groups[i].Cards[0], groups[j].Cards[0]
+		return a.DeckID < b.DeckID || (a.DeckID == b.DeckID && a.Card.ID < b.Card.ID)
+	})
+	return groups
+}
+
+// trigramsOf returns the distinct trigrams of both faces of c, each tagged
+// with its face.
+func trigramsOf(c card) []string {
+	res := make([]string, 0, len(c.first.trigrams)+len(c.second.trigrams))
+	for t := range c.first.trigrams {
+		res = append(res, "1"+t)
+	}
+	for t := range c.second.trigrams {
+		res = append(res, "2"+t)
+	}
+	return res
+}
+
+// Match is a Card alike another one.
+type Match struct {
+	Card       clientModel.Card
+	Similarity float64
+}
+
+// Find returns the candidates at least threshold similar to c, most similar
+// first.
+func Find(c clientModel.Card, candidates []clientModel.Card, threshold float64) []Match {
+	target := newCard("", c)
+	var res []Match
+	for _, other := range candidates {
+		if s := similarity(target, newCard("", other)); s >= threshold {
+			res = append(res, Match{Card: other, Similarity: s})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Similarity > res[j].Similarity })
+	return res
+}
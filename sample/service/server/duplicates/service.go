@@ -0,0 +1,79 @@
+package duplicates
+
+import (
+	"context"
+	"errors"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+)
+
+// Service finds near-duplicate Cards.
+type Service interface {
+	// GetDuplicates groups the alike Cards of a Deck, or of all the Decks of
+	// the caller when deckID is empty. A zero threshold applies the default
+	// one.
+	GetDuplicates(ctx context.Context, deckID string, threshold float64) ([]clientModel.DuplicateGroup, error)
+}
+
+// DefaultThreshold is the similarity from which Cards are near-duplicates.
+const DefaultThreshold = 0.85
+
+// Checks of the new Cards against the Cards of their Deck.
+const (
+	CheckOff    = "off"
+	CheckWarn   = "warn"   // store the Card along with a warning
+	CheckReject = "reject" // refuse the Card with server.ErrDuplicateCard
+)
+
+var (
+	// ErrInvalidThreshold : threshold out of the 0..1 range
+	ErrInvalidThreshold = errors.New("invalid threshold")
+)
+
+type deckService struct {
+	decks     server.SampleService
+	threshold float64
+}
+
+// NewService Service Constructor. Cards are looked up in decks, and are
+// near-duplicates from threshold, DefaultThreshold if zero.
+func NewService(decks server.SampleService, threshold float64) Service {
+	if threshold == 0 {
+		threshold = DefaultThreshold
+	}
+	return &deckService{
+		decks:     decks,
+		threshold: threshold,
+	}
+}
+
+func (s *deckService) GetDuplicates(ctx context.Context, deckID string, threshold float64) ([]clientModel.DuplicateGroup, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+	if threshold == 0 {
+		threshold = s.threshold
+	}
+	var decks []clientModel.Deck
+	if deckID == "" {
+		all, err := s.decks.GetDecks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		decks = all
+	} else {
+		d, err := s.decks.GetDeck(ctx, deckID)
+		if err != nil {
+			return nil, err
+		}
+		decks = []clientModel.Deck{d}
+	}
+	var cards []card
+	for _, d := range decks {
+		for _, c := range d.Cards {
+			cards = append(cards, newCard(d.ID, c))
+		}
+	}
+	return group(cards, threshold), nil
+}
@@ -0,0 +1,43 @@
+package duplicates
+
+import (
+	"context"
+	"testing"
+
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+func TestGetDuplicates(t *testing.T) {
+	ctx := auth.NewContext(context.Background(), auth.Principal{Subject: "alice"})
+	decks := server.NewService(inmem.NewInmemRepository())
+	for _, d := range []model.Deck{
+		{ID: "d1", Cards: []model.Card{{ID: "a", First: "to be", Second: "être"}, {ID: "b", First: "to go", Second: "aller"}}},
+		{ID: "d2", Cards: []model.Card{{ID: "c", First: "To be", Second: "etre"}}},
+	} {
+		if err := decks.PostDeck(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewService(decks, 0)
+
+	// Cards are grouped across the Decks of the caller, or within one Deck.
+	groups, err := s.GetDuplicates(ctx, "", 0)
+	if err != nil || len(groups) != 1 || len(groups[0].Cards) != 2 {
+		t.Fatalf("all decks: %+v (%v)", groups, err)
+	}
+	if groups, err := s.GetDuplicates(ctx, "d1", 0); err != nil || len(groups) != 0 {
+		t.Fatalf("deck d1: %+v (%v)", groups, err)
+	}
+	// A threshold of 0.1 makes "to go" alike "to be".
+	if groups, _ := s.GetDuplicates(ctx, "d1", 0.1); len(groups) != 1 {
+		t.Fatalf("low threshold: %+v", groups)
+	}
+	for _, threshold := range []float64{-0.1, 1.5} {
+		if _, err := s.GetDuplicates(ctx, "", threshold); err != ErrInvalidThreshold {
+			t.Errorf("threshold %v: %v, want %v", threshold, err, ErrInvalidThreshold)
+		}
+	}
+}
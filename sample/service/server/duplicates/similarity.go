@@ -0,0 +1,124 @@
+package duplicates
+
+import (
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/search"
+)
+
+// maxEditLength bounds the faces compared by edit distance, longer ones
+// being compared by trigrams only.
+const maxEditLength = 256
+
+// face is a normalized Card face, ready for comparison.
+type face struct {
+	text     string
+	runes    []rune
+	trigrams map[string]int
+	size     int // trigrams, repetitions included
+}
+
+func newFace(text string) face {
+	f := face{text: search.Normalize(text)}
+	f.runes = []rune(f.text)
+	f.trigrams = map[string]int{}
+	padded := []rune("  " + f.text + " ")
+	for i := 0; i+3 <= len(padded); i++ {
+		f.trigrams[string(padded[i:i+3])]++
+		f.size++
+	}
+	return f
+}
+
+// card is a Card with its normalized faces.
+type card struct {
+	deckID string
+	card   clientModel.Card
+	first  face
+	second face
+}
+
+func newCard(deckID string, c clientModel.Card) card {
+	return card{deckID: deckID, card: c, first: newFace(c.First), second: newFace(c.Second)}
+}
+
+// key identifies the Cards with identical faces once normalized.
+func (c card) key() string {
+	return c.first.text + "\x00" + c.second.text
+}
+
+// Similarity returns how alike two Cards are, from 0 to 1: 1 when their
+// faces are identical once normalized (case, accents and punctuation
+// ignored), the mean similarity of their faces otherwise. Faces compare
+// by the best of their edit distance ratio and trigram Dice coefficient.
+func Similarity(a clientModel.Card, b clientModel.Card) float64 {
+	return similarity(newCard("", a), newCard("", b))
+}
+
+func similarity(a card, b card) float64 {
+	if a.key() == b.key() {
+		return 1
+	}
+	return (faceSimilarity(a.first, b.first) + faceSimilarity(a.second, b.second)) / 2
+}
+
+func faceSimilarity(a face, b face) float64 {
+	if a.text == b.text {
+		return 1
+	}
+	s := dice(a, b)
+	if len(a.runes) <= maxEditLength && len(b.runes) <= maxEditLength {
+		longest := len(a.runes)
+		if len(b.runes) > longest {
+			longest = len(b.runes)
+		}
+		if r := 1 - float64(EditDistance(a.runes, b.runes))/float64(longest); r > s {
+			s = r
+		}
+	}
+	return s
+}
+
+// dice returns the Dice coefficient of the trigrams of a and b.
+func dice(a face, b face) float64 {
+	shared := 0
+	for t, n := range a.trigrams {
+		if m := b.trigrams[t]; m < n {
+			shared += m
+		} else {
+			shared += n
+		}
+	}
+	return 2 * float64(shared) / float64(a.size+b.size)
+}
+
+// EditDistance returns the Levenshtein distance of a and b: the number of
+// runes to insert, delete or substitute to turn one into the other.
+func EditDistance(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,80 @@
+package duplicates
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+func TestEditDistance(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"être", "etre", 1},
+		{"flaw", "lawn", 2},
+	} {
+		if got := EditDistance([]rune(tc.a), []rune(tc.b)); got != tc.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	card := func(first, second string) clientModel.Card {
+		return clientModel.Card{First: first, Second: second}
+	}
+	// Case, accents and punctuation are ignored.
+	if s := Similarity(card("Être", "To be!"), card("etre", "to  be")); s != 1 {
+		t.Errorf("normalized faces: %v, want 1", s)
+	}
+	typo := Similarity(card("l'hôpital", "the hospital"), card("l'hopitl", "the hospital"))
+	if typo < DefaultThreshold || typo >= 1 {
+		t.Errorf("typo: %v, want near-duplicates", typo)
+	}
+	if s := Similarity(card("chat", "cat"), card("chien", "dog")); s >= 0.5 {
+		t.Errorf("different cards: %v", s)
+	}
+	// Faces too long for edit distances compare by trigrams.
+	long := strings.Repeat("lorem ipsum dolor ", 20)
+	if s := Similarity(card(long, "x"), card(long+"amet", "x")); s < DefaultThreshold || s >= 1 {
+		t.Errorf("long faces: %v", s)
+	}
+	if a, b := Similarity(card("a", "b"), card("c", "d")), Similarity(card("c", "d"), card("a", "b")); math.Abs(a-b) > 1e-9 {
+		t.Errorf("not symmetric: %v and %v", a, b)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var cards []card
+	for _, c := range []struct{ deck, id, first, second string }{
+		{"d1", "a", "to be", "être"},
+		{"d2", "b", "To be", "Etre"},
+		{"d1", "c", "to have", "avoir"},
+		{"d1", "d", "to have", "avoirs"},
+		{"d1", "e", "to go", "aller"},
+	} {
+		cards = append(cards, newCard(c.deck, clientModel.Card{ID: c.id, First: c.first, Second: c.second}))
+	}
+	groups := group(cards, DefaultThreshold)
+	if len(groups) != 2 {
+		t.Fatalf("groups %+v, want 2", groups)
+	}
+	exact, near := groups[0], groups[1]
+	if !exact.Exact || exact.Similarity != 1 || exact.Cards[0].Card.ID != "a" || exact.Cards[1].DeckID != "d2" {
+		t.Errorf("exact group %+v", exact)
+	}
+	if near.Exact || near.Similarity < DefaultThreshold || near.Similarity >= 1 || len(near.Cards) != 2 {
+		t.Errorf("near group %+v", near)
+	}
+
+	matches := Find(clientModel.Card{First: "to have", Second: "avoir"}, []clientModel.Card{cards[3].card, cards[2].card, cards[4].card}, DefaultThreshold)
+	if len(matches) != 2 || matches[0].Card.ID != "c" || matches[0].Similarity != 1 {
+		t.Errorf("matches %+v, want c then d", matches)
+	}
+}
@@ -0,0 +1,124 @@
+package duplicates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+// MakeHTTPHandler mounts all of the duplicates endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// GET     /decks/:id/duplicates?threshold=:t   groups the alike Cards of a Deck
+	// GET     /duplicates?threshold=:t             groups the alike Cards of all Decks
+
+	r.Methods("GET").Path("/decks/{id}/duplicates").Handler(httptransport.NewServer(
+		e.GetDuplicatesEndpoint,
+		decodeGetDuplicatesRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/duplicates").Handler(httptransport.NewServer(
+		e.GetDuplicatesEndpoint,
+		decodeGetDuplicatesRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeGetDuplicatesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	req := clientRequest.GetDuplicates{DeckID: mux.Vars(r)["id"]}
+	if t := r.URL.Query().Get("threshold"); t != "" {
+		if req.Threshold, err = strconv.ParseFloat(t, 64); err != nil {
+			return nil, ErrInvalidThreshold
+		}
+	}
+	return req, nil
+}
+
+func encodeGetDuplicatesRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/duplicates")
+	// r.Methods("GET").Path("/duplicates")
+	r := request.(clientRequest.GetDuplicates)
+	req.URL.Path = "/duplicates"
+	if r.DeckID != "" {
+		req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/duplicates"
+	}
+	if r.Threshold != 0 {
+		q := req.URL.Query()
+		q.Set("threshold", strconv.FormatFloat(r.Threshold, 'g', -1, 64))
+		req.URL.RawQuery = q.Encode()
+	}
+	return nil
+}
+
+func decodeGetDuplicatesResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetDuplicates
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrInvalidThreshold} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrInvalidThreshold:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
@@ -2,6 +2,7 @@ package endpoints
 
 import (
 	"context"
+	"errors"
 	"net/url"
 	"strings"
 
@@ -14,8 +15,20 @@ import (
 	httptransport "github.com/go-kit/kit/transport/http"
 
 	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/requestid"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/richtext"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tracing"
 )
 
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// ErrNoBatches is returned by the ApplyDecks client method, as the API has
+// no endpoint storing several writes as a whole.
+var ErrNoBatches = errors.New("deck write batches are not served remotely")
+
 // Endpoints for current microservice
 type Endpoints struct {
 	PostDeckEndpoint   endpoint.Endpoint
@@ -49,7 +62,7 @@ func MakeServerEndpoints(s server.SampleService) Endpoints {
 // MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
 // the corresponding method on the remote instance, via a transport/http.Client.
 // Useful in a decksvc client.
-func MakeClientEndpoints(instance string) (Endpoints, error) {
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
 	if !strings.HasPrefix(instance, "http") {
 		instance = "http://" + instance
 	}
@@ -58,23 +71,26 @@ func MakeClientEndpoints(instance string) (Endpoints, error) {
 		return Endpoints{}, err
 	}
 	tgt.Path = ""
-
-	options := []httptransport.ClientOption{}
+	// Propagate the trace context and request ID of every call to the
+	// remote instance.
+	options = append([]httptransport.ClientOption{
+		httptransport.ClientBefore(tracing.HTTPClientBefore, requestid.HTTPClientBefore),
+	}, options...)
 
 	// Note that the request encoders need to modify the request URL, changing
 	// the path. That's fine: we simply need to provide specific encoders for
 	// each endpoint.
 
 	return Endpoints{
-		PostDeckEndpoint:   httptransport.NewClient("POST", tgt, encodePostDeckRequest, decodePostDeckResponse, options...).Endpoint(),
-		GetDeckEndpoint:    httptransport.NewClient("GET", tgt, encodeGetDeckRequest, decodeGetDeckResponse, options...).Endpoint(),
-		PutDeckEndpoint:    httptransport.NewClient("PUT", tgt, encodePutDeckRequest, decodePutDeckResponse, options...).Endpoint(),
-		GetDecksEndpoint:   httptransport.NewClient("GET", tgt, encodeGetDecksRequest, decodeGetDecksResponse, options...).Endpoint(),
-		DeleteDeckEndpoint: httptransport.NewClient("DELETE", tgt, encodeDeleteDeckRequest, decodeDeleteDeckResponse, options...).Endpoint(),
-		GetCardsEndpoint:   httptransport.NewClient("GET", tgt, encodeGetCardsRequest, decodeGetCardsResponse, options...).Endpoint(),
-		GetCardEndpoint:    httptransport.NewClient("GET", tgt, encodeGetCardRequest, decodeGetCardResponse, options...).Endpoint(),
-		PostCardEndpoint:   httptransport.NewClient("POST", tgt, encodePostCardRequest, decodePostCardResponse, options...).Endpoint(),
-		DeleteCardEndpoint: httptransport.NewClient("DELETE", tgt, encodeDeleteCardRequest, decodeDeleteCardResponse, options...).Endpoint(),
+		PostDeckEndpoint:   tracing.ClientMiddleware("SampleService.PostDeck")(httptransport.NewClient("POST", tgt, encodePostDeckRequest, decodePostDeckResponse, options...).Endpoint()),
+		GetDeckEndpoint:    tracing.ClientMiddleware("SampleService.GetDeck")(httptransport.NewClient("GET", tgt, encodeGetDeckRequest, decodeGetDeckResponse, options...).Endpoint()),
+		PutDeckEndpoint:    tracing.ClientMiddleware("SampleService.PutDeck")(httptransport.NewClient("PUT", tgt, encodePutDeckRequest, decodePutDeckResponse, options...).Endpoint()),
+		GetDecksEndpoint:   tracing.ClientMiddleware("SampleService.GetDecks")(httptransport.NewClient("GET", tgt, encodeGetDecksRequest, decodeGetDecksResponse, options...).Endpoint()),
+		DeleteDeckEndpoint: tracing.ClientMiddleware("SampleService.DeleteDeck")(httptransport.NewClient("DELETE", tgt, encodeDeleteDeckRequest, decodeDeleteDeckResponse, options...).Endpoint()),
+		GetCardsEndpoint:   tracing.ClientMiddleware("SampleService.GetCards")(httptransport.NewClient("GET", tgt, encodeGetCardsRequest, decodeGetCardsResponse, options...).Endpoint()),
+		GetCardEndpoint:    tracing.ClientMiddleware("SampleService.GetCard")(httptransport.NewClient("GET", tgt, encodeGetCardRequest, decodeGetCardResponse, options...).Endpoint()),
+		PostCardEndpoint:   tracing.ClientMiddleware("SampleService.PostCard")(httptransport.NewClient("POST", tgt, encodePostCardRequest, decodePostCardResponse, options...).Endpoint()),
+		DeleteCardEndpoint: tracing.ClientMiddleware("SampleService.DeleteCard")(httptransport.NewClient("DELETE", tgt, encodeDeleteCardRequest, decodeDeleteCardResponse, options...).Endpoint()),
 	}, nil
 }
 
@@ -85,18 +101,30 @@ func (e Endpoints) PostDeck(ctx context.Context, p model.Deck) error {
 	if err != nil {
 		return err
 	}
-	resp := response.(clientResponse.PostDeck)
+	resp, ok := response.(clientResponse.PostDeck)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
 	return resp.Err
 }
 
 // GetDeck implements Service. Primarily useful in a client.
 func (e Endpoints) GetDeck(ctx context.Context, id string) (clientModel.Deck, error) {
-	request := clientRequest.GetDeck{ID: id}
+	return e.GetDeckRendered(ctx, id, "")
+}
+
+// GetDeckRendered returns a Deck with the faces of its Cards rendered as
+// render asks, as stored when blank (see richtext.RenderHTML).
+func (e Endpoints) GetDeckRendered(ctx context.Context, id string, render string) (clientModel.Deck, error) {
+	request := clientRequest.GetDeck{ID: id, Render: render}
 	response, err := e.GetDeckEndpoint(ctx, request)
 	if err != nil {
 		return mapper.ToClientDeck(model.Deck{}), err
 	}
-	resp := response.(clientResponse.GetDeck)
+	resp, ok := response.(clientResponse.GetDeck)
+	if !ok {
+		return mapper.ToClientDeck(model.Deck{}), ErrUnexpectedResponse
+	}
 	return resp.Deck, resp.Err
 }
 
@@ -107,17 +135,30 @@ func (e Endpoints) PutDeck(ctx context.Context, id string, p model.Deck) error {
 	if err != nil {
 		return err
 	}
-	resp := response.(clientResponse.PutDeck)
+	resp, ok := response.(clientResponse.PutDeck)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
 	return resp.Err
 }
 
 // GetDecks implements Service. Primarily useful in a client.
 func (e Endpoints) GetDecks(ctx context.Context) ([]clientModel.Deck, error) {
-	response, err := e.GetDecksEndpoint(ctx, nil)
+	return e.GetDecksTagged(ctx, "")
+}
+
+// GetDecksTagged returns the Decks whose tags match the expression, all of
+// them when blank (see tags.Parse).
+func (e Endpoints) GetDecksTagged(ctx context.Context, expr string) ([]clientModel.Deck, error) {
+	request := clientRequest.GetDecks{Tags: expr}
+	response, err := e.GetDecksEndpoint(ctx, request)
 	if err != nil {
 		return nil, err
 	}
-	resp := response.(clientResponse.GetDecks)
+	resp, ok := response.(clientResponse.GetDecks)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
 	return resp.Decks, resp.Err
 }
 
@@ -128,18 +169,42 @@ func (e Endpoints) DeleteDeck(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	resp := response.(clientResponse.DeleteDeck)
+	resp, ok := response.(clientResponse.DeleteDeck)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
 	return resp.Err
 }
 
+// ApplyDecks implements Service, failing with ErrNoBatches: remote clients
+// use the deck operations of decksvc instead.
+func (e Endpoints) ApplyDecks(ctx context.Context, writes []server.DeckWrite) error {
+	return ErrNoBatches
+}
+
 // GetCards implements Service. Primarily useful in a client.
 func (e Endpoints) GetCards(ctx context.Context, deckID string) ([]clientModel.Card, error) {
-	request := clientRequest.GetCards{DeckID: deckID}
+	return e.GetCardsTagged(ctx, deckID, "")
+}
+
+// GetCardsTagged returns the Cards of a Deck whose tags, along with those of
+// the Deck, match the expression, all of them when blank.
+func (e Endpoints) GetCardsTagged(ctx context.Context, deckID string, expr string) ([]clientModel.Card, error) {
+	return e.GetCardsRendered(ctx, deckID, expr, "")
+}
+
+// GetCardsRendered returns the Cards of a Deck matching the expression, as
+// GetCardsTagged does, with their faces rendered as render asks.
+func (e Endpoints) GetCardsRendered(ctx context.Context, deckID string, expr string, render string) ([]clientModel.Card, error) {
+	request := clientRequest.GetCards{DeckID: deckID, Tags: expr, Render: render}
 	response, err := e.GetCardsEndpoint(ctx, request)
 	if err != nil {
 		return nil, err
 	}
-	resp := response.(clientResponse.GetCards)
+	resp, ok := response.(clientResponse.GetCards)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
 	return resp.Cards, resp.Err
 }
 
@@ -150,7 +215,10 @@ func (e Endpoints) GetCard(ctx context.Context, deckID string, cardID string) (c
 	if err != nil {
 		return mapper.ToClientCard(model.Card{}), err
 	}
-	resp := response.(clientResponse.GetCard)
+	resp, ok := response.(clientResponse.GetCard)
+	if !ok {
+		return mapper.ToClientCard(model.Card{}), ErrUnexpectedResponse
+	}
 	return resp.Card, resp.Err
 }
 
@@ -161,7 +229,10 @@ func (e Endpoints) PostCard(ctx context.Context, deckID string, a model.Card) er
 	if err != nil {
 		return err
 	}
-	resp := response.(clientResponse.PostCard)
+	resp, ok := response.(clientResponse.PostCard)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
 	return resp.Err
 }
 
@@ -172,7 +243,10 @@ func (e Endpoints) DeleteCard(ctx context.Context, deckID string, cardID string)
 	if err != nil {
 		return err
 	}
-	resp := response.(clientResponse.DeleteCard)
+	resp, ok := response.(clientResponse.DeleteCard)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
 	return resp.Err
 }
 
@@ -191,8 +265,11 @@ func MakePostDeckEndpoint(s server.SampleService) endpoint.Endpoint {
 func MakeGetDeckEndpoint(s server.SampleService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(clientRequest.GetDeck)
+		if e := richtext.CheckRender(req.Render); e != nil {
+			return clientResponse.GetDeck{Err: e}, e
+		}
 		p, e := s.GetDeck(ctx, req.ID)
-		return clientResponse.GetDeck{Deck: p, Err: e}, e
+		return clientResponse.GetDeck{Deck: richtext.RenderDeck(p, req.Render), Err: e}, e
 	}
 }
 
@@ -210,7 +287,23 @@ func MakePutDeckEndpoint(s server.SampleService) endpoint.Endpoint {
 // Primarily useful in a server.
 func MakeGetDecksEndpoint(s server.SampleService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetDecks)
+		expr, e := tags.Parse(req.Tags)
+		if e != nil {
+			return clientResponse.GetDecks{Err: e}, e
+		}
+		if e := richtext.CheckRender(req.Render); e != nil {
+			return clientResponse.GetDecks{Err: e}, e
+		}
 		decks, e := s.GetDecks(ctx)
+		if e == nil && req.Tags != "" {
+			decks = tags.FilterDecks(decks, expr)
+		}
+		if e == nil && req.Render != "" {
+			for i := range decks {
+				decks[i] = richtext.RenderDeck(decks[i], req.Render)
+			}
+		}
 		return clientResponse.GetDecks{Decks: decks, Err: e}, e
 	}
 }
@@ -230,8 +323,23 @@ func MakeDeleteDeckEndpoint(s server.SampleService) endpoint.Endpoint {
 func MakeGetCardsEndpoint(s server.SampleService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(clientRequest.GetCards)
-		a, e := s.GetCards(ctx, req.DeckID)
-		return clientResponse.GetCards{Cards: a, Err: e}, e
+		if e := richtext.CheckRender(req.Render); e != nil {
+			return clientResponse.GetCards{Err: e}, e
+		}
+		if req.Tags == "" {
+			a, e := s.GetCards(ctx, req.DeckID)
+			return clientResponse.GetCards{Cards: richtext.RenderCards(a, req.Render), Err: e}, e
+		}
+		expr, e := tags.Parse(req.Tags)
+		if e != nil {
+			return clientResponse.GetCards{Err: e}, e
+		}
+		// Cards inherit the tags of their Deck.
+		d, e := s.GetDeck(ctx, req.DeckID)
+		if e != nil {
+			return clientResponse.GetCards{Err: e}, e
+		}
+		return clientResponse.GetCards{Cards: richtext.RenderCards(tags.FilterCards(d.Cards, d.Tags, expr), req.Render)}, nil
 	}
 }
 
@@ -240,7 +348,13 @@ func MakeGetCardsEndpoint(s server.SampleService) endpoint.Endpoint {
 func MakeGetCardEndpoint(s server.SampleService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(clientRequest.GetCard)
+		if e := richtext.CheckRender(req.Render); e != nil {
+			return clientResponse.GetCard{Err: e}, e
+		}
 		a, e := s.GetCard(ctx, req.DeckID, req.CardID)
+		if e == nil {
+			a = richtext.RenderCard(a, req.Render)
+		}
 		return clientResponse.GetCard{Card: a, Err: e}, e
 	}
 }
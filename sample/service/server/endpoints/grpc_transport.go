@@ -0,0 +1,410 @@
+package endpoints
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/pb"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+// grpcServiceName is the fully qualified name of the service in sample.proto.
+const grpcServiceName = "pb.Sample"
+
+type grpcServer struct {
+	pb.UnimplementedSampleServer
+	postDeck   grpctransport.Handler
+	getDeck    grpctransport.Handler
+	putDeck    grpctransport.Handler
+	getDecks   grpctransport.Handler
+	deleteDeck grpctransport.Handler
+	getCards   grpctransport.Handler
+	getCard    grpctransport.Handler
+	postCard   grpctransport.Handler
+	deleteCard grpctransport.Handler
+}
+
+// MakeGRPCServer mounts all of the service endpoints into a pb.SampleServer,
+// to be registered on a grpc.Server. Useful in a decksvc server.
+func MakeGRPCServer(s server.SampleService, logger log.Logger) pb.SampleServer {
+	e := MakeServerEndpoints(s)
+	options := []grpctransport.ServerOption{
+		grpctransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+	}
+	return &grpcServer{
+		postDeck:   grpctransport.NewServer(grpcServerErrors(e.PostDeckEndpoint), decodeGRPCPostDeckRequest, encodeGRPCPostDeckResponse, options...),
+		getDeck:    grpctransport.NewServer(grpcServerErrors(e.GetDeckEndpoint), decodeGRPCGetDeckRequest, encodeGRPCGetDeckResponse, options...),
+		putDeck:    grpctransport.NewServer(grpcServerErrors(e.PutDeckEndpoint), decodeGRPCPutDeckRequest, encodeGRPCPutDeckResponse, options...),
+		getDecks:   grpctransport.NewServer(grpcServerErrors(e.GetDecksEndpoint), decodeGRPCGetDecksRequest, encodeGRPCGetDecksResponse, options...),
+		deleteDeck: grpctransport.NewServer(grpcServerErrors(e.DeleteDeckEndpoint), decodeGRPCDeleteDeckRequest, encodeGRPCDeleteDeckResponse, options...),
+		getCards:   grpctransport.NewServer(grpcServerErrors(e.GetCardsEndpoint), decodeGRPCGetCardsRequest, encodeGRPCGetCardsResponse, options...),
+		getCard:    grpctransport.NewServer(grpcServerErrors(e.GetCardEndpoint), decodeGRPCGetCardRequest, encodeGRPCGetCardResponse, options...),
+		postCard:   grpctransport.NewServer(grpcServerErrors(e.PostCardEndpoint), decodeGRPCPostCardRequest, encodeGRPCPostCardResponse, options...),
+		deleteCard: grpctransport.NewServer(grpcServerErrors(e.DeleteCardEndpoint), decodeGRPCDeleteCardRequest, encodeGRPCDeleteCardResponse, options...),
+	}
+}
+
+// MakeGRPCClientEndpoints returns an Endpoints struct where each endpoint
+// invokes the corresponding method on the remote instance, via the given gRPC
+// connection. Useful in a decksvc client.
+func MakeGRPCClientEndpoints(conn *grpc.ClientConn) Endpoints {
+	return Endpoints{
+		PostDeckEndpoint:   grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "PostDeck", encodeGRPCPostDeckRequest, decodeGRPCPostDeckResponse, &pb.PostDeckReply{}).Endpoint()),
+		GetDeckEndpoint:    grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "GetDeck", encodeGRPCGetDeckRequest, decodeGRPCGetDeckResponse, &pb.GetDeckReply{}).Endpoint()),
+		PutDeckEndpoint:    grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "PutDeck", encodeGRPCPutDeckRequest, decodeGRPCPutDeckResponse, &pb.PutDeckReply{}).Endpoint()),
+		GetDecksEndpoint:   grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "GetDecks", encodeGRPCGetDecksRequest, decodeGRPCGetDecksResponse, &pb.GetDecksReply{}).Endpoint()),
+		DeleteDeckEndpoint: grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "DeleteDeck", encodeGRPCDeleteDeckRequest, decodeGRPCDeleteDeckResponse, &pb.DeleteDeckReply{}).Endpoint()),
+		GetCardsEndpoint:   grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "GetCards", encodeGRPCGetCardsRequest, decodeGRPCGetCardsResponse, &pb.GetCardsReply{}).Endpoint()),
+		GetCardEndpoint:    grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "GetCard", encodeGRPCGetCardRequest, decodeGRPCGetCardResponse, &pb.GetCardReply{}).Endpoint()),
+		PostCardEndpoint:   grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "PostCard", encodeGRPCPostCardRequest, decodeGRPCPostCardResponse, &pb.PostCardReply{}).Endpoint()),
+		DeleteCardEndpoint: grpcClientErrors(grpctransport.NewClient(conn, grpcServiceName, "DeleteCard", encodeGRPCDeleteCardRequest, decodeGRPCDeleteCardResponse, &pb.DeleteCardReply{}).Endpoint()),
+	}
+}
+
+func (s *grpcServer) PostDeck(ctx context.Context, req *pb.PostDeckRequest) (*pb.PostDeckReply, error) {
+	_, rep, err := s.postDeck.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.PostDeckReply), nil
+}
+
+func (s *grpcServer) GetDeck(ctx context.Context, req *pb.GetDeckRequest) (*pb.GetDeckReply, error) {
+	_, rep, err := s.getDeck.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetDeckReply), nil
+}
+
+func (s *grpcServer) PutDeck(ctx context.Context, req *pb.PutDeckRequest) (*pb.PutDeckReply, error) {
+	_, rep, err := s.putDeck.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.PutDeckReply), nil
+}
+
+func (s *grpcServer) GetDecks(ctx context.Context, req *pb.GetDecksRequest) (*pb.GetDecksReply, error) {
+	_, rep, err := s.getDecks.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetDecksReply), nil
+}
+
+func (s *grpcServer) DeleteDeck(ctx context.Context, req *pb.DeleteDeckRequest) (*pb.DeleteDeckReply, error) {
+	_, rep, err := s.deleteDeck.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.DeleteDeckReply), nil
+}
+
+func (s *grpcServer) GetCards(ctx context.Context, req *pb.GetCardsRequest) (*pb.GetCardsReply, error) {
+	_, rep, err := s.getCards.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetCardsReply), nil
+}
+
+func (s *grpcServer) GetCard(ctx context.Context, req *pb.GetCardRequest) (*pb.GetCardReply, error) {
+	_, rep, err := s.getCard.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.GetCardReply), nil
+}
+
+func (s *grpcServer) PostCard(ctx context.Context, req *pb.PostCardRequest) (*pb.PostCardReply, error) {
+	_, rep, err := s.postCard.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.PostCardReply), nil
+}
+
+func (s *grpcServer) DeleteCard(ctx context.Context, req *pb.DeleteCardRequest) (*pb.DeleteCardReply, error) {
+	_, rep, err := s.deleteCard.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return rep.(*pb.DeleteCardReply), nil
+}
+
+// GRPCRoute returns the HTTP method and path of the route serving the same
+// call as the gRPC request req, as found in http.Request.URL.Path, so that
+// both transports share rate limits.
+func GRPCRoute(req interface{}) (method string, path string) {
+	switch r := req.(type) {
+	case *pb.PostDeckRequest:
+		return "POST", "/decks"
+	case *pb.GetDeckRequest:
+		return "GET", "/decks/" + r.Id
+	case *pb.PutDeckRequest:
+		return "PUT", "/decks/" + r.Id
+	case *pb.GetDecksRequest:
+		return "GET", "/decks"
+	case *pb.DeleteDeckRequest:
+		return "DELETE", "/decks/" + r.Id
+	case *pb.GetCardsRequest:
+		return "GET", "/decks/" + r.DeckId + "/cards"
+	case *pb.GetCardRequest:
+		return "GET", "/decks/" + r.DeckId + "/cards/" + r.CardId
+	case *pb.PostCardRequest:
+		return "POST", "/decks/" + r.DeckId + "/cards"
+	case *pb.DeleteCardRequest:
+		return "DELETE", "/decks/" + r.DeckId + "/cards/" + r.CardId
+	}
+	return "", ""
+}
+
+func decodeGRPCPostDeckRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.PostDeckRequest)
+	return clientRequest.PostDeck{Deck: fromPBDeck(req.Deck)}, nil
+}
+
+func decodeGRPCGetDeckRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetDeckRequest)
+	return clientRequest.GetDeck{ID: req.Id}, nil
+}
+
+func decodeGRPCPutDeckRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.PutDeckRequest)
+	return clientRequest.PutDeck{ID: req.Id, Deck: fromPBDeck(req.Deck)}, nil
+}
+
+func decodeGRPCGetDecksRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetDecksRequest)
+	return clientRequest.GetDecks{Tags: req.Tags}, nil
+}
+
+func decodeGRPCDeleteDeckRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.DeleteDeckRequest)
+	return clientRequest.DeleteDeck{ID: req.Id}, nil
+}
+
+func decodeGRPCGetCardsRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetCardsRequest)
+	return clientRequest.GetCards{DeckID: req.DeckId, Tags: req.Tags}, nil
+}
+
+func decodeGRPCGetCardRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetCardRequest)
+	return clientRequest.GetCard{DeckID: req.DeckId, CardID: req.CardId}, nil
+}
+
+func decodeGRPCPostCardRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.PostCardRequest)
+	return clientRequest.PostCard{DeckID: req.DeckId, Card: fromPBCard(req.Card)}, nil
+}
+
+func decodeGRPCDeleteCardRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.DeleteCardRequest)
+	return clientRequest.DeleteCard{DeckID: req.DeckId, CardID: req.CardId}, nil
+}
+
+func encodeGRPCPostDeckResponse(_ context.Context, response interface{}) (interface{}, error) {
+	return &pb.PostDeckReply{}, nil
+}
+
+func encodeGRPCGetDeckResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(clientResponse.GetDeck)
+	return &pb.GetDeckReply{Deck: toPBDeck(resp.Deck)}, nil
+}
+
+func encodeGRPCPutDeckResponse(_ context.Context, response interface{}) (interface{}, error) {
+	return &pb.PutDeckReply{}, nil
+}
+
+func encodeGRPCGetDecksResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(clientResponse.GetDecks)
+	decks := make([]*pb.Deck, 0, len(resp.Decks))
+	for _, d := range resp.Decks {
+		decks = append(decks, toPBDeck(d))
+	}
+	return &pb.GetDecksReply{Decks: decks}, nil
+}
+
+func encodeGRPCDeleteDeckResponse(_ context.Context, response interface{}) (interface{}, error) {
+	return &pb.DeleteDeckReply{}, nil
+}
+
+func encodeGRPCGetCardsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(clientResponse.GetCards)
+	return &pb.GetCardsReply{Cards: toPBCards(resp.Cards)}, nil
+}
+
+func encodeGRPCGetCardResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(clientResponse.GetCard)
+	return &pb.GetCardReply{Card: toPBCard(resp.Card)}, nil
+}
+
+func encodeGRPCPostCardResponse(_ context.Context, response interface{}) (interface{}, error) {
+	return &pb.PostCardReply{}, nil
+}
+
+func encodeGRPCDeleteCardResponse(_ context.Context, response interface{}) (interface{}, error) {
+	return &pb.DeleteCardReply{}, nil
+}
+
+func encodeGRPCPostDeckRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.PostDeck)
+	return &pb.PostDeckRequest{Deck: toPBDeck(req.Deck)}, nil
+}
+
+func encodeGRPCGetDeckRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.GetDeck)
+	return &pb.GetDeckRequest{Id: req.ID}, nil
+}
+
+func encodeGRPCPutDeckRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.PutDeck)
+	return &pb.PutDeckRequest{Id: req.ID, Deck: toPBDeck(req.Deck)}, nil
+}
+
+func encodeGRPCGetDecksRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req, _ := request.(clientRequest.GetDecks)
+	return &pb.GetDecksRequest{Tags: req.Tags}, nil
+}
+
+func encodeGRPCDeleteDeckRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.DeleteDeck)
+	return &pb.DeleteDeckRequest{Id: req.ID}, nil
+}
+
+func encodeGRPCGetCardsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.GetCards)
+	return &pb.GetCardsRequest{DeckId: req.DeckID, Tags: req.Tags}, nil
+}
+
+func encodeGRPCGetCardRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.GetCard)
+	return &pb.GetCardRequest{DeckId: req.DeckID, CardId: req.CardID}, nil
+}
+
+func encodeGRPCPostCardRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.PostCard)
+	return &pb.PostCardRequest{DeckId: req.DeckID, Card: toPBCard(req.Card)}, nil
+}
+
+func encodeGRPCDeleteCardRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(clientRequest.DeleteCard)
+	return &pb.DeleteCardRequest{DeckId: req.DeckID, CardId: req.CardID}, nil
+}
+
+func decodeGRPCPostDeckResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	return clientResponse.PostDeck{}, nil
+}
+
+func decodeGRPCGetDeckResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.GetDeckReply)
+	return clientResponse.GetDeck{Deck: fromPBDeck(reply.Deck)}, nil
+}
+
+func decodeGRPCPutDeckResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	return clientResponse.PutDeck{}, nil
+}
+
+func decodeGRPCGetDecksResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.GetDecksReply)
+	decks := make([]clientModel.Deck, 0, len(reply.Decks))
+	for _, d := range reply.Decks {
+		decks = append(decks, fromPBDeck(d))
+	}
+	return clientResponse.GetDecks{Decks: decks}, nil
+}
+
+func decodeGRPCDeleteDeckResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	return clientResponse.DeleteDeck{}, nil
+}
+
+func decodeGRPCGetCardsResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.GetCardsReply)
+	return clientResponse.GetCards{Cards: fromPBCards(reply.Cards)}, nil
+}
+
+func decodeGRPCGetCardResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	reply := grpcReply.(*pb.GetCardReply)
+	return clientResponse.GetCard{Card: fromPBCard(reply.Card)}, nil
+}
+
+func decodeGRPCPostCardResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	return clientResponse.PostCard{}, nil
+}
+
+func decodeGRPCDeleteCardResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
+	return clientResponse.DeleteCard{}, nil
+}
+
+func toPBCard(c clientModel.Card) *pb.Card {
+	return &pb.Card{Id: c.ID, First: c.First, Second: c.Second, Tags: c.Tags, Format: c.Format}
+}
+
+func toPBCards(cards []clientModel.Card) []*pb.Card {
+	res := make([]*pb.Card, 0, len(cards))
+	for _, c := range cards {
+		res = append(res, toPBCard(c))
+	}
+	return res
+}
+
+func toPBDeck(d clientModel.Deck) *pb.Deck {
+	return &pb.Deck{Id: d.ID, Name: d.Name, Cards: toPBCards(d.Cards), Tags: d.Tags, Parent: d.Parent, Owner: d.Owner}
+}
+
+func fromPBCard(c *pb.Card) clientModel.Card {
+	return clientModel.Card{ID: c.GetId(), First: c.GetFirst(), Second: c.GetSecond(), Tags: c.GetTags(), Format: c.GetFormat()}
+}
+
+func fromPBCards(cards []*pb.Card) []clientModel.Card {
+	var res []clientModel.Card
+	for i, c := range cards {
+		a := fromPBCard(c)
+		a.Position = i
+		res = append(res, a)
+	}
+	return res
+}
+
+func fromPBDeck(d *pb.Deck) clientModel.Deck {
+	return clientModel.Deck{ID: d.GetId(), Owner: d.GetOwner(), Name: d.GetName(), Cards: fromPBCards(d.GetCards()), Tags: d.GetTags(), Parent: d.GetParent()}
+}
+
+// grpcServerErrors turns business errors into gRPC statuses.
+func grpcServerErrors(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		response, err := next(ctx, request)
+		if err == nil {
+			return response, nil
+		}
+		return response, status.Error(server.GRPCCode(err), err.Error())
+	}
+}
+
+// grpcClientErrors turns gRPC statuses back into business errors, so that
+// clients get the very same error values back.
+func grpcClientErrors(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		response, err := next(ctx, request)
+		if err == nil {
+			return response, nil
+		}
+		if st, ok := status.FromError(err); ok {
+			if known := server.KnownError(st.Message()); known != nil && server.GRPCCode(known) == st.Code() {
+				return response, known
+			}
+		}
+		return response, err
+	}
+}
@@ -0,0 +1,163 @@
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/pb"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/richtext"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+func TestGRPCErrors(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code codes.Code
+	}{
+		{data.ErrNotFound, codes.NotFound},
+		{data.ErrAlreadyExists, codes.AlreadyExists},
+		{server.ErrInvalidDeckID, codes.InvalidArgument},
+		{server.ErrForbidden, codes.PermissionDenied},
+		{server.ErrDeckQuotaExceeded, codes.ResourceExhausted},
+		{server.ErrPayloadTooLarge, codes.ResourceExhausted},
+		{server.ErrHasChildren, codes.FailedPrecondition},
+		{tags.ErrInvalidExpression, codes.InvalidArgument},
+		{richtext.ErrInvalidFormat, codes.InvalidArgument},
+		{errors.New("disk full"), codes.Internal},
+	} {
+		failing := func(context.Context, interface{}) (interface{}, error) { return nil, tc.err }
+		_, err := grpcServerErrors(failing)(context.Background(), nil)
+		if status.Code(err) != tc.code {
+			t.Errorf("%v: answered %v, want %v", tc.err, status.Code(err), tc.code)
+		}
+		answering := func(context.Context, interface{}) (interface{}, error) { return nil, err }
+		_, err = grpcClientErrors(answering)(context.Background(), nil)
+		if server.IsKnownError(tc.err) && err != tc.err {
+			t.Errorf("%v: client got %v back", tc.err, err)
+		}
+	}
+
+	// A status of another code is not mistaken for a known error.
+	answering := func(context.Context, interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unavailable, data.ErrNotFound.Error())
+	}
+	if _, err := grpcClientErrors(answering)(context.Background(), nil); err == data.ErrNotFound {
+		t.Error("Unavailable status turned into data.ErrNotFound")
+	}
+}
+
+// grpcClient serves s over an in-memory gRPC connection, every call being
+// made by alice, and returns a client of it.
+func grpcClient(t *testing.T, s server.SampleService) Endpoints {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	alice := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(auth.NewContext(ctx, auth.Principal{Subject: "alice"}), req)
+	}
+	srv := grpc.NewServer(grpc.UnaryInterceptor(alice))
+	pb.RegisterSampleServer(srv, MakeGRPCServer(s, log.NewNopLogger()))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return MakeGRPCClientEndpoints(conn)
+}
+
+func TestGRPCCarriesOwner(t *testing.T) {
+	ctx := context.Background()
+	c := grpcClient(t, server.NewService(inmem.NewInmemRepository()))
+	if err := c.PostDeck(ctx, model.Deck{ID: "d", Name: "Verbs"}); err != nil {
+		t.Fatal(err)
+	}
+	d, err := c.GetDeck(ctx, "d")
+	if err != nil || d.Owner != "alice" {
+		t.Fatalf("got %+v (%v), want the deck of alice", d, err)
+	}
+	decks, err := c.GetDecks(ctx)
+	if err != nil || len(decks) != 1 || decks[0].Owner != "alice" {
+		t.Fatalf("listed %+v (%v), want the deck of alice", decks, err)
+	}
+}
+
+func TestGRPCCarriesTags(t *testing.T) {
+	ctx := context.Background()
+	c := grpcClient(t, server.NewService(inmem.NewInmemRepository()))
+	for _, d := range []model.Deck{
+		{ID: "verbs", Tags: []string{"fr", "verbs"}, Cards: []model.Card{{ID: "a", First: "être", Second: "to be", Tags: []string{"irregular"}}, {ID: "b", First: "parler", Second: "to speak"}}},
+		{ID: "nouns", Tags: []string{"fr"}},
+	} {
+		if err := c.PostDeck(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	decks, err := c.GetDecksTagged(ctx, "fr AND NOT verbs")
+	if err != nil || len(decks) != 1 || decks[0].ID != "nouns" {
+		t.Fatalf("filtered %+v (%v), want nouns", decks, err)
+	}
+	cards, err := c.GetCardsTagged(ctx, "verbs", "irregular")
+	if err != nil || len(cards) != 1 || cards[0].ID != "a" || len(cards[0].Tags) != 1 {
+		t.Fatalf("filtered %+v (%v), want a with its tag", cards, err)
+	}
+	if _, err := c.GetDecksTagged(ctx, "fr AND"); err != tags.ErrInvalidExpression {
+		t.Fatalf("invalid expression: %v, want %v", err, tags.ErrInvalidExpression)
+	}
+}
+
+func TestGRPCCarriesParent(t *testing.T) {
+	ctx := context.Background()
+	c := grpcClient(t, server.NewService(inmem.NewInmemRepository()))
+	for _, d := range []model.Deck{{ID: "lang"}, {ID: "es", Parent: "lang"}} {
+		if err := c.PostDeck(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d, err := c.GetDeck(ctx, "es"); err != nil || d.Parent != "lang" {
+		t.Fatalf("got %+v (%v), want it under lang", d, err)
+	}
+	if err := c.PutDeck(ctx, "es", model.Deck{ID: "es"}); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := c.GetDeck(ctx, "es"); err != nil || d.Parent != "" {
+		t.Fatalf("got %+v (%v), want it moved to the top", d, err)
+	}
+}
+
+func TestGRPCCarriesFormat(t *testing.T) {
+	ctx := context.Background()
+	c := grpcClient(t, server.NewService(inmem.NewInmemRepository()))
+	if err := c.PostDeck(ctx, model.Deck{ID: "d"}); err != nil {
+		t.Fatal(err)
+	}
+	face := `**to be** <script>alert(1)</script>`
+	if err := c.PostCard(ctx, "d", model.Card{ID: "c", First: face, Second: "être", Format: "Markdown"}); err != nil {
+		t.Fatal(err)
+	}
+	// Faces are stored as written, the format normalized.
+	card, err := c.GetCard(ctx, "d", "c")
+	if err != nil || card.Format != richtext.FormatMarkdown || card.First != face {
+		t.Fatalf("got %+v (%v)", card, err)
+	}
+	if err := c.PostCard(ctx, "d", model.Card{ID: "x", Format: "rtf"}); err != richtext.ErrInvalidFormat {
+		t.Fatalf("unknown format: %v, want %v", err, richtext.ErrInvalidFormat)
+	}
+}
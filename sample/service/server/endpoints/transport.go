@@ -5,21 +5,24 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
 	"github.com/go-kit/kit/log"
-	"github.com/go-kit/kit/transport"
 	httptransport "github.com/go-kit/kit/transport/http"
 
 	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
 	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
 	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
-	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
 	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tracing"
 )
 
 var (
@@ -34,8 +37,12 @@ func MakeHTTPHandler(s server.SampleService, logger log.Logger) http.Handler {
 	r := mux.NewRouter()
 	e := MakeServerEndpoints(s)
 	options := []httptransport.ServerOption{
-		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
 		httptransport.ServerErrorEncoder(encodeError),
+		httptransport.ServerBefore(tracing.HTTPServerBefore),
+		httptransport.ServerFinalizer(tracing.HTTPServerFinalizer),
+		httptransport.ServerBefore(warningsBefore),
+		httptransport.ServerAfter(warningsAfter),
 	}
 
 	// POST    /decks/                          adds another Deck
@@ -119,7 +126,7 @@ func decodeGetDeckRequest(_ context.Context, r *http.Request) (request interface
 	if !ok {
 		return nil, ErrBadRouting
 	}
-	return clientRequest.GetDeck{ID: id}, nil
+	return clientRequest.GetDeck{ID: id, Render: r.URL.Query().Get("render")}, nil
 }
 
 func decodePutDeckRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
@@ -139,7 +146,8 @@ func decodePutDeckRequest(_ context.Context, r *http.Request) (request interface
 }
 
 func decodeGetDecksRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
-	return nil, nil
+	q := r.URL.Query()
+	return clientRequest.GetDecks{Tags: q.Get("tags"), Render: q.Get("render")}, nil
 }
 
 func decodeDeleteDeckRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
@@ -157,7 +165,8 @@ func decodeGetCardsRequest(_ context.Context, r *http.Request) (request interfac
 	if !ok {
 		return nil, ErrBadRouting
 	}
-	return clientRequest.GetCards{DeckID: id}, nil
+	q := r.URL.Query()
+	return clientRequest.GetCards{DeckID: id, Tags: q.Get("tags"), Render: q.Get("render")}, nil
 }
 
 func decodeGetCardRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
@@ -173,6 +182,7 @@ func decodeGetCardRequest(_ context.Context, r *http.Request) (request interface
 	return clientRequest.GetCard{
 		DeckID: id,
 		CardID: cardID,
+		Render: r.URL.Query().Get("render"),
 	}, nil
 }
 
@@ -210,8 +220,9 @@ func decodeDeleteCardRequest(_ context.Context, r *http.Request) (request interf
 
 func encodePostDeckRequest(ctx context.Context, req *http.Request, request interface{}) error {
 	// r.Methods("POST").Path("/decks")
+	r := request.(clientRequest.PostDeck)
 	req.URL.Path = "/decks"
-	return encodeRequest(ctx, req, request)
+	return encodeRequest(ctx, req, r.Deck)
 }
 
 func encodeGetDeckRequest(ctx context.Context, req *http.Request, request interface{}) error {
@@ -219,6 +230,7 @@ func encodeGetDeckRequest(ctx context.Context, req *http.Request, request interf
 	r := request.(clientRequest.GetDeck)
 	deckID := url.QueryEscape(r.ID)
 	req.URL.Path = "/decks/" + deckID
+	req.URL.RawQuery = readQuery("", r.Render)
 	return encodeRequest(ctx, req, request)
 }
 
@@ -227,12 +239,15 @@ func encodePutDeckRequest(ctx context.Context, req *http.Request, request interf
 	r := request.(clientRequest.PutDeck)
 	deckID := url.QueryEscape(r.ID)
 	req.URL.Path = "/decks/" + deckID
-	return encodeRequest(ctx, req, request)
+	return encodeRequest(ctx, req, r.Deck)
 }
 
 func encodeGetDecksRequest(ctx context.Context, req *http.Request, request interface{}) error {
 	// r.Methods("GET").Path("/decks")
 	req.URL.Path = "/decks"
+	if r, ok := request.(clientRequest.GetDecks); ok {
+		req.URL.RawQuery = readQuery(r.Tags, r.Render)
+	}
 	return encodeRequest(ctx, req, request)
 }
 
@@ -249,6 +264,7 @@ func encodeGetCardsRequest(ctx context.Context, req *http.Request, request inter
 	r := request.(clientRequest.GetCards)
 	deckID := url.QueryEscape(r.DeckID)
 	req.URL.Path = "/decks/" + deckID + "/cards"
+	req.URL.RawQuery = readQuery(r.Tags, r.Render)
 	return encodeRequest(ctx, req, request)
 }
 
@@ -258,15 +274,29 @@ func encodeGetCardRequest(ctx context.Context, req *http.Request, request interf
 	deckID := url.QueryEscape(r.DeckID)
 	cardID := url.QueryEscape(r.CardID)
 	req.URL.Path = "/decks/" + deckID + "/cards/" + cardID
+	req.URL.RawQuery = readQuery("", r.Render)
 	return encodeRequest(ctx, req, request)
 }
 
+// readQuery returns the query string of reads filtered by a tags expression
+// and rendered as render asks, blank values being left out.
+func readQuery(tags string, render string) string {
+	q := url.Values{}
+	if tags != "" {
+		q.Set("tags", tags)
+	}
+	if render != "" {
+		q.Set("render", render)
+	}
+	return q.Encode()
+}
+
 func encodePostCardRequest(ctx context.Context, req *http.Request, request interface{}) error {
 	// r.Methods("POST").Path("/decks/{id}/cards")
 	r := request.(clientRequest.PostCard)
 	deckID := url.QueryEscape(r.DeckID)
 	req.URL.Path = "/decks/" + deckID + "/cards"
-	return encodeRequest(ctx, req, request)
+	return encodeRequest(ctx, req, r.Card)
 }
 
 func encodeDeleteCardRequest(ctx context.Context, req *http.Request, request interface{}) error {
@@ -279,59 +309,113 @@ func encodeDeleteCardRequest(ctx context.Context, req *http.Request, request int
 }
 
 func decodePostDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.PostDeck
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodeGetDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.GetDeck
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodePutDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.PutDeck
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodeGetDecksResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.GetDecks
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodeDeleteDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.DeleteDeck
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodeGetCardResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.GetCard
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodeGetCardsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.GetCards
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodePostCardResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.PostCard
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
 func decodeDeleteCardResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
 	var response clientResponse.DeleteCard
 	err := json.NewDecoder(resp.Body).Decode(&response)
 	return response, err
 }
 
+// HTTPError is returned by client endpoints when the server answers with an
+// error status that does not map onto a known business error.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+}
+
+// decodeError turns an error response written by encodeError back into the
+// business error it was built from, or an *HTTPError.
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	if body.Error == "" {
+		body.Error = http.StatusText(resp.StatusCode)
+	}
+	return &HTTPError{StatusCode: resp.StatusCode, Message: body.Error}
+}
+
 // errorer is implemented by all concrete response types that may contain
 // errors. It allows us to change the HTTP response code without needing to
 // trigger an endpoint (transport-level) error. For more information, read the
@@ -340,6 +424,19 @@ type errorer interface {
 	error() error
 }
 
+// warningsBefore collects the warnings of the service calls of the request.
+func warningsBefore(ctx context.Context, _ *http.Request) context.Context {
+	return server.NewWarningsContext(ctx)
+}
+
+// warningsAfter sends the warnings of successful calls as Warning headers.
+func warningsAfter(ctx context.Context, w http.ResponseWriter) context.Context {
+	for _, msg := range server.Warnings(ctx) {
+		w.Header().Add("Warning", "299 decksvc "+strconv.Quote(msg))
+	}
+	return ctx
+}
+
 // encodeResponse is the common method to encode all response types to the
 // clientRequest. I chose to do it this way because, since we're using JSON, there's no
 // reason to provide anything more specific. It's certainly possible to
@@ -380,12 +477,5 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 }
 
 func codeFrom(err error) int {
-	switch err {
-	case data.ErrNotFound:
-		return http.StatusNotFound
-	case data.ErrAlreadyExists, data.ErrInconsistentIDs:
-		return http.StatusBadRequest
-	default:
-		return http.StatusInternalServerError
-	}
+	return server.HTTPStatus(err)
 }
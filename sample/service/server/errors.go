@@ -0,0 +1,79 @@
+package server
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/richtext"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+// knownErrors lists the errors of SampleService refusing calls, along with
+// the HTTP status and the gRPC code every transport answers them with.
+// Other errors are failures of the service.
+var knownErrors = []struct {
+	err    error
+	status int
+	code   codes.Code
+}{
+	{data.ErrNotFound, http.StatusNotFound, codes.NotFound},
+	{data.ErrAlreadyExists, http.StatusBadRequest, codes.AlreadyExists},
+	{data.ErrInconsistentIDs, http.StatusBadRequest, codes.InvalidArgument},
+	{ErrInvalidDeckID, http.StatusBadRequest, codes.InvalidArgument},
+	{ErrInvalidParent, http.StatusBadRequest, codes.InvalidArgument},
+	{tags.ErrInvalidExpression, http.StatusBadRequest, codes.InvalidArgument},
+	{richtext.ErrInvalidFormat, http.StatusBadRequest, codes.InvalidArgument},
+	{richtext.ErrInvalidRender, http.StatusBadRequest, codes.InvalidArgument},
+	{ErrForbidden, http.StatusForbidden, codes.PermissionDenied},
+	{ErrDeckQuotaExceeded, http.StatusForbidden, codes.ResourceExhausted},
+	{ErrCardQuotaExceeded, http.StatusForbidden, codes.ResourceExhausted},
+	{ErrPayloadTooLarge, http.StatusRequestEntityTooLarge, codes.ResourceExhausted},
+	{ErrDuplicateCard, http.StatusConflict, codes.AlreadyExists},
+	{ErrHasChildren, http.StatusConflict, codes.FailedPrecondition},
+}
+
+// HTTPStatus returns the HTTP status answering err, 500 Internal Server
+// Error unless err is a known error.
+func HTTPStatus(err error) int {
+	for _, e := range knownErrors {
+		if err == e.err {
+			return e.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the gRPC code answering err, Internal unless err is a
+// known error.
+func GRPCCode(err error) codes.Code {
+	for _, e := range knownErrors {
+		if err == e.err {
+			return e.code
+		}
+	}
+	return codes.Internal
+}
+
+// IsKnownError tells whether err refuses a call, rather than reports a
+// failure of the service.
+func IsKnownError(err error) bool {
+	for _, e := range knownErrors {
+		if err == e.err {
+			return true
+		}
+	}
+	return false
+}
+
+// KnownError returns the known error whose message is msg, or nil. Clients
+// use it to get the very same error values back from error responses.
+func KnownError(msg string) error {
+	for _, e := range knownErrors {
+		if msg == e.err.Error() {
+			return e.err
+		}
+	}
+	return nil
+}
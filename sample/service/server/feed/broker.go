@@ -0,0 +1,164 @@
+package feed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+)
+
+// subscriberBuffer is the number of events a subscriber may lag behind before
+// being dropped. A dropped subscriber simply reconnects with its
+// Last-Event-ID.
+const subscriberBuffer = 64
+
+// Broker numbers published events, keeps the most recent ones in a bounded
+// ring buffer and fans them out to subscribers.
+type Broker struct {
+	mtx    sync.Mutex
+	ring   []Event
+	start  int // index of the oldest buffered event in ring
+	size   int // number of buffered events
+	last   uint64
+	nextID int
+	subs   map[int]*Subscription
+}
+
+// NewBroker returns a Broker buffering up to capacity events for resumption.
+func NewBroker(capacity int) *Broker {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Broker{
+		ring: make([]Event, capacity),
+		subs: map[int]*Subscription{},
+	}
+}
+
+// Subscription delivers events matching its filter on C.
+// C is closed when the subscription is closed or dropped for lagging behind.
+type Subscription struct {
+	C      <-chan Event
+	c      chan Event
+	id     int
+	all    bool // every owner, for internal consumers
+	owner  string
+	deckID string
+	broker *Broker
+}
+
+// Close stops the delivery of events. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broker.mtx.Lock()
+	defer s.broker.mtx.Unlock()
+	s.broker.remove(s)
+}
+
+// Publish assigns the next sequence number to the event, buffers it and
+// delivers it to every matching subscriber.
+func (b *Broker) Publish(e Event) Event {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.last++
+	e.ID = b.last
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if b.size < len(b.ring) {
+		b.ring[(b.start+b.size)%len(b.ring)] = e
+		b.size++
+	} else {
+		b.ring[b.start] = e
+		b.start = (b.start + 1) % len(b.ring)
+	}
+	for _, s := range b.subs {
+		if !s.matches(e) {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+			b.remove(s)
+		}
+	}
+	return e
+}
+
+// Subscribe registers a new subscriber. Buffered events after lastEventID are
+// replayed first; a lastEventID of zero means live events only. Only the
+// events of the Decks of owner, and of the Decks shared with owner, are
+// delivered. deckID, if not empty, restricts the subscription to a single
+// Deck, referenced the way owner does.
+func (b *Broker) Subscribe(lastEventID uint64, owner string, deckID string) *Subscription {
+	return b.subscribe(lastEventID, &Subscription{owner: owner, deckID: deckID})
+}
+
+// SubscribeAll registers a subscriber receiving the events of every owner,
+// such as the webhook dispatcher. Buffered events after lastEventID are
+// replayed first.
+func (b *Broker) SubscribeAll(lastEventID uint64) *Subscription {
+	return b.subscribe(lastEventID, &Subscription{all: true})
+}
+
+func (b *Broker) subscribe(lastEventID uint64, s *Subscription) *Subscription {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.nextID++
+	s.id = b.nextID
+	s.broker = b
+
+	var backlog []Event
+	if lastEventID > 0 && lastEventID < b.last {
+		oldest := b.last - uint64(b.size) + 1
+		if lastEventID+1 < oldest {
+			backlog = append(backlog, Event{Type: Reset, Time: time.Now().UTC()})
+		}
+		for i := 0; i < b.size; i++ {
+			e := b.ring[(b.start+i)%len(b.ring)]
+			if e.ID > lastEventID && s.matches(e) {
+				backlog = append(backlog, e)
+			}
+		}
+	}
+
+	c := make(chan Event, subscriberBuffer+len(backlog))
+	for _, e := range backlog {
+		c <- e
+	}
+	s.C, s.c = c, c
+	b.subs[s.id] = s
+	return s
+}
+
+// remove must be called with the lock held.
+func (b *Broker) remove(s *Subscription) {
+	if _, ok := b.subs[s.id]; !ok {
+		return
+	}
+	delete(b.subs, s.id)
+	close(s.c)
+}
+
+func (s *Subscription) matches(e Event) bool {
+	if s.all {
+		return true
+	}
+	ref := e.DeckID
+	if e.Owner != s.owner {
+		if !contains(e.Members, s.owner) {
+			return false
+		}
+		ref = e.Owner + server.DeckRefSeparator + e.DeckID // as members reference it
+	}
+	return s.deckID == "" || ref == s.deckID
+}
+
+func contains(users []string, user string) bool {
+	for _, u := range users {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
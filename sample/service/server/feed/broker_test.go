@@ -0,0 +1,64 @@
+package feed
+
+import (
+	"testing"
+)
+
+// received drains the events already delivered to s.
+func received(s *Subscription) []Event {
+	var events []Event
+	for {
+		select {
+		case e := <-s.C:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestMembersReceiveSharedDeckEvents(t *testing.T) {
+	b := NewBroker(16)
+	alice := b.Subscribe(0, "alice", "")
+	bob := b.Subscribe(0, "bob", "")
+	bobDeck := b.Subscribe(0, "bob", "alice~d")
+	carol := b.Subscribe(0, "carol", "")
+
+	b.Publish(Event{Type: DeckUpdated, Owner: "alice", DeckID: "d", Members: []string{"bob"}})
+	b.Publish(Event{Type: DeckUpdated, Owner: "alice", DeckID: "e"})
+
+	for name, tc := range map[string]struct {
+		sub  *Subscription
+		want int
+	}{
+		"owner":               {alice, 2},
+		"member":              {bob, 1},
+		"member, deck filter": {bobDeck, 1},
+		"stranger":            {carol, 0},
+	} {
+		if got := received(tc.sub); len(got) != tc.want {
+			t.Errorf("%s: %d events, want %d", name, len(got), tc.want)
+		}
+	}
+}
+
+func TestResumeReplaysMatchingEvents(t *testing.T) {
+	b := NewBroker(2)
+	for i := 0; i < 3; i++ {
+		b.Publish(Event{Type: DeckCreated, Owner: "alice", DeckID: "d"})
+	}
+	got := received(b.Subscribe(1, "alice", ""))
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Fatalf("replayed %+v, want events 2 and 3", got)
+	}
+	got = received(b.Subscribe(0, "alice", ""))
+	if len(got) != 0 {
+		t.Fatalf("live subscription replayed %+v", got)
+	}
+	// Event 1 is gone from the buffer: a reset comes first.
+	b.Publish(Event{Type: DeckCreated, Owner: "alice", DeckID: "d"})
+	got = received(b.Subscribe(1, "alice", ""))
+	if len(got) != 3 || got[0].Type != Reset {
+		t.Fatalf("replayed %+v, want a reset first", got)
+	}
+}
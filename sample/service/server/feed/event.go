@@ -0,0 +1,43 @@
+package feed
+
+import (
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// EventType : kind of change streamed to the feed subscribers
+type EventType string
+
+const (
+	// DeckCreated : a Deck was created
+	DeckCreated EventType = "deck.created"
+	// DeckUpdated : a Deck was overwritten
+	DeckUpdated EventType = "deck.updated"
+	// DeckDeleted : a Deck was removed
+	DeckDeleted EventType = "deck.deleted"
+	// CardCreated : a Card was added to a Deck
+	CardCreated EventType = "card.created"
+	// CardDeleted : a Card was removed from a Deck
+	CardDeleted EventType = "card.deleted"
+	// Reset is sent first to a resuming subscriber whose Last-Event-ID is no
+	// longer buffered: some events were missed and state should be reloaded.
+	Reset EventType = "reset"
+)
+
+// Event is a single change notification.
+// ID is assigned by the Broker and is monotonically increasing.
+// Owner is the owner of the Deck, events are only streamed to it and to the
+// Members the Deck is shared with.
+type Event struct {
+	ID     uint64            `json:"id"`
+	Type   EventType         `json:"type"`
+	Time   time.Time         `json:"time"`
+	Owner  string            `json:"owner,omitempty"`
+	DeckID string            `json:"deck_id,omitempty"`
+	CardID string            `json:"card_id,omitempty"`
+	Deck   *clientModel.Deck `json:"deck,omitempty"`
+	Card   *clientModel.Card `json:"card,omitempty"`
+	// Members of the Deck when the change happened, not streamed.
+	Members []string `json:"-"`
+}
@@ -0,0 +1,167 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+// heartbeat is the interval between keep-alive messages sent to idle
+// subscribers, so that proxies do not close the connection.
+const heartbeat = 15 * time.Second
+
+// MakeHTTPHandler mounts the change feed into an http.Handler.
+//
+// GET     /events                          Server-Sent Events stream
+// GET     /events/ws                       WebSocket stream
+//
+// Both stream the events of the Decks of the caller, and of the Decks shared
+// with the caller, and accept an optional ?deck= filter. SSE clients resume through the
+// standard Last-Event-ID header, WebSocket clients through ?last_event_id=.
+func MakeHTTPHandler(b *Broker, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	r.Methods("GET").Path("/events").Handler(sseHandler{broker: b, logger: logger})
+	r.Methods("GET").Path("/events/ws").Handler(wsHandler{
+		broker: b,
+		logger: logger,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+	})
+	return r
+}
+
+func lastEventID(r *http.Request) (uint64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(v, 10, 64)
+}
+
+type sseHandler struct {
+	broker *Broker
+	logger log.Logger
+}
+
+func (h sseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	last, err := lastEventID(r)
+	if err != nil {
+		http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+		return
+	}
+	sub := h.broker.Subscribe(last, auth.Subject(r.Context()), r.URL.Query().Get("deck"))
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case e, ok := <-sub.C:
+			if !ok {
+				return // dropped for lagging behind, the client will resume
+			}
+			payload, err := json.Marshal(e)
+			if err != nil {
+				h.logger.Log("transport", "SSE", "err", err)
+				return
+			}
+			if e.Type == Reset {
+				_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, payload)
+			} else {
+				_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, payload)
+			}
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+type wsHandler struct {
+	broker   *Broker
+	logger   log.Logger
+	upgrader websocket.Upgrader
+}
+
+func (h wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	last, err := lastEventID(r)
+	if err != nil {
+		http.Error(w, "invalid last_event_id", http.StatusBadRequest)
+		return
+	}
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // the upgrader already replied with an HTTP error
+	}
+	defer conn.Close()
+
+	sub := h.broker.Subscribe(last, auth.Subject(r.Context()), r.URL.Query().Get("deck"))
+	defer sub.Close()
+
+	// The feed is one way, but reading is required to process control
+	// messages and to notice the peer going away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeat)); err != nil {
+				return
+			}
+		case e, ok := <-sub.C:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber lagging behind"),
+					time.Now().Add(time.Second))
+				return
+			}
+			if err := conn.WriteJSON(e); err != nil {
+				h.logger.Log("transport", "WebSocket", "err", err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+)
+
+type cardsResult struct {
+	cards []clientModel.Card
+	err   error
+}
+
+// cardLoader batches the Cards lookups of a single GraphQL request.
+//
+// Resolvers call Load, which only records the Deck ID and returns a thunk.
+// The executor resolves every field of a level before calling the thunks, so
+// the first thunk called fetches the Cards of all the recorded Decks at once
+// and the following ones are served from the cache.
+type cardLoader struct {
+	mtx     sync.Mutex
+	ctx     context.Context
+	s       server.SampleService
+	pending []string
+	cache   map[string]cardsResult
+}
+
+func newCardLoader(ctx context.Context, s server.SampleService) *cardLoader {
+	return &cardLoader{
+		ctx:   ctx,
+		s:     s,
+		cache: map[string]cardsResult{},
+	}
+}
+
+// Prime stores the Cards of a Deck already fetched by a parent resolver.
+func (l *cardLoader) Prime(d clientModel.Deck) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if _, ok := l.cache[d.ID]; !ok {
+		l.cache[d.ID] = cardsResult{cards: d.Cards}
+	}
+}
+
+// Load returns a thunk resolving to the Cards of the given Deck.
+func (l *cardLoader) Load(deckID string) func() (interface{}, error) {
+	l.mtx.Lock()
+	if _, ok := l.cache[deckID]; !ok {
+		l.pending = append(l.pending, deckID)
+	}
+	l.mtx.Unlock()
+
+	return func() (interface{}, error) {
+		l.mtx.Lock()
+		defer l.mtx.Unlock()
+		if len(l.pending) > 0 {
+			l.fetch(l.pending)
+			l.pending = nil
+		}
+		r := l.cache[deckID]
+		return r.cards, r.err
+	}
+}
+
+// fetch must be called with the lock held.
+func (l *cardLoader) fetch(ids []string) {
+	if len(ids) == 1 {
+		cards, err := l.s.GetCards(l.ctx, ids[0])
+		l.cache[ids[0]] = cardsResult{cards: cards, err: err}
+		return
+	}
+	// The service has no multi-Deck lookup, but a single GetDecks returns
+	// every Card.
+	decks, err := l.s.GetDecks(l.ctx)
+	byID := map[string]clientModel.Deck{}
+	for _, d := range decks {
+		byID[d.ID] = d
+	}
+	for _, id := range ids {
+		if _, ok := l.cache[id]; ok {
+			continue
+		}
+		switch d, ok := byID[id]; {
+		case err != nil:
+			l.cache[id] = cardsResult{err: err}
+		case !ok:
+			l.cache[id] = cardsResult{err: data.ErrNotFound}
+		default:
+			l.cache[id] = cardsResult{cards: d.Cards}
+		}
+	}
+}
@@ -0,0 +1,369 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+// defaultPageSize is the number of Decks returned by the decks query when
+// first is not given.
+const defaultPageSize = 20
+
+type loaderKey struct{}
+
+func loaderFrom(ctx context.Context) *cardLoader {
+	return ctx.Value(loaderKey{}).(*cardLoader)
+}
+
+// deckPage and searchResult fields are resolved by name.
+type deckPage struct {
+	Items       []clientModel.Deck
+	TotalCount  int
+	EndCursor   string
+	HasNextPage bool
+}
+
+type searchResult struct {
+	Decks []clientModel.Deck
+	Cards []clientModel.Card
+}
+
+// NewSchema builds the GraphQL schema resolving against the given service.
+func NewSchema(s server.SampleService) (gql.Schema, error) {
+	cardType := gql.NewObject(gql.ObjectConfig{
+		Name: "Card",
+		Fields: gql.Fields{
+			"id":       &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"first":    &gql.Field{Type: gql.String},
+			"second":   &gql.Field{Type: gql.String},
+			"tags":     &gql.Field{Type: gql.NewList(gql.NewNonNull(gql.String))},
+			"format":   &gql.Field{Type: gql.String},
+			"position": &gql.Field{Type: gql.Int},
+		},
+	})
+
+	deckType := gql.NewObject(gql.ObjectConfig{
+		Name: "Deck",
+		Fields: gql.Fields{
+			"id":     &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"name":   &gql.Field{Type: gql.String},
+			"tags":   &gql.Field{Type: gql.NewList(gql.NewNonNull(gql.String))},
+			"parent": &gql.Field{Type: gql.ID},
+			"cardCount": &gql.Field{
+				Type: gql.NewNonNull(gql.Int),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					thunk := loaderFrom(p.Context).Load(p.Source.(clientModel.Deck).ID)
+					return func() (interface{}, error) {
+						cards, err := thunk()
+						if err != nil {
+							return nil, err
+						}
+						return len(cards.([]clientModel.Card)), nil
+					}, nil
+				},
+			},
+			"cards": &gql.Field{
+				Type: gql.NewList(gql.NewNonNull(cardType)),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return loaderFrom(p.Context).Load(p.Source.(clientModel.Deck).ID), nil
+				},
+			},
+		},
+	})
+
+	deckPageType := gql.NewObject(gql.ObjectConfig{
+		Name: "DeckPage",
+		Fields: gql.Fields{
+			"items":       &gql.Field{Type: gql.NewList(gql.NewNonNull(deckType))},
+			"totalCount":  &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"endCursor":   &gql.Field{Type: gql.String},
+			"hasNextPage": &gql.Field{Type: gql.NewNonNull(gql.Boolean)},
+		},
+	})
+
+	searchResultType := gql.NewObject(gql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: gql.Fields{
+			"decks": &gql.Field{Type: gql.NewList(gql.NewNonNull(deckType))},
+			"cards": &gql.Field{Type: gql.NewList(gql.NewNonNull(cardType))},
+		},
+	})
+
+	cardInput := gql.NewInputObject(gql.InputObjectConfig{
+		Name: "CardInput",
+		Fields: gql.InputObjectConfigFieldMap{
+			"id":     &gql.InputObjectFieldConfig{Type: gql.NewNonNull(gql.ID)},
+			"first":  &gql.InputObjectFieldConfig{Type: gql.String},
+			"second": &gql.InputObjectFieldConfig{Type: gql.String},
+			"tags":   &gql.InputObjectFieldConfig{Type: gql.NewList(gql.NewNonNull(gql.String))},
+			"format": &gql.InputObjectFieldConfig{Type: gql.String},
+		},
+	})
+
+	deckInput := gql.NewInputObject(gql.InputObjectConfig{
+		Name: "DeckInput",
+		Fields: gql.InputObjectConfigFieldMap{
+			"id":     &gql.InputObjectFieldConfig{Type: gql.NewNonNull(gql.ID)},
+			"name":   &gql.InputObjectFieldConfig{Type: gql.String},
+			"cards":  &gql.InputObjectFieldConfig{Type: gql.NewList(gql.NewNonNull(cardInput))},
+			"tags":   &gql.InputObjectFieldConfig{Type: gql.NewList(gql.NewNonNull(gql.String))},
+			"parent": &gql.InputObjectFieldConfig{Type: gql.ID},
+		},
+	})
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"deck": &gql.Field{
+				Type: deckType,
+				Args: gql.FieldConfigArgument{
+					"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					d, err := getDeck(p.Context, s, p.Args["id"].(string))
+					if err == data.ErrNotFound {
+						return nil, nil
+					}
+					return d, err
+				},
+			},
+			"decks": &gql.Field{
+				Type: gql.NewNonNull(deckPageType),
+				Args: gql.FieldConfigArgument{
+					"first": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: defaultPageSize},
+					"after": &gql.ArgumentConfig{Type: gql.String},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					decks, err := s.GetDecks(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					l := loaderFrom(p.Context)
+					for _, d := range decks {
+						l.Prime(d)
+					}
+					after, _ := p.Args["after"].(string)
+					return paginate(decks, p.Args["first"].(int), after)
+				},
+			},
+			"card": &gql.Field{
+				Type: cardType,
+				Args: gql.FieldConfigArgument{
+					"deckId": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"id":     &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					c, err := s.GetCard(p.Context, p.Args["deckId"].(string), p.Args["id"].(string))
+					if err == data.ErrNotFound {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, err
+					}
+					return c, nil
+				},
+			},
+			"search": &gql.Field{
+				Type: gql.NewNonNull(searchResultType),
+				Args: gql.FieldConfigArgument{
+					"query": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					decks, err := s.GetDecks(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					l := loaderFrom(p.Context)
+					for _, d := range decks {
+						l.Prime(d)
+					}
+					return search(decks, p.Args["query"].(string)), nil
+				},
+			},
+		},
+	})
+
+	mutation := gql.NewObject(gql.ObjectConfig{
+		Name: "Mutation",
+		Fields: gql.Fields{
+			"postDeck": &gql.Field{
+				Type: deckType,
+				Args: gql.FieldConfigArgument{
+					"input": &gql.ArgumentConfig{Type: gql.NewNonNull(deckInput)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					d := deckFromInput(p.Args["input"])
+					if err := s.PostDeck(p.Context, mapper.FromClientDeck(d)); err != nil {
+						return nil, err
+					}
+					return getDeck(p.Context, s, d.ID)
+				},
+			},
+			"putDeck": &gql.Field{
+				Type: deckType,
+				Args: gql.FieldConfigArgument{
+					"id":    &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"input": &gql.ArgumentConfig{Type: gql.NewNonNull(deckInput)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					id := p.Args["id"].(string)
+					d := deckFromInput(p.Args["input"])
+					if err := s.PutDeck(p.Context, id, mapper.FromClientDeck(d)); err != nil {
+						return nil, err
+					}
+					return getDeck(p.Context, s, id)
+				},
+			},
+			"deleteDeck": &gql.Field{
+				Type: gql.NewNonNull(gql.Boolean),
+				Args: gql.FieldConfigArgument{
+					"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					if err := s.DeleteDeck(p.Context, p.Args["id"].(string)); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+			"postCard": &gql.Field{
+				Type: cardType,
+				Args: gql.FieldConfigArgument{
+					"deckId": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"input":  &gql.ArgumentConfig{Type: gql.NewNonNull(cardInput)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					deckID := p.Args["deckId"].(string)
+					c := cardFromInput(p.Args["input"])
+					if err := s.PostCard(p.Context, deckID, mapper.FromClientCard(c)); err != nil {
+						return nil, err
+					}
+					return s.GetCard(p.Context, deckID, c.ID)
+				},
+			},
+			"deleteCard": &gql.Field{
+				Type: gql.NewNonNull(gql.Boolean),
+				Args: gql.FieldConfigArgument{
+					"deckId": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"id":     &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					if err := s.DeleteCard(p.Context, p.Args["deckId"].(string), p.Args["id"].(string)); err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+// getDeck fetches a Deck and primes the loader with its Cards.
+func getDeck(ctx context.Context, s server.SampleService, id string) (interface{}, error) {
+	d, err := s.GetDeck(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	loaderFrom(ctx).Prime(d)
+	return d, nil
+}
+
+// paginate orders the Decks by ID and returns the page starting right after
+// the given cursor.
+func paginate(decks []clientModel.Deck, first int, after string) (deckPage, error) {
+	sort.Slice(decks, func(i, j int) bool { return decks[i].ID < decks[j].ID })
+	start := 0
+	if after != "" {
+		b, err := base64.RawURLEncoding.DecodeString(after)
+		if err != nil {
+			return deckPage{}, errInvalidCursor
+		}
+		last := string(b)
+		start = sort.Search(len(decks), func(i int) bool { return decks[i].ID > last })
+	}
+	if first < 0 {
+		first = 0
+	}
+	end := start + first
+	if end > len(decks) {
+		end = len(decks)
+	}
+	page := deckPage{
+		Items:       decks[start:end],
+		TotalCount:  len(decks),
+		HasNextPage: end < len(decks),
+	}
+	if end > start {
+		page.EndCursor = base64.RawURLEncoding.EncodeToString([]byte(decks[end-1].ID))
+	}
+	return page, nil
+}
+
+// search returns the Decks whose name and the Cards whose faces contain the
+// query, ignoring case.
+func search(decks []clientModel.Deck, query string) searchResult {
+	q := strings.ToLower(query)
+	res := searchResult{Decks: []clientModel.Deck{}, Cards: []clientModel.Card{}}
+	if q == "" {
+		return res
+	}
+	for _, d := range decks {
+		if strings.Contains(strings.ToLower(d.Name), q) {
+			res.Decks = append(res.Decks, d)
+		}
+		for _, c := range d.Cards {
+			if strings.Contains(strings.ToLower(c.First), q) || strings.Contains(strings.ToLower(c.Second), q) {
+				res.Cards = append(res.Cards, c)
+			}
+		}
+	}
+	return res
+}
+
+func cardFromInput(in interface{}) clientModel.Card {
+	m := in.(map[string]interface{})
+	c := clientModel.Card{ID: m["id"].(string)}
+	c.First, _ = m["first"].(string)
+	c.Second, _ = m["second"].(string)
+	c.Tags = tagsFromInput(m["tags"])
+	c.Format, _ = m["format"].(string)
+	return c
+}
+
+func deckFromInput(in interface{}) clientModel.Deck {
+	m := in.(map[string]interface{})
+	d := clientModel.Deck{ID: m["id"].(string)}
+	d.Name, _ = m["name"].(string)
+	d.Parent, _ = m["parent"].(string)
+	if cards, ok := m["cards"].([]interface{}); ok {
+		for _, c := range cards {
+			d.Cards = append(d.Cards, cardFromInput(c))
+		}
+	}
+	d.Tags = tagsFromInput(m["tags"])
+	return d
+}
+
+func tagsFromInput(in interface{}) []string {
+	var tags []string
+	if list, ok := in.([]interface{}); ok {
+		for _, t := range list {
+			tags = append(tags, t.(string))
+		}
+	}
+	return tags
+}
@@ -0,0 +1,99 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-kit/kit/log"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// MakeHTTPHandler serves the GraphQL schema over HTTP.
+//
+// POST    /graphql                         {"query": ..., "variables": ..., "operationName": ...}
+// GET     /graphql?query=...               queries only
+func MakeHTTPHandler(s server.SampleService, logger log.Logger) (http.Handler, error) {
+	schema, err := NewSchema(s)
+	if err != nil {
+		return nil, err
+	}
+	return &handler{schema: schema, service: s, logger: logger}, nil
+}
+
+type handler struct {
+	schema  gql.Schema
+	service server.SampleService
+	logger  log.Logger
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	switch r.Method {
+	case "GET":
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				http.Error(w, "invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+	case "POST":
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.Method == "GET" && hasMutation(req.Query) {
+		// GET must stay safe: caches and prefetchers may replay it.
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "mutations require POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), loaderKey{}, newCardLoader(r.Context(), h.service))
+	result := gql.Do(gql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		h.logger.Log("transport", "GraphQL", "err", err)
+	}
+}
+
+func hasMutation(query string) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false // reported by the executor
+	}
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok && op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
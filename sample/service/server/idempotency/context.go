@@ -0,0 +1,37 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the idempotency key.
+func NewContext(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the idempotency key carried by ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	key, _ := ctx.Value(contextKey{}).(string)
+	return key
+}
+
+// NewKey returns a random idempotency key.
+func NewKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HTTPClientBefore is a go-kit http RequestFunc sending the idempotency key
+// of ctx, if any, with the outgoing request.
+func HTTPClientBefore(ctx context.Context, r *http.Request) context.Context {
+	if key := FromContext(ctx); key != "" {
+		r.Header.Set(KeyHeader, key)
+	}
+	return ctx
+}
@@ -0,0 +1,104 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrMismatch is returned when a key is reused with a different request.
+var ErrMismatch = errors.New("idempotency key reused with a different request")
+
+// Response is a stored response, replayed to the retries of its request.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Store keeps the responses of idempotent requests in memory for a TTL.
+type Store struct {
+	ttl time.Duration
+
+	mtx       sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// entry is a request in flight until done is closed, then holds its
+// response until expires. A nil response means the request is to be run
+// again.
+type entry struct {
+	fingerprint string
+	done        chan struct{}
+	response    *Response
+	expires     time.Time
+}
+
+// sweepEvery is the period of the removal of expired responses.
+const sweepEvery = time.Minute
+
+// NewStore returns a Store keeping responses for ttl.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		entries: map[string]*entry{},
+		now:     time.Now,
+	}
+}
+
+// begin returns the entry of key. The caller is its leader, which must run
+// the request then call finish, when the key was unknown or expired.
+func (s *Store) begin(key string, fingerprint string) (e *entry, leader bool, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := s.now()
+	s.sweep(now)
+	if e, ok := s.entries[key]; ok && !e.expired(now) {
+		if e.fingerprint != fingerprint {
+			return nil, false, ErrMismatch
+		}
+		return e, false, nil
+	}
+	e = &entry{fingerprint: fingerprint, done: make(chan struct{})}
+	s.entries[key] = e
+	return e, true, nil
+}
+
+// finish stores the response of the request of e, or forgets e when
+// response is nil, and wakes up the retries waiting for it.
+func (s *Store) finish(key string, e *entry, response *Response) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if response == nil {
+		delete(s.entries, key)
+	} else {
+		e.response = response
+		e.expires = s.now().Add(s.ttl)
+	}
+	close(e.done)
+}
+
+func (e *entry) expired(now time.Time) bool {
+	select {
+	case <-e.done:
+		return e.response == nil || !now.Before(e.expires)
+	default:
+		return false
+	}
+}
+
+// sweep drops the expired responses. It must be called with the lock held.
+func (s *Store) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepEvery {
+		return
+	}
+	s.lastSweep = now
+	for k, e := range s.entries {
+		if e.expired(now) {
+			delete(s.entries, k)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+const (
+	// KeyHeader carries the client chosen key identifying the retries of a
+	// request.
+	KeyHeader = "Idempotency-Key"
+	// ReplayedHeader is set on the responses replayed from the Store.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxKeyLength bounds the length of idempotency keys.
+const maxKeyLength = 255
+
+// HTTPHandler makes the POST requests carrying an Idempotency-Key header
+// idempotent. The first response to a key (server errors aside) is stored
+// and replayed to the retries of the same request, concurrent retries
+// waiting for the first one to complete. Reusing a key for a different
+// method, path or body answers 422 Unprocessable Entity. Keys are scoped
+// by principal.
+//
+// Bodies are held in memory to be compared: maxBody returns the size limit
+// of the body of r, past which it answers 413 Request Entity Too Large.
+func HTTPHandler(s *Store, maxBody func(r *http.Request) int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(KeyHeader)
+		if r.Method != "POST" || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			encodeError(w, http.StatusBadRequest, "invalid idempotency key")
+			return
+		}
+		if r.ContentLength > maxBody(r) {
+			encodeError(w, http.StatusRequestEntityTooLarge, server.ErrPayloadTooLarge.Error())
+			return
+		}
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBody(r)))
+		if _, ok := err.(*http.MaxBytesError); ok {
+			encodeError(w, http.StatusRequestEntityTooLarge, server.ErrPayloadTooLarge.Error())
+			return
+		}
+		if err != nil {
+			encodeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		key = auth.Subject(r.Context()) + "\x00" + key
+		fingerprint := fingerprint(r, body)
+
+		for {
+			e, leader, err := s.begin(key, fingerprint)
+			if err != nil {
+				encodeError(w, http.StatusUnprocessableEntity, err.Error())
+				return
+			}
+			if leader {
+				outer := w.Header().Clone()
+				rec := &recorder{ResponseWriter: w, statusCode: http.StatusOK}
+				defer func() {
+					// Server errors and panics are not stored, retries run again.
+					p := recover()
+					var response *Response
+					if p == nil && rec.statusCode < 500 {
+						response = &Response{StatusCode: rec.statusCode, Header: added(outer, w.Header()), Body: rec.body.Bytes()}
+					}
+					s.finish(key, e, response)
+					if p != nil {
+						panic(p)
+					}
+				}()
+				next.ServeHTTP(rec, r)
+				return
+			}
+			select {
+			case <-e.done:
+			case <-r.Context().Done():
+				return
+			}
+			if e.response != nil {
+				replay(w, e.response)
+				return
+			}
+		}
+	})
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// added returns the headers of after not in before, leaving out those set
+// by the outer handlers, such as the request ID.
+func added(before http.Header, after http.Header) http.Header {
+	h := http.Header{}
+	for k, v := range after {
+		if _, ok := before[k]; !ok {
+			h[k] = append([]string(nil), v...)
+		}
+	}
+	return h
+}
+
+func replay(w http.ResponseWriter, response *Response) {
+	for k, v := range response.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(response.StatusCode)
+	w.Write(response.Body)
+}
+
+func encodeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": msg,
+	})
+}
+
+// recorder copies the response written to its ResponseWriter.
+type recorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.statusCode, r.wroteHeader = code, true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,138 @@
+package idempotency
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+// counting answers 201 with the number of requests it served, or 503 to
+// bodies saying "fail".
+type counting struct {
+	calls   int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *counting) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := atomic.AddInt32(&c.calls, 1)
+	if c.started != nil {
+		c.started <- struct{}{}
+		<-c.release
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	if string(body) == "fail" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Location", "/decks/d")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte{'0' + byte(n)})
+}
+
+func limit(n int64) func(*http.Request) int64 {
+	return func(*http.Request) int64 { return n }
+}
+
+func post(h http.Handler, user, path, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", path, strings.NewReader(body))
+	r.Header.Set(KeyHeader, key)
+	if user != "" {
+		r = r.WithContext(auth.NewContext(r.Context(), auth.Principal{Subject: user}))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestReplay(t *testing.T) {
+	next := &counting{}
+	h := HTTPHandler(NewStore(time.Hour), limit(1024), next)
+
+	first := post(h, "alice", "/decks", "k", "{}")
+	retry := post(h, "alice", "/decks", "k", "{}")
+	if first.Code != http.StatusCreated || retry.Code != http.StatusCreated || next.calls != 1 {
+		t.Fatalf("answered %d then %d, ran %d times", first.Code, retry.Code, next.calls)
+	}
+	if retry.Body.String() != "1" || retry.Header().Get("Location") != "/decks/d" || retry.Header().Get(ReplayedHeader) != "true" {
+		t.Fatalf("replayed %q %v", retry.Body, retry.Header())
+	}
+	if first.Header().Get(ReplayedHeader) != "" {
+		t.Fatal("first response marked as replayed")
+	}
+
+	if w := post(h, "alice", "/decks", "k", `{"id":"other"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused with another body: %d", w.Code)
+	}
+	if w := post(h, "alice", "/decks/d/cards", "k", "{}"); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused with another path: %d", w.Code)
+	}
+	// Keys are scoped by principal.
+	if w := post(h, "bob", "/decks", "k", "{}"); w.Body.String() != "2" {
+		t.Fatalf("bob got the response of alice: %q", w.Body)
+	}
+}
+
+func TestServerErrorsAreNotStored(t *testing.T) {
+	next := &counting{}
+	h := HTTPHandler(NewStore(time.Hour), limit(1024), next)
+	post(h, "alice", "/decks", "k", "fail")
+	if w := post(h, "alice", "/decks", "k", "fail"); w.Code != http.StatusServiceUnavailable || next.calls != 2 {
+		t.Fatalf("retry answered %d, ran %d times", w.Code, next.calls)
+	}
+}
+
+func TestConcurrentRetriesWait(t *testing.T) {
+	next := &counting{started: make(chan struct{}), release: make(chan struct{})}
+	h := HTTPHandler(NewStore(time.Hour), limit(1024), next)
+
+	var wg sync.WaitGroup
+	codes := make([]int, 3)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = post(h, "alice", "/decks", "k", "{}").Code
+		}(i)
+	}
+	<-next.started
+	time.Sleep(10 * time.Millisecond) // let the retries queue up
+	close(next.release)
+	wg.Wait()
+	if next.calls != 1 {
+		t.Fatalf("ran %d times", next.calls)
+	}
+	for i, code := range codes {
+		if code != http.StatusCreated {
+			t.Errorf("request %d answered %d", i, code)
+		}
+	}
+}
+
+func TestBodiesAreBounded(t *testing.T) {
+	next := &counting{}
+	h := HTTPHandler(NewStore(time.Hour), limit(4), next)
+	if w := post(h, "alice", "/decks", "k", "12345"); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("declared length over the limit: %d", w.Code)
+	}
+
+	r := httptest.NewRequest("POST", "/decks", ioutil.NopCloser(strings.NewReader("12345")))
+	r.ContentLength = -1 // chunked
+	r.Header.Set(KeyHeader, "k")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge || next.calls != 0 {
+		t.Fatalf("streamed body over the limit: %d, ran %d times", w.Code, next.calls)
+	}
+
+	if w := post(h, "alice", "/decks", "k", "1234"); w.Code != http.StatusCreated {
+		t.Fatalf("body at the limit: %d", w.Code)
+	}
+}
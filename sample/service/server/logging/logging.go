@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/requestid"
+)
+
+// NewLogger returns a logger writing to w in the given format, logfmt or
+// json, and dropping lines below the given level: debug, info, warn or
+// error. Lines logged without a level are considered info.
+func NewLogger(w io.Writer, format, lvl string) (log.Logger, error) {
+	var logger log.Logger
+	switch format {
+	case "logfmt":
+		logger = log.NewLogfmtLogger(log.NewSyncWriter(w))
+	case "json":
+		logger = log.NewJSONLogger(log.NewSyncWriter(w))
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	var allow level.Option
+	switch lvl {
+	case "debug":
+		allow = level.AllowDebug()
+	case "info":
+		allow = level.AllowInfo()
+	case "warn":
+		allow = level.AllowWarn()
+	case "error":
+		allow = level.AllowError()
+	default:
+		return nil, fmt.Errorf("unknown log level %q", lvl)
+	}
+	logger = level.NewFilter(logger, allow)
+	return level.NewInjector(logger, level.InfoValue()), nil
+}
+
+// FromContext returns logger annotated with the request ID and the
+// authenticated subject found in ctx.
+func FromContext(ctx context.Context, logger log.Logger) log.Logger {
+	if id := requestid.FromContext(ctx); id != "" {
+		logger = log.With(logger, "request_id", id)
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		logger = log.With(logger, "subject", p.Subject)
+	}
+	return logger
+}
+
+// Sampler keeps one out of every n events. A nil Sampler keeps them all.
+type Sampler struct {
+	n     uint64
+	count uint64
+}
+
+// NewSampler returns a Sampler keeping one event out of n; n <= 1 keeps
+// every event.
+func NewSampler(n int) *Sampler {
+	if n <= 1 {
+		return nil
+	}
+	return &Sampler{n: uint64(n)}
+}
+
+// Sample reports whether the current event should be kept.
+func (s *Sampler) Sample() bool {
+	if s == nil {
+		return true
+	}
+	return atomic.AddUint64(&s.count, 1)%s.n == 1
+}
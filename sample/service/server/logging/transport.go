@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/go-kit/kit/transport"
+)
+
+// NewErrorHandler returns a go-kit transport ErrorHandler logging errors
+// with the request ID of their context.
+func NewErrorHandler(logger log.Logger) transport.ErrorHandler {
+	return errorHandler{logger: logger}
+}
+
+type errorHandler struct {
+	logger log.Logger
+}
+
+func (h errorHandler) Handle(ctx context.Context, err error) {
+	level.Error(FromContext(ctx, h.logger)).Log("err", err)
+}
+
+// AccessLog logs one line per HTTP request served by next. Successful GET
+// requests are only logged when sampler keeps them.
+func AccessLog(logger log.Logger, sampler *Sampler, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		begin := time.Now()
+		rw := &responseWriter{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rw, r)
+		if r.Method == http.MethodGet && rw.code < http.StatusBadRequest && !sampler.Sample() {
+			return
+		}
+		l := FromContext(r.Context(), logger)
+		switch {
+		case rw.code >= http.StatusInternalServerError:
+			l = level.Error(l)
+		case rw.code >= http.StatusBadRequest:
+			l = level.Warn(l)
+		default:
+			l = level.Info(l)
+		}
+		l.Log("http_method", r.Method, "path", r.URL.Path, "status", rw.code, "size", rw.size, "took", time.Since(begin), "remote", r.RemoteAddr)
+	})
+}
+
+// responseWriter records the status code and size of a response while
+// keeping the streaming (SSE) and hijacking (WebSocket) capabilities of the
+// underlying ResponseWriter.
+type responseWriter struct {
+	http.ResponseWriter
+	code        int
+	size        int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.code = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	w.code = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
@@ -11,14 +11,19 @@ func ToClientCard(input model.Card) client.Card {
 		ID:     input.ID,
 		First:  input.First,
 		Second: input.Second,
+		Tags:   input.Tags,
+		Format: input.Format,
 	}
 }
 
-// ToClientCards : Card model object to Card client object (list version)
+// ToClientCards : Card model object to Card client object (list version),
+// along with their positions
 func ToClientCards(inputList []model.Card) []client.Card {
 	var res []client.Card
-	for _, val := range inputList {
-		res = append(res, ToClientCard(val))
+	for i, val := range inputList {
+		c := ToClientCard(val)
+		c.Position = i
+		res = append(res, c)
 	}
 	return res
 }
@@ -26,9 +31,12 @@ func ToClientCards(inputList []model.Card) []client.Card {
 // ToClientDeck : Deck model object to Deck client object
 func ToClientDeck(input model.Deck) client.Deck {
 	return client.Deck{
-		ID:    input.ID,
-		Name:  input.Name,
-		Cards: ToClientCards(input.Cards),
+		ID:     input.ID,
+		Owner:  input.Owner,
+		Name:   input.Name,
+		Cards:  ToClientCards(input.Cards),
+		Tags:   input.Tags,
+		Parent: input.Parent,
 	}
 }
 
@@ -47,6 +55,8 @@ func FromClientCard(input client.Card) model.Card {
 		ID:     input.ID,
 		First:  input.First,
 		Second: input.Second,
+		Tags:   input.Tags,
+		Format: input.Format,
 	}
 }
 
@@ -62,9 +72,12 @@ func FromClientCards(inputList []client.Card) []model.Card {
 // FromClientDeck : Deck client object to Deck model object
 func FromClientDeck(input client.Deck) model.Deck {
 	return model.Deck{
-		ID:    input.ID,
-		Name:  input.Name,
-		Cards: FromClientCards(input.Cards),
+		ID:     input.ID,
+		Owner:  input.Owner,
+		Name:   input.Name,
+		Cards:  FromClientCards(input.Cards),
+		Tags:   input.Tags,
+		Parent: input.Parent,
 	}
 }
 
@@ -0,0 +1,98 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the media API. Blobs are downloaded with plain GET
+// requests, served with their ranges rather than through an endpoint.
+type Endpoints struct {
+	UploadEndpoint  endpoint.Endpoint
+	CollectEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		UploadEndpoint:  MakeUploadEndpoint(s),
+		CollectEndpoint: MakeCollectEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+// Collect is only served by the admin address.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		UploadEndpoint:  httptransport.NewClient("POST", tgt, encodeUploadRequest, decodeUploadResponse, options...).Endpoint(),
+		CollectEndpoint: httptransport.NewClient("POST", tgt, encodeCollectRequest, decodeCollectResponse, options...).Endpoint(),
+	}, nil
+}
+
+// Upload stores content remotely. Primarily useful in a client.
+func (e Endpoints) Upload(ctx context.Context, filename string, content io.Reader) (clientModel.Media, error) {
+	response, err := e.UploadEndpoint(ctx, clientRequest.UploadMedia{Filename: filename, Content: content})
+	if err != nil {
+		return clientModel.Media{}, err
+	}
+	resp, ok := response.(clientResponse.UploadMedia)
+	if !ok {
+		return clientModel.Media{}, ErrUnexpectedResponse
+	}
+	return resp.Media, resp.Err
+}
+
+// Collect implements Service. Primarily useful in a client.
+func (e Endpoints) Collect(ctx context.Context) (int, error) {
+	response, err := e.CollectEndpoint(ctx, clientRequest.CollectMedia{})
+	if err != nil {
+		return 0, err
+	}
+	resp, ok := response.(clientResponse.CollectMedia)
+	if !ok {
+		return 0, ErrUnexpectedResponse
+	}
+	return resp.Removed, resp.Err
+}
+
+// MakeUploadEndpoint returns an endpoint via the passed service.
+func MakeUploadEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.UploadMedia)
+		m, e := s.Upload(ctx, req.Content)
+		return clientResponse.UploadMedia{Media: m, Err: e}, e
+	}
+}
+
+// MakeCollectEndpoint returns an endpoint via the passed service.
+func MakeCollectEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		n, e := s.Collect(ctx)
+		return clientResponse.CollectMedia{Removed: n, Err: e}, e
+	}
+}
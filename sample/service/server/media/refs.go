@@ -0,0 +1,18 @@
+package media
+
+import "regexp"
+
+// RefPrefix starts the references to blobs from the faces of Cards, such
+// as "media:3a7bd3e2...", written anywhere in the text.
+const RefPrefix = "media:"
+
+var refPattern = regexp.MustCompile(RefPrefix + `([0-9a-f]{64})`)
+
+// Refs returns the hashes of the blobs text references.
+func Refs(text string) []string {
+	var hashes []string
+	for _, m := range refPattern.FindAllStringSubmatch(text, -1) {
+		hashes = append(hashes, m[1])
+	}
+	return hashes
+}
@@ -0,0 +1,89 @@
+package media
+
+import (
+	"context"
+	"io"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+)
+
+// Service stores the images and audio Cards reference from their faces.
+type Service interface {
+	// Upload stores content, once for a given content.
+	Upload(ctx context.Context, content io.Reader) (clientModel.Media, error)
+	// Open returns the content of a blob, to be closed by the caller.
+	Open(ctx context.Context, hash string) (io.ReadSeekCloser, Blob, error)
+	// Collect removes the blobs no Card references, and returns how many.
+	Collect(ctx context.Context) (int, error)
+}
+
+// GCGrace is the age from which unreferenced blobs are collected, leaving
+// time to reference the blobs just uploaded.
+const GCGrace = time.Hour
+
+type mediaService struct {
+	store *Store
+	repo  data.SampleRepository
+}
+
+// NewService Service Constructor. Blobs are kept in store, the Cards of
+// every owner in repo referencing them.
+func NewService(store *Store, repo data.SampleRepository) Service {
+	return &mediaService{
+		store: store,
+		repo:  repo,
+	}
+}
+
+// toMedia : Blob to Media client object
+func toMedia(b Blob) clientModel.Media {
+	return clientModel.Media{
+		Hash:        b.Hash,
+		ContentType: b.ContentType,
+		Size:        b.Size,
+		Ref:         RefPrefix + b.Hash,
+		URL:         "/media/" + b.Hash,
+	}
+}
+
+func (s *mediaService) Upload(ctx context.Context, content io.Reader) (clientModel.Media, error) {
+	b, err := s.store.Put(content)
+	if err != nil {
+		return clientModel.Media{}, err
+	}
+	return toMedia(b), nil
+}
+
+func (s *mediaService) Open(ctx context.Context, hash string) (io.ReadSeekCloser, Blob, error) {
+	f, b, err := s.store.Open(hash)
+	if err != nil {
+		return nil, Blob{}, err
+	}
+	return f, b, nil
+}
+
+// references returns the hashes of the blobs the Cards of every owner
+// reference.
+func (s *mediaService) references(ctx context.Context) (map[string]bool, error) {
+	decks, err := s.repo.GetAllDecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	referenced := map[string]bool{}
+	for _, d := range decks {
+		for _, c := range d.Cards {
+			for _, hash := range append(Refs(c.First), Refs(c.Second)...) {
+				referenced[hash] = true
+			}
+		}
+	}
+	return referenced, nil
+}
+
+func (s *mediaService) Collect(ctx context.Context) (int, error) {
+	return s.store.Collect(func() (map[string]bool, error) {
+		return s.references(ctx)
+	}, GCGrace)
+}
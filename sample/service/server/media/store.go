@@ -0,0 +1,186 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+)
+
+// ErrUnsupportedType : blob being neither an image nor audio
+var ErrUnsupportedType = errors.New("unsupported media type")
+
+// hashPattern matches the hashes naming blobs: hex SHA-256 sums.
+var hashPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Blob describes a stored blob.
+type Blob struct {
+	Hash        string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// Store keeps blobs on local disk, named after the SHA-256 sum of their
+// content: uploading the same content twice stores it once. Blobs live in
+// dir/ab/abcdef..., uploads in progress in dir as temporary files.
+type Store struct {
+	dir     string
+	maxSize int64
+	// mtx serializes the storage of blobs with their collection, so that
+	// a blob stored or refreshed by Put is never removed by a Collect
+	// which read its references before.
+	mtx sync.Mutex
+}
+
+// NewStore returns a Store in dir, created when missing, accepting blobs
+// of at most maxSize bytes.
+func NewStore(dir string, maxSize int64) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, maxSize: maxSize}, nil
+}
+
+func (s *Store) path(hash string) string {
+	return filepath.Join(s.dir, hash[:2], hash)
+}
+
+// sniff returns the type of the content of f, found from its first bytes.
+func sniff(f *os.File) (string, error) {
+	head := make([]byte, 512)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+// supported tells whether blobs of contentType are accepted.
+func supported(contentType string) bool {
+	contentType = strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	return strings.HasPrefix(contentType, "image/") || strings.HasPrefix(contentType, "audio/") ||
+		contentType == "application/ogg"
+}
+
+// Put stores the content of r, failing with server.ErrPayloadTooLarge past
+// the size limit and ErrUnsupportedType for other than images and audio.
+// Storing known content again only refreshes its modification time.
+func (s *Store) Put(r io.Reader) (Blob, error) {
+	tmp, err := ioutil.TempFile(s.dir, ".upload-")
+	if err != nil {
+		return Blob{}, err
+	}
+	defer os.Remove(tmp.Name()) // once renamed, a no-op
+	defer tmp.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return Blob{}, err
+	}
+	if n > s.maxSize {
+		return Blob{}, server.ErrPayloadTooLarge
+	}
+	contentType, err := sniff(tmp)
+	if err != nil {
+		return Blob{}, err
+	}
+	if !supported(contentType) {
+		return Blob{}, ErrUnsupportedType
+	}
+	if err := tmp.Close(); err != nil {
+		return Blob{}, err
+	}
+
+	hash := hex.EncodeToString(h.Sum(nil))
+	path := s.path(hash)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := time.Now()
+	if _, err := os.Stat(path); err == nil {
+		return Blob{Hash: hash, ContentType: contentType, Size: n, ModTime: now}, os.Chtimes(path, now, now)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Blob{}, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return Blob{}, err
+	}
+	return Blob{Hash: hash, ContentType: contentType, Size: n, ModTime: now}, nil
+}
+
+// Open returns the content of the blob named hash, to be closed by the
+// caller, failing with data.ErrNotFound for unknown blobs.
+func (s *Store) Open(hash string) (*os.File, Blob, error) {
+	if !hashPattern.MatchString(hash) {
+		return nil, Blob{}, data.ErrNotFound
+	}
+	f, err := os.Open(s.path(hash))
+	if os.IsNotExist(err) {
+		return nil, Blob{}, data.ErrNotFound
+	}
+	if err != nil {
+		return nil, Blob{}, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, Blob{}, err
+	}
+	contentType, err := sniff(f)
+	if err != nil {
+		f.Close()
+		return nil, Blob{}, err
+	}
+	return f, Blob{Hash: hash, ContentType: contentType, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Collect removes the blobs not in the set returned by references, along
+// with abandoned uploads, as long as they were not written within grace:
+// blobs just uploaded are not referenced yet. references is called once,
+// with Put held off until the blobs are removed: blobs stored or refreshed
+// after the set was taken are newer than grace. It returns the number of
+// blobs removed.
+func (s *Store) Collect(references func() (map[string]bool, error), grace time.Duration) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	referenced, err := references()
+	if err != nil {
+		return 0, err
+	}
+	before := time.Now().Add(-grace)
+	removed := 0
+	err = filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !info.ModTime().Before(before) {
+			return nil
+		}
+		name := info.Name()
+		switch {
+		case strings.HasPrefix(name, ".upload-"):
+			return os.Remove(path)
+		case hashPattern.MatchString(name) && !referenced[name]:
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			os.Remove(filepath.Dir(path)) // only once empty
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
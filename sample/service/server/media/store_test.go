@@ -0,0 +1,90 @@
+package media
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// png returns the content of a distinct PNG blob.
+func png(name string) *strings.Reader {
+	return strings.NewReader("\x89PNG\r\n\x1a\n" + name)
+}
+
+// age sets the modification time of the blob b to d ago.
+func age(t *testing.T, s *Store, b Blob, d time.Duration) {
+	t.Helper()
+	then := time.Now().Add(-d)
+	if err := os.Chtimes(s.path(b.Hash), then, then); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobs := map[string]Blob{}
+	for _, name := range []string{"referenced", "orphan", "recent"} {
+		if blobs[name], err = s.Put(png(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	age(t, s, blobs["referenced"], 2*time.Hour)
+	age(t, s, blobs["orphan"], 2*time.Hour)
+
+	calls := 0
+	removed, err := s.Collect(func() (map[string]bool, error) {
+		calls++
+		return map[string]bool{blobs["referenced"].Hash: true}, nil
+	}, time.Hour)
+	if err != nil || removed != 1 || calls != 1 {
+		t.Fatalf("removed %d blobs (%v) reading references %d times, want 1 and once", removed, err, calls)
+	}
+	for name, b := range blobs {
+		f, _, err := s.Open(b.Hash)
+		if f != nil {
+			f.Close()
+		}
+		if (err == nil) != (name != "orphan") {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestPutWaitsForCollect(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.Put(png("old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	age(t, s, b, 2*time.Hour)
+
+	// The blob is uploaded again once its references are read: the upload
+	// must wait for the collection, then store it anew.
+	put := make(chan error)
+	s.Collect(func() (map[string]bool, error) {
+		go func() {
+			_, err := s.Put(png("old"))
+			put <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+		return map[string]bool{}, nil
+	}, time.Hour)
+	if err := <-put; err != nil {
+		t.Fatal(err)
+	}
+	f, got, err := s.Open(b.Hash)
+	if err != nil {
+		t.Fatalf("uploaded blob removed: %v", err)
+	}
+	f.Close()
+	if time.Since(got.ModTime) > time.Minute {
+		t.Fatalf("uploaded blob not refreshed: %v", got.ModTime)
+	}
+}
@@ -0,0 +1,218 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+	// ErrMissingFile : upload not being a multipart form with a file part
+	ErrMissingFile = errors.New("missing file part")
+)
+
+// FilePart is the name of the multipart form part holding uploads.
+const FilePart = "file"
+
+// MakeHTTPHandler mounts all of the media endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /media                          uploads an image or audio file (multipart, part "file")
+	// GET     /media/:hash                    downloads a blob, or ranges of it
+
+	r.Methods("POST").Path("/media").Handler(httptransport.NewServer(
+		e.UploadEndpoint,
+		decodeUploadRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET", "HEAD").Path("/media/{hash}").Handler(serveBlob(s, logger))
+	return r
+}
+
+// MakeAdminHandler mounts the media maintenance endpoints into an
+// http.Handler, for the admin address.
+func MakeAdminHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /media/gc                       removes the blobs no card references
+
+	r.Methods("POST").Path("/media/gc").Handler(httptransport.NewServer(
+		e.CollectEndpoint,
+		decodeCollectRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+// serveBlob answers blobs with their ranges and conditional requests.
+// Blobs never change, so that they are cached for good.
+func serveBlob(s Service, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hash, ok := mux.Vars(r)["hash"]
+		if !ok {
+			encodeError(r.Context(), ErrBadRouting, w)
+			return
+		}
+		f, b, err := s.Open(r.Context(), hash)
+		if err != nil {
+			if err != data.ErrNotFound {
+				logger.Log("err", err)
+			}
+			encodeError(r.Context(), err, w)
+			return
+		}
+		defer f.Close()
+		w.Header().Set("Content-Type", b.ContentType)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("ETag", `"`+b.Hash+`"`)
+		w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+		http.ServeContent(w, r, "", b.ModTime, f)
+	})
+}
+
+// decodeUploadRequest streams the file part of the form, without buffering
+// it: it is read by the endpoint.
+func decodeUploadRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	form, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrMissingFile
+	}
+	for {
+		part, err := form.NextPart()
+		if err == io.EOF {
+			return nil, ErrMissingFile
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == FilePart {
+			return clientRequest.UploadMedia{Filename: part.FileName(), Content: part}, nil
+		}
+	}
+}
+
+func decodeCollectRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return clientRequest.CollectMedia{}, nil
+}
+
+func encodeUploadRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/media")
+	r := request.(clientRequest.UploadMedia)
+	req.URL.Path = "/media"
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	part, err := form.CreateFormFile(FilePart, r.Filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r.Content); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.ContentLength = int64(buf.Len())
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func encodeCollectRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/media/gc")
+	req.URL.Path = "/media/gc"
+	return nil
+}
+
+func decodeUploadResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.UploadMedia
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeCollectResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.CollectMedia
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrUnsupportedType, ErrMissingFile} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrUnsupportedType:
+		return http.StatusUnsupportedMediaType
+	case ErrMissingFile:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
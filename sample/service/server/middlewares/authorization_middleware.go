@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/sharing"
+)
+
+// AuthorizationMiddleware : Check the role of the caller on the Decks of
+// other users referenced by input SampleService calls. Viewers read,
+// editors also change the Deck and its Cards, admins also delete it.
+// Decks the caller is not a member of are not found, while insufficient
+// roles are forbidden. GetDecks adds the Decks shared with the caller.
+func AuthorizationMiddleware(acl sharing.Authorizer) Middleware {
+	return func(next server.SampleService) server.SampleService {
+		return &authorizationMiddleware{
+			next: next,
+			acl:  acl,
+		}
+	}
+}
+
+type authorizationMiddleware struct {
+	next server.SampleService
+	acl  sharing.Authorizer
+}
+
+// authorize checks the caller holds at least role on the referenced Deck.
+func (mw authorizationMiddleware) authorize(ctx context.Context, ref string, role string) error {
+	owner, id := server.ParseDeckRef(ctx, ref)
+	caller := auth.Subject(ctx)
+	if owner == caller {
+		return nil
+	}
+	got := mw.acl.Role(ctx, owner, id, caller)
+	if got == "" {
+		return data.ErrNotFound
+	}
+	if !sharing.Allows(got, role) {
+		return server.ErrForbidden
+	}
+	return nil
+}
+
+func (mw authorizationMiddleware) PostDeck(ctx context.Context, p model.Deck) error {
+	return mw.next.PostDeck(ctx, p)
+}
+
+func (mw authorizationMiddleware) GetDeck(ctx context.Context, id string) (clientModel.Deck, error) {
+	if err := mw.authorize(ctx, id, clientModel.RoleViewer); err != nil {
+		return clientModel.Deck{}, err
+	}
+	return mw.next.GetDeck(ctx, id)
+}
+
+func (mw authorizationMiddleware) PutDeck(ctx context.Context, id string, p model.Deck) error {
+	if err := mw.authorize(ctx, id, clientModel.RoleEditor); err != nil {
+		return err
+	}
+	return mw.next.PutDeck(ctx, id, p)
+}
+
+func (mw authorizationMiddleware) GetDecks(ctx context.Context) ([]clientModel.Deck, error) {
+	decks, err := mw.next.GetDecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range mw.acl.SharedWith(ctx, auth.Subject(ctx)) {
+		d, err := mw.next.GetDeck(ctx, ref)
+		if err != nil {
+			continue // deleted meanwhile
+		}
+		decks = append(decks, d)
+	}
+	return decks, nil
+}
+
+func (mw authorizationMiddleware) DeleteDeck(ctx context.Context, id string) error {
+	if err := mw.authorize(ctx, id, clientModel.RoleAdmin); err != nil {
+		return err
+	}
+	if err := mw.next.DeleteDeck(ctx, id); err != nil {
+		return err
+	}
+	owner, deckID := server.ParseDeckRef(ctx, id)
+	mw.acl.Forget(ctx, owner, deckID)
+	return nil
+}
+
+func (mw authorizationMiddleware) GetCards(ctx context.Context, DeckID string) ([]clientModel.Card, error) {
+	if err := mw.authorize(ctx, DeckID, clientModel.RoleViewer); err != nil {
+		return nil, err
+	}
+	return mw.next.GetCards(ctx, DeckID)
+}
+
+func (mw authorizationMiddleware) GetCard(ctx context.Context, DeckID string, CardID string) (clientModel.Card, error) {
+	if err := mw.authorize(ctx, DeckID, clientModel.RoleViewer); err != nil {
+		return clientModel.Card{}, err
+	}
+	return mw.next.GetCard(ctx, DeckID, CardID)
+}
+
+func (mw authorizationMiddleware) PostCard(ctx context.Context, DeckID string, a model.Card) error {
+	if err := mw.authorize(ctx, DeckID, clientModel.RoleEditor); err != nil {
+		return err
+	}
+	return mw.next.PostCard(ctx, DeckID, a)
+}
+
+func (mw authorizationMiddleware) DeleteCard(ctx context.Context, DeckID string, CardID string) error {
+	if err := mw.authorize(ctx, DeckID, clientModel.RoleEditor); err != nil {
+		return err
+	}
+	return mw.next.DeleteCard(ctx, DeckID, CardID)
+}
+
+// ApplyDecks checks every write of the batch before storing any of them.
+func (mw authorizationMiddleware) ApplyDecks(ctx context.Context, writes []server.DeckWrite) error {
+	for _, w := range writes {
+		var err error
+		switch w.Kind {
+		case data.WritePutDeck:
+			err = mw.authorize(ctx, w.ID, clientModel.RoleEditor)
+		case data.WriteDeleteDeck:
+			err = mw.authorize(ctx, w.ID, clientModel.RoleAdmin)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := mw.next.ApplyDecks(ctx, writes); err != nil {
+		return err
+	}
+	for _, w := range writes {
+		if w.Kind == data.WriteDeleteDeck {
+			owner, deckID := server.ParseDeckRef(ctx, w.ID)
+			mw.acl.Forget(ctx, owner, deckID)
+		}
+	}
+	return nil
+}
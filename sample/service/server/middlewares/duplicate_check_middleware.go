@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/duplicates"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+// DuplicateCheckMiddleware : Check the Cards posted to input SampleService
+// against the Cards of their Deck. In duplicates.CheckWarn mode, Cards with
+// near-duplicates are stored along with a warning (see server.AddWarning),
+// in duplicates.CheckReject mode they are refused with
+// server.ErrDuplicateCard.
+func DuplicateCheckMiddleware(mode string, threshold float64) Middleware {
+	return func(next server.SampleService) server.SampleService {
+		return &duplicateCheckMiddleware{
+			next:      next,
+			mode:      mode,
+			threshold: threshold,
+		}
+	}
+}
+
+type duplicateCheckMiddleware struct {
+	next      server.SampleService
+	mode      string
+	threshold float64
+}
+
+func (mw duplicateCheckMiddleware) PostDeck(ctx context.Context, p model.Deck) error {
+	return mw.next.PostDeck(ctx, p)
+}
+
+func (mw duplicateCheckMiddleware) GetDeck(ctx context.Context, id string) (clientModel.Deck, error) {
+	return mw.next.GetDeck(ctx, id)
+}
+
+func (mw duplicateCheckMiddleware) PutDeck(ctx context.Context, id string, p model.Deck) error {
+	return mw.next.PutDeck(ctx, id, p)
+}
+
+func (mw duplicateCheckMiddleware) GetDecks(ctx context.Context) ([]clientModel.Deck, error) {
+	return mw.next.GetDecks(ctx)
+}
+
+func (mw duplicateCheckMiddleware) DeleteDeck(ctx context.Context, id string) error {
+	return mw.next.DeleteDeck(ctx, id)
+}
+
+func (mw duplicateCheckMiddleware) GetCards(ctx context.Context, DeckID string) ([]clientModel.Card, error) {
+	return mw.next.GetCards(ctx, DeckID)
+}
+
+func (mw duplicateCheckMiddleware) GetCard(ctx context.Context, DeckID string, CardID string) (clientModel.Card, error) {
+	return mw.next.GetCard(ctx, DeckID, CardID)
+}
+
+func (mw duplicateCheckMiddleware) PostCard(ctx context.Context, DeckID string, a model.Card) error {
+	if mw.mode != duplicates.CheckWarn && mw.mode != duplicates.CheckReject {
+		return mw.next.PostCard(ctx, DeckID, a)
+	}
+	cards, err := mw.next.GetCards(ctx, DeckID)
+	if err != nil {
+		return err
+	}
+	// Cards with the same ID are left to the repository, as already existing.
+	others := cards[:0]
+	for _, c := range cards {
+		if c.ID != a.ID {
+			others = append(others, c)
+		}
+	}
+	matches := duplicates.Find(mapper.ToClientCard(a), others, mw.threshold)
+	if len(matches) > 0 && mw.mode == duplicates.CheckReject {
+		return server.ErrDuplicateCard
+	}
+	if err := mw.next.PostCard(ctx, DeckID, a); err != nil {
+		return err
+	}
+	for _, m := range matches {
+		server.AddWarning(ctx, fmt.Sprintf("near-duplicate of card %s (similarity %.2f)", m.Card.ID, m.Similarity))
+	}
+	return nil
+}
+
+func (mw duplicateCheckMiddleware) DeleteCard(ctx context.Context, DeckID string, CardID string) error {
+	return mw.next.DeleteCard(ctx, DeckID, CardID)
+}
+
+func (mw duplicateCheckMiddleware) ApplyDecks(ctx context.Context, writes []server.DeckWrite) error {
+	return mw.next.ApplyDecks(ctx, writes)
+}
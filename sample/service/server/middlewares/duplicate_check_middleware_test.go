@@ -0,0 +1,45 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/duplicates"
+)
+
+func TestDuplicateCheck(t *testing.T) {
+	for _, mode := range []string{duplicates.CheckOff, duplicates.CheckWarn, duplicates.CheckReject} {
+		s := DuplicateCheckMiddleware(mode, duplicates.DefaultThreshold)(server.NewService(inmem.NewInmemRepository()))
+		ctx := server.NewWarningsContext(as("alice"))
+		if err := s.PostDeck(ctx, model.Deck{ID: "d", Cards: []model.Card{{ID: "a", First: "to be", Second: "être"}}}); err != nil {
+			t.Fatal(err)
+		}
+		err := s.PostCard(ctx, "d", model.Card{ID: "b", First: "To be!", Second: "etre"})
+		warnings := server.Warnings(ctx)
+		switch mode {
+		case duplicates.CheckOff:
+			if err != nil || len(warnings) != 0 {
+				t.Errorf("%s: %v %q", mode, err, warnings)
+			}
+		case duplicates.CheckWarn:
+			if err != nil || len(warnings) != 1 || !strings.Contains(warnings[0], "card a") {
+				t.Errorf("%s: %v %q", mode, err, warnings)
+			}
+		case duplicates.CheckReject:
+			if err != server.ErrDuplicateCard {
+				t.Errorf("%s: %v, want %v", mode, err, server.ErrDuplicateCard)
+			}
+			if cards, _ := s.GetCards(ctx, "d"); len(cards) != 1 {
+				t.Errorf("%s: stored %+v", mode, cards)
+			}
+		}
+
+		// Cards are not compared with themselves.
+		if err := s.PostCard(ctx, "d", model.Card{ID: "a", First: "to be", Second: "être"}); err == server.ErrDuplicateCard {
+			t.Errorf("%s: card a compared with itself", mode)
+		}
+	}
+}
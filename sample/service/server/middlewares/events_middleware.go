@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/feed"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/sharing"
+)
+
+// EventsMiddleware : Publish successful mutations of input SampleService to
+// the change feed, for the owner of the Deck and the members acl tells.
+// Events carry the Decks and Cards as next stored them.
+func EventsMiddleware(broker *feed.Broker, acl sharing.Authorizer) Middleware {
+	return func(next server.SampleService) server.SampleService {
+		return &eventsMiddleware{
+			next:   next,
+			broker: broker,
+			acl:    acl,
+		}
+	}
+}
+
+type eventsMiddleware struct {
+	next   server.SampleService
+	broker *feed.Broker
+	acl    sharing.Authorizer
+}
+
+// event returns an event of the Deck ref, for its owner and its members.
+// Members are looked up before deletions, which forget them.
+func (mw eventsMiddleware) event(ctx context.Context, t feed.EventType, ref string) feed.Event {
+	// Events carry the owner of the Deck, which may be shared with the caller.
+	owner, deckID := server.ParseDeckRef(ctx, ref)
+	return feed.Event{Owner: owner, Type: t, DeckID: deckID, Members: mw.acl.Members(ctx, owner, deckID)}
+}
+
+// stored returns the Deck ref as next stores it, p when it is gone already.
+func (mw eventsMiddleware) stored(ctx context.Context, ref string, p model.Deck) *clientModel.Deck {
+	owner, deckID := server.ParseDeckRef(ctx, ref)
+	d, err := mw.next.GetDeck(ctx, ref)
+	if err != nil {
+		d = mapper.ToClientDeck(p)
+	}
+	d.ID, d.Owner = deckID, owner
+	return &d
+}
+
+func (mw eventsMiddleware) PostDeck(ctx context.Context, p model.Deck) error {
+	if err := mw.next.PostDeck(ctx, p); err != nil {
+		return err
+	}
+	e := mw.event(ctx, feed.DeckCreated, p.ID)
+	e.Deck = mw.stored(ctx, p.ID, p)
+	mw.broker.Publish(e)
+	return nil
+}
+
+func (mw eventsMiddleware) GetDeck(ctx context.Context, id string) (clientModel.Deck, error) {
+	return mw.next.GetDeck(ctx, id)
+}
+
+func (mw eventsMiddleware) PutDeck(ctx context.Context, id string, p model.Deck) error {
+	// PUT creates or updates: look the Deck up first to tell both apart.
+	_, getErr := mw.next.GetDeck(ctx, id)
+	if err := mw.next.PutDeck(ctx, id, p); err != nil {
+		return err
+	}
+	t := feed.DeckUpdated
+	if getErr != nil {
+		t = feed.DeckCreated
+	}
+	e := mw.event(ctx, t, id)
+	e.Deck = mw.stored(ctx, id, p)
+	mw.broker.Publish(e)
+	return nil
+}
+
+func (mw eventsMiddleware) GetDecks(ctx context.Context) ([]clientModel.Deck, error) {
+	return mw.next.GetDecks(ctx)
+}
+
+func (mw eventsMiddleware) DeleteDeck(ctx context.Context, id string) error {
+	e := mw.event(ctx, feed.DeckDeleted, id)
+	if err := mw.next.DeleteDeck(ctx, id); err != nil {
+		return err
+	}
+	mw.broker.Publish(e)
+	return nil
+}
+
+func (mw eventsMiddleware) GetCards(ctx context.Context, DeckID string) ([]clientModel.Card, error) {
+	return mw.next.GetCards(ctx, DeckID)
+}
+
+func (mw eventsMiddleware) GetCard(ctx context.Context, DeckID string, CardID string) (clientModel.Card, error) {
+	return mw.next.GetCard(ctx, DeckID, CardID)
+}
+
+func (mw eventsMiddleware) PostCard(ctx context.Context, DeckID string, a model.Card) error {
+	if err := mw.next.PostCard(ctx, DeckID, a); err != nil {
+		return err
+	}
+	e := mw.event(ctx, feed.CardCreated, DeckID)
+	e.CardID = a.ID
+	c, err := mw.next.GetCard(ctx, DeckID, a.ID)
+	if err != nil {
+		c = mapper.ToClientCard(a)
+	}
+	e.Card = &c
+	mw.broker.Publish(e)
+	return nil
+}
+
+func (mw eventsMiddleware) DeleteCard(ctx context.Context, DeckID string, CardID string) error {
+	if err := mw.next.DeleteCard(ctx, DeckID, CardID); err != nil {
+		return err
+	}
+	e := mw.event(ctx, feed.CardDeleted, DeckID)
+	e.CardID = CardID
+	mw.broker.Publish(e)
+	return nil
+}
+
+func (mw eventsMiddleware) ApplyDecks(ctx context.Context, writes []server.DeckWrite) error {
+	// Puts of Decks missing before the batch, or deleted by it, create them.
+	exists := map[string]bool{}
+	var events []feed.Event
+	for _, w := range writes {
+		if _, seen := exists[w.ID]; !seen {
+			_, err := mw.next.GetDeck(ctx, w.ID)
+			exists[w.ID] = err == nil
+		}
+		t := feed.DeckDeleted
+		if w.Kind != data.WriteDeleteDeck {
+			t = feed.DeckCreated
+			if exists[w.ID] {
+				t = feed.DeckUpdated
+			}
+		}
+		exists[w.ID] = w.Kind != data.WriteDeleteDeck
+		events = append(events, mw.event(ctx, t, w.ID))
+	}
+	if err := mw.next.ApplyDecks(ctx, writes); err != nil {
+		return err
+	}
+	for i, w := range writes {
+		if w.Kind != data.WriteDeleteDeck {
+			events[i].Deck = mw.stored(ctx, w.ID, w.Deck)
+		}
+		mw.broker.Publish(events[i])
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/richtext"
+)
+
+// InstrumentingMiddleware : Record request metrics of input SampleService.
+// requestCount is labelled by method and error ("true" or "false"),
+// errorCount by method and kind, requestLatency (in seconds) by method.
+func InstrumentingMiddleware(requestCount, errorCount metrics.Counter, requestLatency metrics.Histogram) Middleware {
+	return func(next server.SampleService) server.SampleService {
+		return &instrumentingMiddleware{
+			next:           next,
+			requestCount:   requestCount,
+			errorCount:     errorCount,
+			requestLatency: requestLatency,
+		}
+	}
+}
+
+type instrumentingMiddleware struct {
+	next           server.SampleService
+	requestCount   metrics.Counter
+	errorCount     metrics.Counter
+	requestLatency metrics.Histogram
+}
+
+func (mw instrumentingMiddleware) observe(method string, begin time.Time, err error) {
+	mw.requestCount.With("method", method, "error", boolLabel(err != nil)).Add(1)
+	mw.requestLatency.With("method", method).Observe(time.Since(begin).Seconds())
+	if err != nil {
+		mw.errorCount.With("method", method, "kind", errorKind(err)).Add(1)
+	}
+}
+
+func (mw instrumentingMiddleware) PostDeck(ctx context.Context, p model.Deck) (err error) {
+	defer func(begin time.Time) {
+		mw.observe("PostDeck", begin, err)
+	}(time.Now())
+	return mw.next.PostDeck(ctx, p)
+}
+
+func (mw instrumentingMiddleware) GetDeck(ctx context.Context, id string) (p clientModel.Deck, err error) {
+	defer func(begin time.Time) {
+		mw.observe("GetDeck", begin, err)
+	}(time.Now())
+	return mw.next.GetDeck(ctx, id)
+}
+
+func (mw instrumentingMiddleware) PutDeck(ctx context.Context, id string, p model.Deck) (err error) {
+	defer func(begin time.Time) {
+		mw.observe("PutDeck", begin, err)
+	}(time.Now())
+	return mw.next.PutDeck(ctx, id, p)
+}
+
+func (mw instrumentingMiddleware) GetDecks(ctx context.Context) (decks []clientModel.Deck, err error) {
+	defer func(begin time.Time) {
+		mw.observe("GetDecks", begin, err)
+	}(time.Now())
+	return mw.next.GetDecks(ctx)
+}
+
+func (mw instrumentingMiddleware) DeleteDeck(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		mw.observe("DeleteDeck", begin, err)
+	}(time.Now())
+	return mw.next.DeleteDeck(ctx, id)
+}
+
+func (mw instrumentingMiddleware) GetCards(ctx context.Context, DeckID string) (cards []clientModel.Card, err error) {
+	defer func(begin time.Time) {
+		mw.observe("GetCards", begin, err)
+	}(time.Now())
+	return mw.next.GetCards(ctx, DeckID)
+}
+
+func (mw instrumentingMiddleware) GetCard(ctx context.Context, DeckID string, CardID string) (a clientModel.Card, err error) {
+	defer func(begin time.Time) {
+		mw.observe("GetCard", begin, err)
+	}(time.Now())
+	return mw.next.GetCard(ctx, DeckID, CardID)
+}
+
+func (mw instrumentingMiddleware) PostCard(ctx context.Context, DeckID string, a model.Card) (err error) {
+	defer func(begin time.Time) {
+		mw.observe("PostCard", begin, err)
+	}(time.Now())
+	return mw.next.PostCard(ctx, DeckID, a)
+}
+
+func (mw instrumentingMiddleware) DeleteCard(ctx context.Context, DeckID string, CardID string) (err error) {
+	defer func(begin time.Time) {
+		mw.observe("DeleteCard", begin, err)
+	}(time.Now())
+	return mw.next.DeleteCard(ctx, DeckID, CardID)
+}
+
+func (mw instrumentingMiddleware) ApplyDecks(ctx context.Context, writes []server.DeckWrite) (err error) {
+	defer func(begin time.Time) {
+		mw.observe("ApplyDecks", begin, err)
+	}(time.Now())
+	return mw.next.ApplyDecks(ctx, writes)
+}
+
+func boolLabel(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// errorKind maps an error onto a bounded set of label values.
+func errorKind(err error) string {
+	switch err {
+	case data.ErrNotFound:
+		return "not_found"
+	case data.ErrAlreadyExists:
+		return "already_exists"
+	case data.ErrInconsistentIDs:
+		return "inconsistent_ids"
+	case server.ErrInvalidDeckID:
+		return "invalid_deck_id"
+	case server.ErrForbidden:
+		return "forbidden"
+	case server.ErrDeckQuotaExceeded, server.ErrCardQuotaExceeded:
+		return "quota_exceeded"
+	case server.ErrPayloadTooLarge:
+		return "payload_too_large"
+	case server.ErrDuplicateCard:
+		return "duplicate_card"
+	case server.ErrInvalidParent:
+		return "invalid_parent"
+	case server.ErrHasChildren:
+		return "has_children"
+	case richtext.ErrInvalidFormat:
+		return "invalid_format"
+	case context.Canceled:
+		return "canceled"
+	case context.DeadlineExceeded:
+		return "deadline_exceeded"
+	default:
+		return "internal"
+	}
+}
@@ -7,86 +7,117 @@ import (
 	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
 	"github.com/TangiFavennec/go-service-sample/sample/service/model"
 	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 )
 
 // Middleware describes a service (as opposed to endpoint) middleware.
 type Middleware func(server.SampleService) server.SampleService
 
 // LoggingMiddleware : Plug MiddleWare to input SampleService
-func LoggingMiddleware(logger log.Logger) Middleware {
+// Every line carries the request ID of the call context. Successful reads
+// are only logged when sampler keeps them, a nil sampler keeps them all.
+func LoggingMiddleware(logger log.Logger, sampler *logging.Sampler) Middleware {
 	return func(next server.SampleService) server.SampleService {
 		return &loggingMiddleware{
-			next:   next,
-			logger: logger,
+			next:    next,
+			logger:  logger,
+			sampler: sampler,
 		}
 	}
 }
 
 type loggingMiddleware struct {
-	next   server.SampleService
-	logger log.Logger
+	next    server.SampleService
+	logger  log.Logger
+	sampler *logging.Sampler
+}
+
+// log writes keyvals followed by err at a level depending on err: info on
+// success, warn on errors caused by the caller, error otherwise.
+func (mw loggingMiddleware) log(ctx context.Context, read bool, err error, keyvals ...interface{}) {
+	if read && err == nil && !mw.sampler.Sample() {
+		return
+	}
+	l := logging.FromContext(ctx, mw.logger)
+	switch {
+	case err == nil:
+		l = level.Info(l)
+	case server.IsKnownError(err):
+		l = level.Warn(l)
+	default:
+		l = level.Error(l)
+	}
+	l.Log(append(keyvals, "err", err)...)
 }
 
 func (mw loggingMiddleware) PostDeck(ctx context.Context, p model.Deck) (err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "PostDeck", "id", p.ID, "took", time.Since(begin), "err", err)
+		mw.log(ctx, false, err, "method", "PostDeck", "id", p.ID, "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.PostDeck(ctx, p)
 }
 
 func (mw loggingMiddleware) GetDeck(ctx context.Context, id string) (p clientModel.Deck, err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "GetDeck", "id", id, "took", time.Since(begin), "err", err)
+		mw.log(ctx, true, err, "method", "GetDeck", "id", id, "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.GetDeck(ctx, id)
 }
 
 func (mw loggingMiddleware) PutDeck(ctx context.Context, id string, p model.Deck) (err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "PutDeck", "id", id, "took", time.Since(begin), "err", err)
+		mw.log(ctx, false, err, "method", "PutDeck", "id", id, "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.PutDeck(ctx, id, p)
 }
 
 func (mw loggingMiddleware) GetDecks(ctx context.Context) (decks []clientModel.Deck, err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "GetDecks", "took", time.Since(begin), "err", err)
+		mw.log(ctx, true, err, "method", "GetDecks", "count", len(decks), "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.GetDecks(ctx)
 }
 
 func (mw loggingMiddleware) DeleteDeck(ctx context.Context, id string) (err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "DeleteDeck", "id", id, "took", time.Since(begin), "err", err)
+		mw.log(ctx, false, err, "method", "DeleteDeck", "id", id, "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.DeleteDeck(ctx, id)
 }
 
 func (mw loggingMiddleware) GetCards(ctx context.Context, DeckID string) (cards []clientModel.Card, err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "GetCards", "DeckID", DeckID, "took", time.Since(begin), "err", err)
+		mw.log(ctx, true, err, "method", "GetCards", "DeckID", DeckID, "count", len(cards), "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.GetCards(ctx, DeckID)
 }
 
 func (mw loggingMiddleware) GetCard(ctx context.Context, DeckID string, CardID string) (a clientModel.Card, err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "GetCard", "DeckID", DeckID, "CardID", CardID, "took", time.Since(begin), "err", err)
+		mw.log(ctx, true, err, "method", "GetCard", "DeckID", DeckID, "CardID", CardID, "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.GetCard(ctx, DeckID, CardID)
 }
 
 func (mw loggingMiddleware) PostCard(ctx context.Context, DeckID string, a model.Card) (err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "PostCard", "DeckID", DeckID, "took", time.Since(begin), "err", err)
+		mw.log(ctx, false, err, "method", "PostCard", "DeckID", DeckID, "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.PostCard(ctx, DeckID, a)
 }
 
 func (mw loggingMiddleware) DeleteCard(ctx context.Context, DeckID string, CardID string) (err error) {
 	defer func(begin time.Time) {
-		mw.logger.Log("method", "DeleteCard", "DeckID", DeckID, "CardID", CardID, "took", time.Since(begin), "err", err)
+		mw.log(ctx, false, err, "method", "DeleteCard", "DeckID", DeckID, "CardID", CardID, "took", time.Since(begin))
 	}(time.Now())
 	return mw.next.DeleteCard(ctx, DeckID, CardID)
 }
+
+func (mw loggingMiddleware) ApplyDecks(ctx context.Context, writes []server.DeckWrite) (err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, false, err, "method", "ApplyDecks", "writes", len(writes), "took", time.Since(begin))
+	}(time.Now())
+	return mw.next.ApplyDecks(ctx, writes)
+}
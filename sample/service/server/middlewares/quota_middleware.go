@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+// Quotas bound the storage of each user. Zero values mean unlimited.
+type Quotas struct {
+	MaxDecks       int // Decks per owner
+	MaxCards       int // Cards per Deck
+	MaxPayloadSize int // bytes of the JSON encoding of a stored Deck or Card
+}
+
+// QuotaMiddleware : Reject the calls of input SampleService exceeding quotas
+// with server.ErrDeckQuotaExceeded, server.ErrCardQuotaExceeded or
+// server.ErrPayloadTooLarge. Decks are counted in repo, per owner: writes
+// to the shared Deck of another user count against the quota of its owner.
+func QuotaMiddleware(q Quotas, repo data.SampleRepository) Middleware {
+	return func(next server.SampleService) server.SampleService {
+		return &quotaMiddleware{
+			next:   next,
+			repo:   repo,
+			quotas: q,
+			mtx:    &sync.Mutex{},
+		}
+	}
+}
+
+type quotaMiddleware struct {
+	next   server.SampleService
+	repo   data.SampleRepository
+	quotas Quotas
+	// mtx is held from the count of Decks or Cards to the write checked
+	// against it, so that concurrent writes cannot share the last free slot.
+	mtx *sync.Mutex
+}
+
+func (mw quotaMiddleware) checkPayload(v interface{}) error {
+	if mw.quotas.MaxPayloadSize <= 0 {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(b) > mw.quotas.MaxPayloadSize {
+		return server.ErrPayloadTooLarge
+	}
+	return nil
+}
+
+func (mw quotaMiddleware) checkDecks(ctx context.Context, owner string) error {
+	if mw.quotas.MaxDecks <= 0 {
+		return nil
+	}
+	decks, err := mw.repo.GetDecks(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if len(decks) >= mw.quotas.MaxDecks {
+		return server.ErrDeckQuotaExceeded
+	}
+	return nil
+}
+
+func (mw quotaMiddleware) PostDeck(ctx context.Context, p model.Deck) error {
+	if mw.quotas.MaxCards > 0 && len(p.Cards) > mw.quotas.MaxCards {
+		return server.ErrCardQuotaExceeded
+	}
+	if err := mw.checkPayload(p); err != nil {
+		return err
+	}
+	if mw.quotas.MaxDecks > 0 {
+		mw.mtx.Lock()
+		defer mw.mtx.Unlock()
+	}
+	if err := mw.checkDecks(ctx, auth.Subject(ctx)); err != nil {
+		return err
+	}
+	return mw.next.PostDeck(ctx, p)
+}
+
+func (mw quotaMiddleware) GetDeck(ctx context.Context, id string) (clientModel.Deck, error) {
+	return mw.next.GetDeck(ctx, id)
+}
+
+func (mw quotaMiddleware) PutDeck(ctx context.Context, id string, p model.Deck) error {
+	if mw.quotas.MaxCards > 0 && len(p.Cards) > mw.quotas.MaxCards {
+		return server.ErrCardQuotaExceeded
+	}
+	if err := mw.checkPayload(p); err != nil {
+		return err
+	}
+	// PUT creates missing Decks, which count against the Deck quota.
+	if mw.quotas.MaxDecks > 0 {
+		mw.mtx.Lock()
+		defer mw.mtx.Unlock()
+	}
+	owner, deckID := server.ParseDeckRef(ctx, id)
+	if _, err := mw.repo.GetDeck(ctx, owner, deckID); err == data.ErrNotFound {
+		if err := mw.checkDecks(ctx, owner); err != nil {
+			return err
+		}
+	}
+	return mw.next.PutDeck(ctx, id, p)
+}
+
+func (mw quotaMiddleware) GetDecks(ctx context.Context) ([]clientModel.Deck, error) {
+	return mw.next.GetDecks(ctx)
+}
+
+func (mw quotaMiddleware) DeleteDeck(ctx context.Context, id string) error {
+	return mw.next.DeleteDeck(ctx, id)
+}
+
+func (mw quotaMiddleware) GetCards(ctx context.Context, DeckID string) ([]clientModel.Card, error) {
+	return mw.next.GetCards(ctx, DeckID)
+}
+
+func (mw quotaMiddleware) GetCard(ctx context.Context, DeckID string, CardID string) (clientModel.Card, error) {
+	return mw.next.GetCard(ctx, DeckID, CardID)
+}
+
+func (mw quotaMiddleware) PostCard(ctx context.Context, DeckID string, a model.Card) error {
+	if err := mw.checkPayload(a); err != nil {
+		return err
+	}
+	if mw.quotas.MaxCards > 0 {
+		mw.mtx.Lock()
+		defer mw.mtx.Unlock()
+		cards, err := mw.next.GetCards(ctx, DeckID)
+		if err != nil {
+			return err
+		}
+		if len(cards) >= mw.quotas.MaxCards {
+			return server.ErrCardQuotaExceeded
+		}
+	}
+	return mw.next.PostCard(ctx, DeckID, a)
+}
+
+func (mw quotaMiddleware) DeleteCard(ctx context.Context, DeckID string, CardID string) error {
+	return mw.next.DeleteCard(ctx, DeckID, CardID)
+}
+
+func (mw quotaMiddleware) ApplyDecks(ctx context.Context, writes []server.DeckWrite) error {
+	for _, w := range writes {
+		if w.Kind == data.WriteDeleteDeck {
+			continue
+		}
+		if mw.quotas.MaxCards > 0 && len(w.Deck.Cards) > mw.quotas.MaxCards {
+			return server.ErrCardQuotaExceeded
+		}
+		if err := mw.checkPayload(w.Deck); err != nil {
+			return err
+		}
+	}
+	if mw.quotas.MaxDecks <= 0 {
+		return mw.next.ApplyDecks(ctx, writes)
+	}
+	mw.mtx.Lock()
+	defer mw.mtx.Unlock()
+	// The batch may create Decks as long as they still fit the quota of
+	// their owner once its deletions are done.
+	type state struct {
+		exists         map[string]bool
+		count, created int
+	}
+	owners := map[string]*state{}
+	for _, w := range writes {
+		owner, id := server.ParseDeckRef(ctx, w.ID)
+		st := owners[owner]
+		if st == nil {
+			decks, err := mw.repo.GetDecks(ctx, owner)
+			if err != nil {
+				return err
+			}
+			st = &state{exists: map[string]bool{}, count: len(decks)}
+			for _, d := range decks {
+				st.exists[d.ID] = true
+			}
+			owners[owner] = st
+		}
+		switch {
+		case w.Kind == data.WriteDeleteDeck && st.exists[id]:
+			st.exists[id] = false
+			st.count--
+		case w.Kind != data.WriteDeleteDeck && !st.exists[id]:
+			st.exists[id] = true
+			st.count++
+			st.created++
+		}
+	}
+	for _, st := range owners {
+		if st.created > 0 && st.count > mw.quotas.MaxDecks {
+			return server.ErrDeckQuotaExceeded
+		}
+	}
+	return mw.next.ApplyDecks(ctx, writes)
+}
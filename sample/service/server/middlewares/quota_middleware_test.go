@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+func as(user string) context.Context {
+	return auth.NewContext(context.Background(), auth.Principal{Subject: user})
+}
+
+func TestDeckQuotaCountsTheOwner(t *testing.T) {
+	repo := inmem.NewInmemRepository()
+	s := QuotaMiddleware(Quotas{MaxDecks: 1}, repo)(server.NewService(repo))
+	if err := s.PostDeck(as("alice"), model.Deck{ID: "full"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// bob has no Deck, alice has no room left.
+	if err := s.PutDeck(as("bob"), "alice~new", model.Deck{ID: "new"}); err != server.ErrDeckQuotaExceeded {
+		t.Fatalf("PutDeck of alice~new: %v, want %v", err, server.ErrDeckQuotaExceeded)
+	}
+	err := s.ApplyDecks(as("bob"), []server.DeckWrite{{Kind: data.WritePutDeck, ID: "alice~new", Deck: model.Deck{ID: "new"}}})
+	if err != server.ErrDeckQuotaExceeded {
+		t.Fatalf("batch creating alice~new: %v, want %v", err, server.ErrDeckQuotaExceeded)
+	}
+	if err := s.PutDeck(as("bob"), "alice~full", model.Deck{ID: "full", Name: "Full"}); err != nil {
+		t.Fatalf("PutDeck of an existing Deck: %v", err)
+	}
+	if err := s.PutDeck(as("bob"), "new", model.Deck{ID: "new"}); err != nil {
+		t.Fatalf("PutDeck of bob: %v", err)
+	}
+
+	// Deletions make room in the same batch, per owner.
+	err = s.ApplyDecks(as("alice"), []server.DeckWrite{
+		{Kind: data.WriteDeleteDeck, ID: "full"},
+		{Kind: data.WritePostDeck, ID: "other", Deck: model.Deck{ID: "other"}},
+		{Kind: data.WritePutDeck, ID: "bob~new", Deck: model.Deck{ID: "new", Name: "New"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/search"
+)
+
+// RepositoryIndexingMiddleware : Keep the search index up to date with the
+// successful mutations of input SampleRepository. The index is rebuilt
+// from the repository at construction.
+func RepositoryIndexingMiddleware(index *search.Index) RepositoryMiddleware {
+	return func(next data.SampleRepository) data.SampleRepository {
+		index.Rebuild(context.Background(), next)
+		return &repositoryIndexingMiddleware{
+			next:  next,
+			index: index,
+		}
+	}
+}
+
+type repositoryIndexingMiddleware struct {
+	next  data.SampleRepository
+	index *search.Index
+}
+
+// reindex indexes the Deck as stored, once written.
+func (mw repositoryIndexingMiddleware) reindex(ctx context.Context, owner string, id string) {
+	if d, err := mw.next.GetDeck(ctx, owner, id); err == nil {
+		mw.index.PutDeck(owner, d)
+	}
+}
+
+func (mw repositoryIndexingMiddleware) PostDeck(ctx context.Context, owner string, p model.Deck) error {
+	if err := mw.next.PostDeck(ctx, owner, p); err != nil {
+		return err
+	}
+	mw.reindex(ctx, owner, p.ID)
+	return nil
+}
+
+func (mw repositoryIndexingMiddleware) GetDeck(ctx context.Context, owner string, id string) (model.Deck, error) {
+	return mw.next.GetDeck(ctx, owner, id)
+}
+
+func (mw repositoryIndexingMiddleware) PutDeck(ctx context.Context, owner string, id string, p model.Deck) error {
+	if err := mw.next.PutDeck(ctx, owner, id, p); err != nil {
+		return err
+	}
+	mw.reindex(ctx, owner, id)
+	return nil
+}
+
+func (mw repositoryIndexingMiddleware) GetDecks(ctx context.Context, owner string) ([]model.Deck, error) {
+	return mw.next.GetDecks(ctx, owner)
+}
+
+func (mw repositoryIndexingMiddleware) GetAllDecks(ctx context.Context) ([]model.Deck, error) {
+	return mw.next.GetAllDecks(ctx)
+}
+
+func (mw repositoryIndexingMiddleware) DeleteDeck(ctx context.Context, owner string, id string) error {
+	if err := mw.next.DeleteDeck(ctx, owner, id); err != nil {
+		return err
+	}
+	mw.index.DeleteDeck(owner, id)
+	return nil
+}
+
+func (mw repositoryIndexingMiddleware) GetCards(ctx context.Context, owner string, DeckID string) ([]model.Card, error) {
+	return mw.next.GetCards(ctx, owner, DeckID)
+}
+
+func (mw repositoryIndexingMiddleware) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (model.Card, error) {
+	return mw.next.GetCard(ctx, owner, DeckID, CardID)
+}
+
+func (mw repositoryIndexingMiddleware) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) error {
+	if err := mw.next.PostCard(ctx, owner, DeckID, a); err != nil {
+		return err
+	}
+	mw.index.PutCard(owner, DeckID, a)
+	return nil
+}
+
+func (mw repositoryIndexingMiddleware) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) error {
+	if err := mw.next.DeleteCard(ctx, owner, DeckID, CardID); err != nil {
+		return err
+	}
+	mw.index.DeleteCard(owner, DeckID, CardID)
+	return nil
+}
+
+func (mw repositoryIndexingMiddleware) Apply(ctx context.Context, batch []data.Write) error {
+	if err := mw.next.Apply(ctx, batch); err != nil {
+		return err
+	}
+	for _, w := range batch {
+		if w.Kind == data.WriteDeleteDeck {
+			mw.index.DeleteDeck(w.Owner, w.ID)
+		} else {
+			mw.reindex(ctx, w.Owner, w.ID)
+		}
+	}
+	return nil
+}
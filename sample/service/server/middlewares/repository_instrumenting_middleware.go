@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/metrics"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+// RepositoryInstrumentingMiddleware : Gauge the Decks and Cards stored by
+// input SampleRepository, all owners included. The gauges are refreshed
+// after every successful mutation.
+func RepositoryInstrumentingMiddleware(deckCount, cardCount metrics.Gauge) RepositoryMiddleware {
+	return func(next data.SampleRepository) data.SampleRepository {
+		mw := &repositoryInstrumentingMiddleware{
+			next:      next,
+			deckCount: deckCount,
+			cardCount: cardCount,
+		}
+		mw.refresh(context.Background())
+		return mw
+	}
+}
+
+type repositoryInstrumentingMiddleware struct {
+	next      data.SampleRepository
+	deckCount metrics.Gauge
+	cardCount metrics.Gauge
+}
+
+// refresh recomputes the gauges from the wrapped repository.
+func (mw repositoryInstrumentingMiddleware) refresh(ctx context.Context) {
+	decks, err := mw.next.GetAllDecks(ctx)
+	if err != nil {
+		return
+	}
+	cards := 0
+	for _, d := range decks {
+		cards += len(d.Cards)
+	}
+	mw.deckCount.Set(float64(len(decks)))
+	mw.cardCount.Set(float64(cards))
+}
+
+// refreshed refreshes the gauges if err is nil, then returns err.
+func (mw repositoryInstrumentingMiddleware) refreshed(ctx context.Context, err error) error {
+	if err == nil {
+		mw.refresh(ctx)
+	}
+	return err
+}
+
+func (mw repositoryInstrumentingMiddleware) PostDeck(ctx context.Context, owner string, p model.Deck) error {
+	return mw.refreshed(ctx, mw.next.PostDeck(ctx, owner, p))
+}
+
+func (mw repositoryInstrumentingMiddleware) GetDeck(ctx context.Context, owner string, id string) (model.Deck, error) {
+	return mw.next.GetDeck(ctx, owner, id)
+}
+
+func (mw repositoryInstrumentingMiddleware) PutDeck(ctx context.Context, owner string, id string, p model.Deck) error {
+	return mw.refreshed(ctx, mw.next.PutDeck(ctx, owner, id, p))
+}
+
+func (mw repositoryInstrumentingMiddleware) GetDecks(ctx context.Context, owner string) ([]model.Deck, error) {
+	return mw.next.GetDecks(ctx, owner)
+}
+
+func (mw repositoryInstrumentingMiddleware) GetAllDecks(ctx context.Context) ([]model.Deck, error) {
+	return mw.next.GetAllDecks(ctx)
+}
+
+func (mw repositoryInstrumentingMiddleware) DeleteDeck(ctx context.Context, owner string, id string) error {
+	return mw.refreshed(ctx, mw.next.DeleteDeck(ctx, owner, id))
+}
+
+func (mw repositoryInstrumentingMiddleware) GetCards(ctx context.Context, owner string, DeckID string) ([]model.Card, error) {
+	return mw.next.GetCards(ctx, owner, DeckID)
+}
+
+func (mw repositoryInstrumentingMiddleware) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (model.Card, error) {
+	return mw.next.GetCard(ctx, owner, DeckID, CardID)
+}
+
+func (mw repositoryInstrumentingMiddleware) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) error {
+	return mw.refreshed(ctx, mw.next.PostCard(ctx, owner, DeckID, a))
+}
+
+func (mw repositoryInstrumentingMiddleware) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) error {
+	return mw.refreshed(ctx, mw.next.DeleteCard(ctx, owner, DeckID, CardID))
+}
+
+func (mw repositoryInstrumentingMiddleware) Apply(ctx context.Context, batch []data.Write) error {
+	return mw.refreshed(ctx, mw.next.Apply(ctx, batch))
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+// RepositoryMiddleware describes a SampleRepository middleware.
+type RepositoryMiddleware func(data.SampleRepository) data.SampleRepository
+
+// RepositoryLoggingMiddleware : Log every call to input SampleRepository at
+// debug level, with the request ID of the call context
+func RepositoryLoggingMiddleware(logger log.Logger) RepositoryMiddleware {
+	return func(next data.SampleRepository) data.SampleRepository {
+		return &repositoryLoggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type repositoryLoggingMiddleware struct {
+	next   data.SampleRepository
+	logger log.Logger
+}
+
+func (mw repositoryLoggingMiddleware) log(ctx context.Context, keyvals ...interface{}) {
+	level.Debug(logging.FromContext(ctx, mw.logger)).Log(keyvals...)
+}
+
+func (mw repositoryLoggingMiddleware) PostDeck(ctx context.Context, owner string, p model.Deck) (err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "PostDeck", "owner", owner, "id", p.ID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.PostDeck(ctx, owner, p)
+}
+
+func (mw repositoryLoggingMiddleware) GetDeck(ctx context.Context, owner string, id string) (p model.Deck, err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "GetDeck", "owner", owner, "id", id, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetDeck(ctx, owner, id)
+}
+
+func (mw repositoryLoggingMiddleware) PutDeck(ctx context.Context, owner string, id string, p model.Deck) (err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "PutDeck", "owner", owner, "id", id, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.PutDeck(ctx, owner, id, p)
+}
+
+func (mw repositoryLoggingMiddleware) GetDecks(ctx context.Context, owner string) (decks []model.Deck, err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "GetDecks", "owner", owner, "count", len(decks), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetDecks(ctx, owner)
+}
+
+func (mw repositoryLoggingMiddleware) GetAllDecks(ctx context.Context) (decks []model.Deck, err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "GetAllDecks", "count", len(decks), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetAllDecks(ctx)
+}
+
+func (mw repositoryLoggingMiddleware) DeleteDeck(ctx context.Context, owner string, id string) (err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "DeleteDeck", "owner", owner, "id", id, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.DeleteDeck(ctx, owner, id)
+}
+
+func (mw repositoryLoggingMiddleware) GetCards(ctx context.Context, owner string, DeckID string) (cards []model.Card, err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "GetCards", "owner", owner, "DeckID", DeckID, "count", len(cards), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetCards(ctx, owner, DeckID)
+}
+
+func (mw repositoryLoggingMiddleware) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (a model.Card, err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "GetCard", "owner", owner, "DeckID", DeckID, "CardID", CardID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetCard(ctx, owner, DeckID, CardID)
+}
+
+func (mw repositoryLoggingMiddleware) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) (err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "PostCard", "owner", owner, "DeckID", DeckID, "CardID", a.ID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.PostCard(ctx, owner, DeckID, a)
+}
+
+func (mw repositoryLoggingMiddleware) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) (err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "DeleteCard", "owner", owner, "DeckID", DeckID, "CardID", CardID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.DeleteCard(ctx, owner, DeckID, CardID)
+}
+
+func (mw repositoryLoggingMiddleware) Apply(ctx context.Context, batch []data.Write) (err error) {
+	defer func(begin time.Time) {
+		mw.log(ctx, "repository", "Apply", "writes", len(batch), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.Apply(ctx, batch)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+)
+
+// RepositoryTreeMiddleware : Keep the Decks of every owner of input
+// SampleRepository a forest. Parents must be other Decks of the same owner
+// and not among the sub-decks of the Deck, and Decks having sub-decks
+// cannot be deleted.
+func RepositoryTreeMiddleware() RepositoryMiddleware {
+	return func(next data.SampleRepository) data.SampleRepository {
+		return &repositoryTreeMiddleware{
+			next: next,
+		}
+	}
+}
+
+type repositoryTreeMiddleware struct {
+	next data.SampleRepository
+}
+
+// deckReader reads Decks from the repository, or an Overlay of it.
+type deckReader interface {
+	GetDeck(ctx context.Context, owner string, id string) (model.Deck, error)
+	GetDecks(ctx context.Context, owner string) ([]model.Deck, error)
+}
+
+// checkParent walks up the ancestors p would have, looking for a missing
+// Deck or a cycle.
+func checkParent(ctx context.Context, decks deckReader, owner string, p model.Deck) error {
+	if p.Parent != "" && strings.Contains(p.Parent, server.DeckRefSeparator) {
+		return server.ErrInvalidParent
+	}
+	seen := map[string]bool{p.ID: true}
+	for parent := p.Parent; parent != ""; {
+		if seen[parent] {
+			return server.ErrInvalidParent
+		}
+		seen[parent] = true
+		d, err := decks.GetDeck(ctx, owner, parent)
+		if err == data.ErrNotFound {
+			return server.ErrInvalidParent
+		}
+		if err != nil {
+			return err
+		}
+		parent = d.Parent
+	}
+	return nil
+}
+
+// checkChildren makes sure Deck id has no sub-decks.
+func checkChildren(ctx context.Context, decks deckReader, owner string, id string) error {
+	all, err := decks.GetDecks(ctx, owner)
+	if err != nil {
+		return err
+	}
+	for _, d := range all {
+		if d.Parent == id {
+			return server.ErrHasChildren
+		}
+	}
+	return nil
+}
+
+func (mw repositoryTreeMiddleware) PostDeck(ctx context.Context, owner string, p model.Deck) error {
+	if err := checkParent(ctx, mw.next, owner, p); err != nil {
+		return err
+	}
+	return mw.next.PostDeck(ctx, owner, p)
+}
+
+func (mw repositoryTreeMiddleware) GetDeck(ctx context.Context, owner string, id string) (model.Deck, error) {
+	return mw.next.GetDeck(ctx, owner, id)
+}
+
+func (mw repositoryTreeMiddleware) PutDeck(ctx context.Context, owner string, id string, p model.Deck) error {
+	if err := checkParent(ctx, mw.next, owner, p); err != nil {
+		return err
+	}
+	return mw.next.PutDeck(ctx, owner, id, p)
+}
+
+func (mw repositoryTreeMiddleware) GetDecks(ctx context.Context, owner string) ([]model.Deck, error) {
+	return mw.next.GetDecks(ctx, owner)
+}
+
+func (mw repositoryTreeMiddleware) GetAllDecks(ctx context.Context) ([]model.Deck, error) {
+	return mw.next.GetAllDecks(ctx)
+}
+
+func (mw repositoryTreeMiddleware) DeleteDeck(ctx context.Context, owner string, id string) error {
+	if err := checkChildren(ctx, mw.next, owner, id); err != nil {
+		return err
+	}
+	return mw.next.DeleteDeck(ctx, owner, id)
+}
+
+func (mw repositoryTreeMiddleware) GetCards(ctx context.Context, owner string, DeckID string) ([]model.Card, error) {
+	return mw.next.GetCards(ctx, owner, DeckID)
+}
+
+func (mw repositoryTreeMiddleware) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (model.Card, error) {
+	return mw.next.GetCard(ctx, owner, DeckID, CardID)
+}
+
+func (mw repositoryTreeMiddleware) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) error {
+	return mw.next.PostCard(ctx, owner, DeckID, a)
+}
+
+func (mw repositoryTreeMiddleware) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) error {
+	return mw.next.DeleteCard(ctx, owner, DeckID, CardID)
+}
+
+// Apply checks every Write against the Decks the previous ones leave, so that
+// a batch may move sub-decks away before deleting their parent.
+func (mw repositoryTreeMiddleware) Apply(ctx context.Context, batch []data.Write) error {
+	o := data.NewOverlay(mw.next)
+	for _, w := range batch {
+		var err error
+		switch w.Kind {
+		case data.WritePostDeck, data.WritePutDeck:
+			err = checkParent(ctx, o, w.Owner, w.Deck)
+		case data.WriteDeleteDeck:
+			err = checkChildren(ctx, o, w.Owner, w.ID)
+		}
+		if err != nil {
+			return err
+		}
+		if err := o.Stage(ctx, w); err != nil {
+			return err
+		}
+	}
+	return mw.next.Apply(ctx, batch)
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tracing"
+)
+
+// TracingMiddleware : Run every method of input SampleService in a child
+// span of the span found in its context
+func TracingMiddleware(tracer trace.Tracer) Middleware {
+	return func(next server.SampleService) server.SampleService {
+		return &tracingMiddleware{
+			next:   next,
+			tracer: tracer,
+		}
+	}
+}
+
+type tracingMiddleware struct {
+	next   server.SampleService
+	tracer trace.Tracer
+}
+
+func (mw tracingMiddleware) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return mw.tracer.Start(ctx, "SampleService."+method, trace.WithAttributes(attrs...))
+}
+
+func (mw tracingMiddleware) PostDeck(ctx context.Context, p model.Deck) (err error) {
+	ctx, span := mw.start(ctx, "PostDeck", attribute.String("deck.id", p.ID))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.PostDeck(ctx, p)
+}
+
+func (mw tracingMiddleware) GetDeck(ctx context.Context, id string) (p clientModel.Deck, err error) {
+	ctx, span := mw.start(ctx, "GetDeck", attribute.String("deck.id", id))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.GetDeck(ctx, id)
+}
+
+func (mw tracingMiddleware) PutDeck(ctx context.Context, id string, p model.Deck) (err error) {
+	ctx, span := mw.start(ctx, "PutDeck", attribute.String("deck.id", id))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.PutDeck(ctx, id, p)
+}
+
+func (mw tracingMiddleware) GetDecks(ctx context.Context) (decks []clientModel.Deck, err error) {
+	ctx, span := mw.start(ctx, "GetDecks")
+	defer func() {
+		span.SetAttributes(attribute.Int("decks.count", len(decks)))
+		tracing.End(span, err)
+	}()
+	return mw.next.GetDecks(ctx)
+}
+
+func (mw tracingMiddleware) DeleteDeck(ctx context.Context, id string) (err error) {
+	ctx, span := mw.start(ctx, "DeleteDeck", attribute.String("deck.id", id))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.DeleteDeck(ctx, id)
+}
+
+func (mw tracingMiddleware) GetCards(ctx context.Context, DeckID string) (cards []clientModel.Card, err error) {
+	ctx, span := mw.start(ctx, "GetCards", attribute.String("deck.id", DeckID))
+	defer func() {
+		span.SetAttributes(attribute.Int("cards.count", len(cards)))
+		tracing.End(span, err)
+	}()
+	return mw.next.GetCards(ctx, DeckID)
+}
+
+func (mw tracingMiddleware) GetCard(ctx context.Context, DeckID string, CardID string) (a clientModel.Card, err error) {
+	ctx, span := mw.start(ctx, "GetCard", attribute.String("deck.id", DeckID), attribute.String("card.id", CardID))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.GetCard(ctx, DeckID, CardID)
+}
+
+func (mw tracingMiddleware) PostCard(ctx context.Context, DeckID string, a model.Card) (err error) {
+	ctx, span := mw.start(ctx, "PostCard", attribute.String("deck.id", DeckID), attribute.String("card.id", a.ID))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.PostCard(ctx, DeckID, a)
+}
+
+func (mw tracingMiddleware) DeleteCard(ctx context.Context, DeckID string, CardID string) (err error) {
+	ctx, span := mw.start(ctx, "DeleteCard", attribute.String("deck.id", DeckID), attribute.String("card.id", CardID))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.DeleteCard(ctx, DeckID, CardID)
+}
+
+func (mw tracingMiddleware) ApplyDecks(ctx context.Context, writes []server.DeckWrite) (err error) {
+	ctx, span := mw.start(ctx, "ApplyDecks", attribute.Int("writes.count", len(writes)))
+	defer func() { tracing.End(span, err) }()
+	return mw.next.ApplyDecks(ctx, writes)
+}
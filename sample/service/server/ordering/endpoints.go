@@ -0,0 +1,121 @@
+package ordering
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the card ordering API
+type Endpoints struct {
+	MoveCardEndpoint     endpoint.Endpoint
+	ReorderCardsEndpoint endpoint.Endpoint
+	ShuffleCardsEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		MoveCardEndpoint:     MakeMoveCardEndpoint(s),
+		ReorderCardsEndpoint: MakeReorderCardsEndpoint(s),
+		ShuffleCardsEndpoint: MakeShuffleCardsEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		MoveCardEndpoint:     httptransport.NewClient("POST", tgt, encodeMoveCardRequest, decodeMoveCardResponse, options...).Endpoint(),
+		ReorderCardsEndpoint: httptransport.NewClient("POST", tgt, encodeReorderCardsRequest, decodeReorderCardsResponse, options...).Endpoint(),
+		ShuffleCardsEndpoint: httptransport.NewClient("POST", tgt, encodeShuffleCardsRequest, decodeShuffleCardsResponse, options...).Endpoint(),
+	}, nil
+}
+
+// MoveCard implements Service. Primarily useful in a client.
+func (e Endpoints) MoveCard(ctx context.Context, deckID string, cardID string, move clientModel.CardMove) ([]clientModel.Card, error) {
+	response, err := e.MoveCardEndpoint(ctx, clientRequest.MoveCard{DeckID: deckID, CardID: cardID, Move: move})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.MoveCard)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Cards, resp.Err
+}
+
+// ReorderCards implements Service. Primarily useful in a client.
+func (e Endpoints) ReorderCards(ctx context.Context, deckID string, cardIDs []string) ([]clientModel.Card, error) {
+	response, err := e.ReorderCardsEndpoint(ctx, clientRequest.ReorderCards{DeckID: deckID, CardIDs: cardIDs})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.ReorderCards)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Cards, resp.Err
+}
+
+// ShuffleCards implements Service. Primarily useful in a client.
+func (e Endpoints) ShuffleCards(ctx context.Context, deckID string, seed *int64) ([]clientModel.Card, int64, error) {
+	response, err := e.ShuffleCardsEndpoint(ctx, clientRequest.ShuffleCards{DeckID: deckID, Seed: seed})
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, ok := response.(clientResponse.ShuffleCards)
+	if !ok {
+		return nil, 0, ErrUnexpectedResponse
+	}
+	return resp.Cards, resp.Seed, resp.Err
+}
+
+// MakeMoveCardEndpoint returns an endpoint via the passed service.
+func MakeMoveCardEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.MoveCard)
+		c, e := s.MoveCard(ctx, req.DeckID, req.CardID, req.Move)
+		return clientResponse.MoveCard{Cards: c, Err: e}, e
+	}
+}
+
+// MakeReorderCardsEndpoint returns an endpoint via the passed service.
+func MakeReorderCardsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.ReorderCards)
+		c, e := s.ReorderCards(ctx, req.DeckID, req.CardIDs)
+		return clientResponse.ReorderCards{Cards: c, Err: e}, e
+	}
+}
+
+// MakeShuffleCardsEndpoint returns an endpoint via the passed service.
+func MakeShuffleCardsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.ShuffleCards)
+		c, seed, e := s.ShuffleCards(ctx, req.DeckID, req.Seed)
+		return clientResponse.ShuffleCards{Cards: c, Seed: seed, Err: e}, e
+	}
+}
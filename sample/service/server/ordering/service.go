@@ -0,0 +1,162 @@
+package ordering
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+// Service orders the Cards of a Deck. The order of a Deck is the one its
+// Cards are stored in, served as is by GetDeck and GetCards: new Cards go
+// last and deleted ones leave the others in place.
+type Service interface {
+	// MoveCard gives a Card another position, and returns the Cards of its
+	// Deck in their new order.
+	MoveCard(ctx context.Context, deckID string, cardID string, move clientModel.CardMove) ([]clientModel.Card, error)
+	// ReorderCards orders the Cards of a Deck as cardIDs, which must list
+	// each of them once.
+	ReorderCards(ctx context.Context, deckID string, cardIDs []string) ([]clientModel.Card, error)
+	// ShuffleCards orders the Cards of a Deck randomly, and returns them
+	// with the seed used: the same seed gives the same order back for the
+	// same Cards, whatever their current order.
+	ShuffleCards(ctx context.Context, deckID string, seed *int64) ([]clientModel.Card, int64, error)
+}
+
+var (
+	// ErrInvalidMove : Card move not giving exactly one of before, after and
+	// index, or an index out of the Deck
+	ErrInvalidMove = errors.New("invalid card move, give one of before, after or index")
+	// ErrInvalidOrder : new order not listing every Card of the Deck once
+	ErrInvalidOrder = errors.New("invalid card order, list every card of the deck once")
+)
+
+type deckService struct {
+	mtx   sync.Mutex
+	decks server.SampleService
+}
+
+// NewService Service Constructor. Decks are read and written through
+// decks, one change at a time.
+func NewService(decks server.SampleService) Service {
+	return &deckService{
+		decks: decks,
+	}
+}
+
+// reorder applies order to the Cards of a Deck, and returns them as stored.
+func (s *deckService) reorder(ctx context.Context, deckID string, order func(cards []clientModel.Card) ([]clientModel.Card, error)) ([]clientModel.Card, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	d, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	if d.Cards, err = order(d.Cards); err != nil {
+		return nil, err
+	}
+	if err := s.decks.PutDeck(ctx, deckID, mapper.FromClientDeck(d)); err != nil {
+		return nil, err
+	}
+	if d, err = s.decks.GetDeck(ctx, deckID); err != nil {
+		return nil, err
+	}
+	return d.Cards, nil
+}
+
+func indexOf(cards []clientModel.Card, cardID string) int {
+	for i, c := range cards {
+		if c.ID == cardID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *deckService) MoveCard(ctx context.Context, deckID string, cardID string, move clientModel.CardMove) ([]clientModel.Card, error) {
+	set := 0
+	for _, given := range []bool{move.Before != "", move.After != "", move.Index != nil} {
+		if given {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, ErrInvalidMove
+	}
+	return s.reorder(ctx, deckID, func(cards []clientModel.Card) ([]clientModel.Card, error) {
+		i := indexOf(cards, cardID)
+		if i < 0 {
+			return nil, data.ErrNotFound
+		}
+		if move.Before == cardID || move.After == cardID {
+			return cards, nil
+		}
+		card := cards[i]
+		rest := append(append([]clientModel.Card(nil), cards[:i]...), cards[i+1:]...)
+		var to int
+		switch {
+		case move.Index != nil:
+			if to = *move.Index; to < 0 || to > len(rest) {
+				return nil, ErrInvalidMove
+			}
+		case move.Before != "":
+			if to = indexOf(rest, move.Before); to < 0 {
+				return nil, data.ErrNotFound
+			}
+		default:
+			if to = indexOf(rest, move.After); to < 0 {
+				return nil, data.ErrNotFound
+			}
+			to++
+		}
+		return append(rest[:to], append([]clientModel.Card{card}, rest[to:]...)...), nil
+	})
+}
+
+func (s *deckService) ReorderCards(ctx context.Context, deckID string, cardIDs []string) ([]clientModel.Card, error) {
+	return s.reorder(ctx, deckID, func(cards []clientModel.Card) ([]clientModel.Card, error) {
+		if len(cardIDs) != len(cards) {
+			return nil, ErrInvalidOrder
+		}
+		byID := map[string]clientModel.Card{}
+		for _, c := range cards {
+			byID[c.ID] = c
+		}
+		res := make([]clientModel.Card, 0, len(cards))
+		for _, id := range cardIDs {
+			c, ok := byID[id]
+			if !ok {
+				return nil, ErrInvalidOrder
+			}
+			delete(byID, id)
+			res = append(res, c)
+		}
+		return res, nil
+	})
+}
+
+func (s *deckService) ShuffleCards(ctx context.Context, deckID string, seed *int64) ([]clientModel.Card, int64, error) {
+	var used int64
+	if seed != nil {
+		used = *seed
+	} else {
+		used = time.Now().UnixNano()
+	}
+	cards, err := s.reorder(ctx, deckID, func(cards []clientModel.Card) ([]clientModel.Card, error) {
+		// Cards are sorted first so that the seed alone decides the order.
+		res := append([]clientModel.Card(nil), cards...)
+		sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+		rand.New(rand.NewSource(used)).Shuffle(len(res), func(i, j int) {
+			res[i], res[j] = res[j], res[i]
+		})
+		return res, nil
+	})
+	return cards, used, err
+}
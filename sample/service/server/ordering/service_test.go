@@ -0,0 +1,112 @@
+package ordering
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+// newDeck returns a Service over Deck "d" of alice holding Cards a to e,
+// and the context of alice.
+func newDeck(t *testing.T) (Service, server.SampleService, context.Context) {
+	t.Helper()
+	ctx := auth.NewContext(context.Background(), auth.Principal{Subject: "alice"})
+	decks := server.NewService(inmem.NewInmemRepository())
+	d := model.Deck{ID: "d"}
+	for _, id := range "abcde" {
+		d.Cards = append(d.Cards, model.Card{ID: string(id), First: string(id), Second: string(id)})
+	}
+	if err := decks.PostDeck(ctx, d); err != nil {
+		t.Fatal(err)
+	}
+	return NewService(decks), decks, ctx
+}
+
+func ids(cards []clientModel.Card) string {
+	var b strings.Builder
+	for i, c := range cards {
+		if c.Position != i {
+			return "bad position"
+		}
+		b.WriteString(c.ID)
+	}
+	return b.String()
+}
+
+func index(i int) *int { return &i }
+
+func TestMoveCard(t *testing.T) {
+	for name, tc := range map[string]struct {
+		card string
+		move clientModel.CardMove
+		want string
+		err  error
+	}{
+		"before":        {"d", clientModel.CardMove{Before: "b"}, "adbce", nil},
+		"after":         {"a", clientModel.CardMove{After: "c"}, "bcade", nil},
+		"after last":    {"b", clientModel.CardMove{After: "e"}, "acdeb", nil},
+		"first":         {"e", clientModel.CardMove{Index: index(0)}, "eabcd", nil},
+		"last":          {"a", clientModel.CardMove{Index: index(4)}, "bcdea", nil},
+		"itself":        {"c", clientModel.CardMove{Before: "c"}, "abcde", nil},
+		"out of deck":   {"a", clientModel.CardMove{Index: index(5)}, "", ErrInvalidMove},
+		"two targets":   {"a", clientModel.CardMove{Before: "b", After: "c"}, "", ErrInvalidMove},
+		"no target":     {"a", clientModel.CardMove{}, "", ErrInvalidMove},
+		"missing card":  {"z", clientModel.CardMove{Before: "a"}, "", data.ErrNotFound},
+		"missing other": {"a", clientModel.CardMove{After: "z"}, "", data.ErrNotFound},
+	} {
+		s, decks, ctx := newDeck(t)
+		cards, err := s.MoveCard(ctx, "d", tc.card, tc.move)
+		if err != tc.err || (err == nil && ids(cards) != tc.want) {
+			t.Errorf("%s: %s (%v), want %s (%v)", name, ids(cards), err, tc.want, tc.err)
+			continue
+		}
+		// The order is the one stored.
+		if stored, _ := decks.GetCards(ctx, "d"); err == nil && ids(stored) != tc.want {
+			t.Errorf("%s: stored %s, want %s", name, ids(stored), tc.want)
+		}
+	}
+}
+
+func TestReorderCards(t *testing.T) {
+	s, _, ctx := newDeck(t)
+	cards, err := s.ReorderCards(ctx, "d", []string{"e", "d", "c", "b", "a"})
+	if err != nil || ids(cards) != "edcba" {
+		t.Fatalf("reordered %s (%v)", ids(cards), err)
+	}
+	for _, order := range [][]string{
+		{"a", "b", "c", "d"},
+		{"a", "b", "c", "d", "d"},
+		{"a", "b", "c", "d", "z"},
+	} {
+		if _, err := s.ReorderCards(ctx, "d", order); err != ErrInvalidOrder {
+			t.Errorf("order %v: %v, want %v", order, err, ErrInvalidOrder)
+		}
+	}
+}
+
+func TestShuffleCards(t *testing.T) {
+	s, _, ctx := newDeck(t)
+	seed := int64(42)
+	first, used, err := s.ShuffleCards(ctx, "d", &seed)
+	if err != nil || used != seed {
+		t.Fatalf("shuffled with seed %d (%v)", used, err)
+	}
+	// The same seed gives the same order back, whatever the current one.
+	if _, err := s.ReorderCards(ctx, "d", []string{"c", "e", "a", "d", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	again, _, _ := s.ShuffleCards(ctx, "d", &seed)
+	if ids(again) != ids(first) {
+		t.Fatalf("shuffled %s then %s with the same seed", ids(first), ids(again))
+	}
+	if _, used, _ := s.ShuffleCards(ctx, "d", nil); used == 0 {
+		t.Fatal("no seed returned")
+	}
+}
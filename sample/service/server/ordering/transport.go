@@ -0,0 +1,206 @@
+package ordering
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the card ordering endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /decks/:id/cards/:cardID:move       moves a Card before or after another one, or to an index
+	// POST    /decks/:id/cards:reorder            orders all of the Cards of a Deck
+	// POST    /decks/:id/cards:shuffle            shuffles the Cards of a Deck, with a seed
+
+	r.Methods("POST").Path("/decks/{id}/cards/{cardID}:move").Handler(httptransport.NewServer(
+		e.MoveCardEndpoint,
+		decodeMoveCardRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/decks/{id}/cards:reorder").Handler(httptransport.NewServer(
+		e.ReorderCardsEndpoint,
+		decodeReorderCardsRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/decks/{id}/cards:shuffle").Handler(httptransport.NewServer(
+		e.ShuffleCardsEndpoint,
+		decodeShuffleCardsRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeMoveCardRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	cardID, ok := vars["cardID"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	req := clientRequest.MoveCard{DeckID: id, CardID: cardID}
+	if e := json.NewDecoder(r.Body).Decode(&req.Move); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeReorderCardsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	var order clientModel.CardOrder
+	if e := json.NewDecoder(r.Body).Decode(&order); e != nil {
+		return nil, e
+	}
+	return clientRequest.ReorderCards{DeckID: id, CardIDs: order.CardIDs}, nil
+}
+
+func decodeShuffleCardsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	// The body is optional, a random seed being picked without it.
+	var shuffle clientModel.CardShuffle
+	if e := json.NewDecoder(r.Body).Decode(&shuffle); e != nil && e != io.EOF {
+		return nil, e
+	}
+	return clientRequest.ShuffleCards{DeckID: id, Seed: shuffle.Seed}, nil
+}
+
+func encodeMoveCardRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}/cards/{cardID}:move")
+	r := request.(clientRequest.MoveCard)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/cards/" + url.QueryEscape(r.CardID) + ":move"
+	return encodeBody(req, r.Move)
+}
+
+func encodeReorderCardsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}/cards:reorder")
+	r := request.(clientRequest.ReorderCards)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/cards:reorder"
+	return encodeBody(req, clientModel.CardOrder{CardIDs: r.CardIDs})
+}
+
+func encodeShuffleCardsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}/cards:shuffle")
+	r := request.(clientRequest.ShuffleCards)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/cards:shuffle"
+	return encodeBody(req, clientModel.CardShuffle{Seed: r.Seed})
+}
+
+func encodeBody(req *http.Request, body interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func decodeMoveCardResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.MoveCard
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeReorderCardsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.ReorderCards
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeShuffleCardsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.ShuffleCards
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrInvalidMove, ErrInvalidOrder} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrInvalidMove, ErrInvalidOrder:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
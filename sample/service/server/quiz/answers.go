@@ -0,0 +1,130 @@
+package quiz
+
+import (
+	mathrand "math/rand"
+	"sort"
+	"strings"
+	"unicode"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/duplicates"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/search"
+)
+
+// answer is a Second face or a typed answer, normalized for comparison.
+type answer struct {
+	text  string
+	runes []rune
+}
+
+func newAnswer(s string) answer {
+	text := search.Normalize(s)
+	return answer{text: text, runes: []rune(text)}
+}
+
+// matches tells whether a is expected, case, accents and punctuation being
+// ignored.
+func (a answer) matches(expected string) bool {
+	return a.text == newAnswer(expected).text
+}
+
+// close tells whether a is expected but for a few typos, as many as typos
+// allows.
+func (a answer) close(expected string) bool {
+	e := newAnswer(expected)
+	n := typos(len(e.runes))
+	return n > 0 && duplicates.EditDistance(a.runes, e.runes) <= n
+}
+
+// typos returns the number of typos allowed in answers of n runes: none
+// for short words, where a typo often makes another word.
+func typos(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 9:
+		return 1
+	}
+	return 2
+}
+
+// numeric tells whether a only holds numbers.
+func (a answer) numeric() bool {
+	for _, r := range a.runes {
+		if !unicode.IsDigit(r) && r != ' ' {
+			return false
+		}
+	}
+	return len(a.runes) > 0
+}
+
+// candidate is an answer of a Deck, drawn as distractor for the others.
+type candidate struct {
+	second string
+	answer answer
+	tags   []string
+}
+
+// pool holds the distinct answers of a Deck.
+type pool []candidate
+
+func newPool(cards []clientModel.Card) pool {
+	var p pool
+	seen := map[string]bool{}
+	for _, c := range cards {
+		a := newAnswer(c.Second)
+		if a.text == "" || seen[a.text] {
+			continue
+		}
+		seen[a.text] = true
+		p = append(p, candidate{second: c.Second, answer: a, tags: c.Tags})
+	}
+	return p
+}
+
+// distractors returns up to n wrong answers for c, the most plausible
+// ones: of Cards sharing its tags, of the same kind (numbers or words) and
+// of a similar length. Some randomness keeps quizzes from repeating.
+func (p pool) distractors(c clientModel.Card, n int, rnd *mathrand.Rand) []string {
+	want := newAnswer(c.Second)
+	type scored struct {
+		second string
+		score  float64
+	}
+	var all []scored
+	for _, o := range p {
+		if o.answer.text == want.text {
+			continue
+		}
+		all = append(all, scored{second: o.second, score: plausibility(c, want, o) + rnd.Float64()})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].score > all[j].score })
+	var res []string
+	for i := 0; i < len(all) && i < n; i++ {
+		res = append(res, all[i].second)
+	}
+	return res
+}
+
+// plausibility scores how likely o may be taken for want, the answer of c.
+func plausibility(c clientModel.Card, want answer, o candidate) float64 {
+	s := 0.0
+	for _, t := range c.Tags {
+		for _, u := range o.tags {
+			if t == u {
+				s++
+			}
+		}
+	}
+	if want.numeric() == o.answer.numeric() {
+		s += 2
+	}
+	if len(strings.Fields(want.text)) == len(strings.Fields(o.answer.text)) {
+		s++
+	}
+	a, b := len(want.runes), len(o.answer.runes)
+	if a < b {
+		a, b = b, a
+	}
+	return s + float64(b)/float64(a)
+}
@@ -0,0 +1,54 @@
+package quiz
+
+import (
+	mathrand "math/rand"
+	"testing"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+func TestAnswers(t *testing.T) {
+	for _, tc := range []struct {
+		typed, expected string
+		matches, close  bool
+	}{
+		{"Être!", "etre", true, false},
+		{"hopitl", "l'hôpital", false, false},
+		{"lhopitl", "l'hôpital", false, true},
+		{"the hospitel", "the hospital", false, true},
+		{"the hostpitel", "the hospital", false, true},
+		// Short answers allow no typo.
+		{"cat", "car", false, false},
+		{"chat", "chats", false, true},
+	} {
+		a := newAnswer(tc.typed)
+		if a.matches(tc.expected) != tc.matches || !tc.matches && a.close(tc.expected) != tc.close {
+			t.Errorf("%q for %q: matches %v, close %v", tc.typed, tc.expected, a.matches(tc.expected), a.close(tc.expected))
+		}
+	}
+}
+
+func TestDistractors(t *testing.T) {
+	p := newPool([]clientModel.Card{
+		{Second: "to be", Tags: []string{"verb"}},
+		{Second: "To be!"},
+		{Second: "to have", Tags: []string{"verb"}},
+		{Second: "1789"},
+		{Second: "a cat"},
+		{Second: ""},
+	})
+	if len(p) != 4 {
+		t.Fatalf("pool %+v, want 4 distinct answers", p)
+	}
+	rnd := mathrand.New(mathrand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		// Verbs sharing tags come first, a number is never drawn.
+		got := p.distractors(clientModel.Card{Second: "to go", Tags: []string{"verb"}}, 3, rnd)
+		if len(got) != 3 || got[2] != "a cat" {
+			t.Fatalf("distractors %q", got)
+		}
+	}
+	if got := p.distractors(clientModel.Card{Second: "to be"}, 5, rnd); len(got) != 3 {
+		t.Fatalf("distractors %q, the answer left out", got)
+	}
+}
@@ -0,0 +1,96 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the quiz API
+type Endpoints struct {
+	NewQuizEndpoint   endpoint.Endpoint
+	ScoreQuizEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		NewQuizEndpoint:   MakeNewQuizEndpoint(s),
+		ScoreQuizEndpoint: MakeScoreQuizEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		NewQuizEndpoint:   httptransport.NewClient("GET", tgt, encodeNewQuizRequest, decodeNewQuizResponse, options...).Endpoint(),
+		ScoreQuizEndpoint: httptransport.NewClient("POST", tgt, encodeScoreQuizRequest, decodeScoreQuizResponse, options...).Endpoint(),
+	}, nil
+}
+
+// NewQuiz implements Service. Primarily useful in a client.
+func (e Endpoints) NewQuiz(ctx context.Context, deckID string, quizType string, count int, tagFilter string) (clientModel.Quiz, error) {
+	response, err := e.NewQuizEndpoint(ctx, clientRequest.NewQuiz{DeckID: deckID, Type: quizType, Count: count, Tags: tagFilter})
+	if err != nil {
+		return clientModel.Quiz{}, err
+	}
+	resp, ok := response.(clientResponse.NewQuiz)
+	if !ok {
+		return clientModel.Quiz{}, ErrUnexpectedResponse
+	}
+	return resp.Quiz, resp.Err
+}
+
+// ScoreQuiz implements Service. Primarily useful in a client.
+func (e Endpoints) ScoreQuiz(ctx context.Context, quizID string, attempt clientModel.QuizAttempt) (clientModel.QuizResult, error) {
+	response, err := e.ScoreQuizEndpoint(ctx, clientRequest.ScoreQuiz{QuizID: quizID, Attempt: attempt})
+	if err != nil {
+		return clientModel.QuizResult{}, err
+	}
+	resp, ok := response.(clientResponse.ScoreQuiz)
+	if !ok {
+		return clientModel.QuizResult{}, ErrUnexpectedResponse
+	}
+	return resp.Result, resp.Err
+}
+
+// MakeNewQuizEndpoint returns an endpoint via the passed service.
+func MakeNewQuizEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.NewQuiz)
+		q, e := s.NewQuiz(ctx, req.DeckID, req.Type, req.Count, req.Tags)
+		return clientResponse.NewQuiz{Quiz: q, Err: e}, e
+	}
+}
+
+// MakeScoreQuizEndpoint returns an endpoint via the passed service.
+func MakeScoreQuizEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.ScoreQuiz)
+		r, e := s.ScoreQuiz(ctx, req.QuizID, req.Attempt)
+		return clientResponse.ScoreQuiz{Result: r, Err: e}, e
+	}
+}
@@ -0,0 +1,265 @@
+package quiz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	mathrand "math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+// Service generates quizzes from the Cards of Decks, and scores the
+// answers to them. Quizzes are kept per caller until answered or expired.
+type Service interface {
+	// NewQuiz draws up to count Cards of a Deck, among those matching the
+	// tags expression, into a Quiz of the given type.
+	NewQuiz(ctx context.Context, deckID string, quizType string, count int, tagFilter string) (clientModel.Quiz, error)
+	// ScoreQuiz scores an attempt at a Quiz, which cannot be answered again.
+	ScoreQuiz(ctx context.Context, quizID string, attempt clientModel.QuizAttempt) (clientModel.QuizResult, error)
+}
+
+const (
+	// DefaultCount is the number of Cards of a Quiz when not given.
+	DefaultCount = 10
+	// MaxCount bounds the number of Cards of a Quiz.
+	MaxCount = 100
+	// Choices is the number of choices of multiple choice questions, the
+	// answer included, fewer when the Deck lacks distractors.
+	Choices = 4
+	// MatchingSize is the number of pairs of matching questions.
+	MatchingSize = 5
+	// TTL is how long a Quiz can be answered.
+	TTL = 24 * time.Hour
+)
+
+var (
+	// ErrInvalidType : Quiz type other than multiple_choice, typing or matching
+	ErrInvalidType = errors.New("invalid quiz type, use multiple_choice, typing or matching")
+	// ErrInvalidCount : number of Cards out of 1..MaxCount
+	ErrInvalidCount = errors.New("invalid quiz count, use 1 to 100")
+	// ErrNotEnoughCards : Deck without Cards to ask, or without distinct
+	// answers to choose from
+	ErrNotEnoughCards = errors.New("not enough cards for this quiz")
+)
+
+// quiz is a Quiz pending its answers.
+type quiz struct {
+	owner    string
+	quiz     clientModel.Quiz
+	cardIDs  [][]string // per Question, one per Prompt
+	expected [][]string // per Question, one per Prompt
+}
+
+type inmemService struct {
+	mtx     sync.Mutex
+	decks   server.SampleService
+	quizzes map[string]*quiz
+}
+
+// NewInmemService In Memory Service Constructor. Cards are drawn from the
+// Decks of decks, as seen by the caller.
+func NewInmemService(decks server.SampleService) Service {
+	return &inmemService{
+		decks:   decks,
+		quizzes: map[string]*quiz{},
+	}
+}
+
+func (s *inmemService) NewQuiz(ctx context.Context, deckID string, quizType string, count int, tagFilter string) (clientModel.Quiz, error) {
+	switch quizType {
+	case "":
+		quizType = clientModel.QuizMultipleChoice
+	case clientModel.QuizMultipleChoice, clientModel.QuizTyping, clientModel.QuizMatching:
+	default:
+		return clientModel.Quiz{}, ErrInvalidType
+	}
+	if count == 0 {
+		count = DefaultCount
+	}
+	if count < 1 || count > MaxCount {
+		return clientModel.Quiz{}, ErrInvalidCount
+	}
+	expr, err := tags.Parse(tagFilter)
+	if err != nil {
+		return clientModel.Quiz{}, err
+	}
+	d, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return clientModel.Quiz{}, err
+	}
+
+	// Cards without answer are left out, those asked being drawn at random.
+	var cards []clientModel.Card
+	for _, c := range tags.FilterCards(d.Cards, d.Tags, expr) {
+		if newAnswer(c.Second).text != "" {
+			cards = append(cards, c)
+		}
+	}
+	rnd := mathrand.New(mathrand.NewSource(time.Now().UnixNano()))
+	rnd.Shuffle(len(cards), func(i, j int) { cards[i], cards[j] = cards[j], cards[i] })
+	if len(cards) > count {
+		cards = cards[:count]
+	}
+	pool := newPool(d.Cards)
+	if len(cards) == 0 || quizType != clientModel.QuizTyping && len(pool) < 2 ||
+		quizType == clientModel.QuizMatching && len(cards) < 2 {
+		return clientModel.Quiz{}, ErrNotEnoughCards
+	}
+
+	q := &quiz{
+		owner: auth.Subject(ctx),
+		quiz: clientModel.Quiz{
+			ID:        newID(),
+			DeckID:    d.ID,
+			Type:      quizType,
+			Questions: []clientModel.QuizQuestion{},
+			Expires:   time.Now().Add(TTL).UTC(),
+		},
+	}
+	add := func(question clientModel.QuizQuestion, asked []clientModel.Card) {
+		question.ID = strconv.Itoa(len(q.quiz.Questions) + 1)
+		var ids, expected []string
+		for _, c := range asked {
+			ids = append(ids, c.ID)
+			expected = append(expected, c.Second)
+		}
+		q.quiz.Questions = append(q.quiz.Questions, question)
+		q.cardIDs = append(q.cardIDs, ids)
+		q.expected = append(q.expected, expected)
+	}
+	switch quizType {
+	case clientModel.QuizMultipleChoice:
+		for _, c := range cards {
+			choices := append(pool.distractors(c, Choices-1, rnd), c.Second)
+			rnd.Shuffle(len(choices), func(i, j int) { choices[i], choices[j] = choices[j], choices[i] })
+			add(clientModel.QuizQuestion{Prompt: c.First, Choices: choices, Format: c.Format}, []clientModel.Card{c})
+		}
+	case clientModel.QuizTyping:
+		for _, c := range cards {
+			add(clientModel.QuizQuestion{Prompt: c.First, Format: c.Format}, []clientModel.Card{c})
+		}
+	case clientModel.QuizMatching:
+		for _, group := range groups(cards, MatchingSize) {
+			question := clientModel.QuizQuestion{Format: group[0].Format}
+			for _, c := range group {
+				question.Prompts = append(question.Prompts, c.First)
+				question.Choices = append(question.Choices, c.Second)
+			}
+			rnd.Shuffle(len(question.Choices), func(i, j int) {
+				question.Choices[i], question.Choices[j] = question.Choices[j], question.Choices[i]
+			})
+			add(question, group)
+		}
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := time.Now()
+	for id, pending := range s.quizzes {
+		if now.After(pending.quiz.Expires) {
+			delete(s.quizzes, id)
+		}
+	}
+	s.quizzes[q.quiz.ID] = q
+	return q.quiz, nil
+}
+
+func (s *inmemService) ScoreQuiz(ctx context.Context, quizID string, attempt clientModel.QuizAttempt) (clientModel.QuizResult, error) {
+	s.mtx.Lock()
+	q, ok := s.quizzes[quizID]
+	if ok && q.owner == auth.Subject(ctx) {
+		delete(s.quizzes, quizID)
+	}
+	s.mtx.Unlock()
+	if !ok || q.owner != auth.Subject(ctx) || time.Now().After(q.quiz.Expires) {
+		return clientModel.QuizResult{}, data.ErrNotFound
+	}
+
+	answers := map[string]clientModel.QuizAnswer{}
+	for _, a := range attempt.Answers {
+		answers[a.QuestionID] = a
+	}
+	res := clientModel.QuizResult{QuizID: q.quiz.ID, Questions: []clientModel.QuizQuestionResult{}}
+	for i, question := range q.quiz.Questions {
+		r := score(q.quiz.Type, question, q.expected[i], answers[question.ID])
+		r.QuestionID = question.ID
+		r.CardIDs = q.cardIDs[i]
+		r.Expected = q.expected[i]
+		res.Score += r.Score
+		res.MaxScore += r.MaxScore
+		res.Questions = append(res.Questions, r)
+	}
+	return res, nil
+}
+
+// score scores the answer a to question, expecting the Second faces
+// expected.
+func score(quizType string, question clientModel.QuizQuestion, expected []string, a clientModel.QuizAnswer) clientModel.QuizQuestionResult {
+	r := clientModel.QuizQuestionResult{MaxScore: len(expected)}
+	switch quizType {
+	case clientModel.QuizMultipleChoice:
+		if chosen, ok := choice(question, a.Choice); ok && newAnswer(chosen).matches(expected[0]) {
+			r.Score = 1
+		}
+	case clientModel.QuizTyping:
+		if a.Text != "" {
+			switch typed := newAnswer(a.Text); {
+			case typed.matches(expected[0]):
+				r.Score = 1
+			case typed.close(expected[0]):
+				r.Score, r.Close = 1, true
+			}
+		}
+	case clientModel.QuizMatching:
+		for j, want := range expected {
+			if j >= len(a.Matches) {
+				break
+			}
+			if chosen, ok := choice(question, &a.Matches[j]); ok && newAnswer(chosen).matches(want) {
+				r.Score++
+			}
+		}
+	}
+	r.Correct = r.Score == r.MaxScore
+	return r
+}
+
+// choice returns the choice of question at index i, if any.
+func choice(question clientModel.QuizQuestion, i *int) (string, bool) {
+	if i == nil || *i < 0 || *i >= len(question.Choices) {
+		return "", false
+	}
+	return question.Choices[*i], true
+}
+
+// groups splits cards into groups of size, merging a last lone Card into
+// the group before.
+func groups(cards []clientModel.Card, size int) [][]clientModel.Card {
+	var res [][]clientModel.Card
+	for len(cards) > 0 {
+		n := size
+		if len(cards) < n || len(cards) == n+1 {
+			n = len(cards)
+		}
+		res = append(res, cards[:n])
+		cards = cards[n:]
+	}
+	return res
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
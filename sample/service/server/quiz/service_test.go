@@ -0,0 +1,174 @@
+package quiz
+
+import (
+	"context"
+	"testing"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+var verbs = model.Deck{ID: "verbs", Cards: []model.Card{
+	{ID: "etre", First: "être", Second: "to be"},
+	{ID: "avoir", First: "avoir", Second: "to have"},
+	{ID: "aller", First: "aller", Second: "to go"},
+	{ID: "faire", First: "faire", Second: "to do", Tags: []string{"irregular"}},
+	{ID: "venir", First: "venir", Second: "to come"},
+	{ID: "blank", First: "blank"},
+}}
+
+// newQuizzes returns a Service over the Decks of alice, holding verbs, and
+// the context of alice.
+func newQuizzes(t *testing.T) (Service, context.Context) {
+	t.Helper()
+	ctx := auth.NewContext(context.Background(), auth.Principal{Subject: "alice"})
+	decks := server.NewService(inmem.NewInmemRepository())
+	if err := decks.PostDeck(ctx, verbs); err != nil {
+		t.Fatal(err)
+	}
+	return NewInmemService(decks), ctx
+}
+
+// second returns the Second face of the Card of verbs with the First face.
+func second(first string) string {
+	for _, c := range verbs.Cards {
+		if c.First == first {
+			return c.Second
+		}
+	}
+	return ""
+}
+
+func index(choices []string, s string) *int {
+	for i, c := range choices {
+		if c == s {
+			return &i
+		}
+	}
+	return nil
+}
+
+func TestMultipleChoice(t *testing.T) {
+	s, ctx := newQuizzes(t)
+	q, err := s.NewQuiz(ctx, "verbs", "", 0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cards without answer are not asked.
+	if q.Type != clientModel.QuizMultipleChoice || len(q.Questions) != 5 {
+		t.Fatalf("quiz %+v", q)
+	}
+	var attempt clientModel.QuizAttempt
+	for i, question := range q.Questions {
+		if len(question.Choices) != Choices {
+			t.Fatalf("question %+v", question)
+		}
+		a := clientModel.QuizAnswer{QuestionID: question.ID, Choice: index(question.Choices, second(question.Prompt))}
+		if i == 0 {
+			wrong := (*a.Choice + 1) % Choices
+			a.Choice = &wrong
+		}
+		attempt.Answers = append(attempt.Answers, a)
+	}
+	res, err := s.ScoreQuiz(ctx, q.ID, attempt)
+	if err != nil || res.Score != 4 || res.MaxScore != 5 || res.Questions[0].Correct {
+		t.Fatalf("result %+v (%v)", res, err)
+	}
+	if res.Questions[1].Expected[0] != second(q.Questions[1].Prompt) {
+		t.Fatalf("result %+v", res.Questions[1])
+	}
+
+	// Quizzes are answered once.
+	if _, err := s.ScoreQuiz(ctx, q.ID, attempt); err != data.ErrNotFound {
+		t.Fatalf("answered again: %v, want %v", err, data.ErrNotFound)
+	}
+}
+
+func TestTyping(t *testing.T) {
+	s, ctx := newQuizzes(t)
+	for _, tc := range []struct {
+		text  string
+		score int
+		close bool
+	}{
+		{"to make", 0, false},
+		{"to dp", 1, true},
+		{"To Do!", 1, false},
+	} {
+		q, err := s.NewQuiz(ctx, "verbs", clientModel.QuizTyping, 1, "irregular")
+		if err != nil || len(q.Questions) != 1 || q.Questions[0].Prompt != "faire" || len(q.Questions[0].Choices) != 0 {
+			t.Fatalf("quiz %+v (%v)", q, err)
+		}
+		res, err := s.ScoreQuiz(ctx, q.ID, clientModel.QuizAttempt{Answers: []clientModel.QuizAnswer{{QuestionID: "1", Text: tc.text}}})
+		if err != nil || res.Score != tc.score || res.Questions[0].Close != tc.close || res.Questions[0].CardIDs[0] != "faire" {
+			t.Errorf("%q: result %+v (%v)", tc.text, res, err)
+		}
+	}
+}
+
+func TestMatching(t *testing.T) {
+	s, ctx := newQuizzes(t)
+	q, err := s.NewQuiz(ctx, "verbs", clientModel.QuizMatching, 0, "")
+	if err != nil || len(q.Questions) != 1 || len(q.Questions[0].Prompts) != 5 {
+		t.Fatalf("quiz %+v (%v)", q, err)
+	}
+	question := q.Questions[0]
+	var matches []int
+	for _, p := range question.Prompts {
+		matches = append(matches, *index(question.Choices, second(p)))
+	}
+	matches = matches[:4] // the last Prompt is left unanswered
+	res, err := s.ScoreQuiz(ctx, q.ID, clientModel.QuizAttempt{Answers: []clientModel.QuizAnswer{{QuestionID: question.ID, Matches: matches}}})
+	if err != nil || res.Score != 4 || res.MaxScore != 5 || res.Questions[0].Correct {
+		t.Fatalf("result %+v (%v)", res, err)
+	}
+}
+
+func TestNewQuizErrors(t *testing.T) {
+	s, ctx := newQuizzes(t)
+	for _, tc := range []struct {
+		quizType  string
+		count     int
+		tagFilter string
+		err       error
+	}{
+		{"essay", 0, "", ErrInvalidType},
+		{"", -1, "", ErrInvalidCount},
+		{"", MaxCount + 1, "", ErrInvalidCount},
+		{"", 0, "nope", ErrNotEnoughCards},
+		{clientModel.QuizMatching, 0, "irregular", ErrNotEnoughCards},
+	} {
+		if _, err := s.NewQuiz(ctx, "verbs", tc.quizType, tc.count, tc.tagFilter); err != tc.err {
+			t.Errorf("%q %d %q: %v, want %v", tc.quizType, tc.count, tc.tagFilter, err, tc.err)
+		}
+	}
+	if _, err := s.NewQuiz(ctx, "missing", "", 0, ""); err != data.ErrNotFound {
+		t.Errorf("missing deck: %v, want %v", err, data.ErrNotFound)
+	}
+
+	// Quizzes are scored for their owner only.
+	q, _ := s.NewQuiz(ctx, "verbs", "", 1, "")
+	bob := auth.NewContext(context.Background(), auth.Principal{Subject: "bob"})
+	if _, err := s.ScoreQuiz(bob, q.ID, clientModel.QuizAttempt{}); err != data.ErrNotFound {
+		t.Errorf("scored by bob: %v, want %v", err, data.ErrNotFound)
+	}
+	if _, err := s.ScoreQuiz(ctx, q.ID, clientModel.QuizAttempt{}); err != nil {
+		t.Errorf("scored by alice after bob: %v", err)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	for n, want := range map[int][]int{1: {1}, 5: {5}, 6: {6}, 7: {5, 2}, 11: {5, 6}} {
+		var got []int
+		for _, g := range groups(make([]clientModel.Card, n), MatchingSize) {
+			got = append(got, len(g))
+		}
+		if len(got) != len(want) || got[0] != want[0] || got[len(got)-1] != want[len(want)-1] {
+			t.Errorf("%d cards in groups of %v, want %v", n, got, want)
+		}
+	}
+}
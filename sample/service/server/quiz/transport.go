@@ -0,0 +1,181 @@
+package quiz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the quiz endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// GET     /decks/:id/quiz?type=&count=&tags=  generates a Quiz from the Cards of a Deck
+	// POST    /quiz/:id/answers                   scores the answers to a Quiz
+
+	r.Methods("GET").Path("/decks/{id}/quiz").Handler(httptransport.NewServer(
+		e.NewQuizEndpoint,
+		decodeNewQuizRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/quiz/{id}/answers").Handler(httptransport.NewServer(
+		e.ScoreQuizEndpoint,
+		decodeScoreQuizRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeNewQuizRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	q := r.URL.Query()
+	req := clientRequest.NewQuiz{DeckID: id, Type: q.Get("type"), Tags: q.Get("tags")}
+	if count := q.Get("count"); count != "" {
+		if req.Count, err = strconv.Atoi(count); err != nil || req.Count == 0 {
+			return nil, ErrInvalidCount
+		}
+	}
+	return req, nil
+}
+
+func decodeScoreQuizRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	req := clientRequest.ScoreQuiz{QuizID: id}
+	if e := json.NewDecoder(r.Body).Decode(&req.Attempt); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func encodeNewQuizRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/quiz")
+	r := request.(clientRequest.NewQuiz)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/quiz"
+	q := url.Values{}
+	if r.Type != "" {
+		q.Set("type", r.Type)
+	}
+	if r.Count != 0 {
+		q.Set("count", strconv.Itoa(r.Count))
+	}
+	if r.Tags != "" {
+		q.Set("tags", r.Tags)
+	}
+	req.URL.RawQuery = q.Encode()
+	return nil
+}
+
+func encodeScoreQuizRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/quiz/{id}/answers")
+	r := request.(clientRequest.ScoreQuiz)
+	req.URL.Path = "/quiz/" + url.QueryEscape(r.QuizID) + "/answers"
+	return encodeBody(req, r.Attempt)
+}
+
+func encodeBody(req *http.Request, body interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func decodeNewQuizResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.NewQuiz
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeScoreQuizResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.ScoreQuiz
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrInvalidType, ErrInvalidCount, ErrNotEnoughCards} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrInvalidType, ErrInvalidCount:
+		return http.StatusBadRequest
+	case ErrNotEnoughCards:
+		return http.StatusUnprocessableEntity
+	default:
+		return server.HTTPStatus(err)
+	}
+}
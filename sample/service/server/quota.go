@@ -0,0 +1,12 @@
+package server
+
+import "errors"
+
+var (
+	// ErrDeckQuotaExceeded : the caller already owns the maximum number of Decks
+	ErrDeckQuotaExceeded = errors.New("deck quota exceeded")
+	// ErrCardQuotaExceeded : the Deck already holds the maximum number of Cards
+	ErrCardQuotaExceeded = errors.New("card quota exceeded")
+	// ErrPayloadTooLarge : the Deck or Card exceeds the maximum payload size
+	ErrPayloadTooLarge = errors.New("payload too large")
+)
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Policy is a token bucket holding up to Burst requests, refilled with Rate
+// requests per second.
+type Policy struct {
+	Rate  float64
+	Burst int
+}
+
+// Route applies a Policy to the requests of Method (any method if empty)
+// whose path starts with Prefix.
+type Route struct {
+	Method string
+	Prefix string
+	Policy
+}
+
+// ParseRoutes parses comma separated "[METHOD ]PREFIX=RATE:BURST" routes,
+// such as "POST /decks=1:5,/=10:20".
+func ParseRoutes(s string) ([]Route, error) {
+	var routes []Route
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		i := strings.LastIndex(spec, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("rate limit route %q: missing =RATE:BURST", spec)
+		}
+		var r Route
+		if f := strings.Fields(spec[:i]); len(f) == 1 {
+			r.Prefix = f[0]
+		} else if len(f) == 2 {
+			r.Method, r.Prefix = strings.ToUpper(f[0]), f[1]
+		} else {
+			return nil, fmt.Errorf("rate limit route %q: expecting [METHOD ]PREFIX", spec)
+		}
+		if !strings.HasPrefix(r.Prefix, "/") {
+			return nil, fmt.Errorf("rate limit route %q: prefix must start with /", spec)
+		}
+		p := strings.SplitN(spec[i+1:], ":", 2)
+		if len(p) != 2 {
+			return nil, fmt.Errorf("rate limit route %q: expecting RATE:BURST", spec)
+		}
+		var err error
+		if r.Rate, err = strconv.ParseFloat(p[0], 64); err != nil || r.Rate <= 0 {
+			return nil, fmt.Errorf("rate limit route %q: invalid rate %q", spec, p[0])
+		}
+		if r.Burst, err = strconv.Atoi(p[1]); err != nil || r.Burst < 1 {
+			return nil, fmt.Errorf("rate limit route %q: invalid burst %q", spec, p[1])
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+// Result tells whether a request is allowed, and the state of its bucket.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed, if not Allowed
+}
+
+// Limiter keeps a token bucket per Route and client. The buckets of idle
+// clients are dropped once full again.
+type Limiter struct {
+	routes []Route
+
+	mtx       sync.Mutex
+	buckets   map[bucketKey]*rate.Limiter
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucketKey struct {
+	route  int
+	client string
+}
+
+// sweepEvery is the period of the removal of full buckets.
+const sweepEvery = time.Minute
+
+// NewLimiter returns a Limiter applying the most specific of routes to each
+// request: the longest prefix, routes with a method first.
+func NewLimiter(routes []Route) *Limiter {
+	routes = append([]Route(nil), routes...)
+	sort.SliceStable(routes, func(i, j int) bool {
+		if len(routes[i].Prefix) != len(routes[j].Prefix) {
+			return len(routes[i].Prefix) > len(routes[j].Prefix)
+		}
+		return routes[i].Method != "" && routes[j].Method == ""
+	})
+	return &Limiter{
+		routes:  routes,
+		buckets: map[bucketKey]*rate.Limiter{},
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of client for the route of method and
+// path. ok is false when no route applies.
+func (l *Limiter) Allow(method string, path string, client string) (res Result, ok bool) {
+	i := l.route(method, path)
+	if i < 0 {
+		return Result{}, false
+	}
+	p := l.routes[i].Policy
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := l.now()
+	l.sweep(now)
+	k := bucketKey{route: i, client: client}
+	b, found := l.buckets[k]
+	if !found {
+		b = rate.NewLimiter(rate.Limit(p.Rate), p.Burst)
+		l.buckets[k] = b
+	}
+
+	res = Result{Allowed: b.AllowN(now, 1), Limit: p.Burst}
+	tokens := b.TokensAt(now)
+	if tokens > 0 {
+		res.Remaining = int(tokens)
+	}
+	res.Reset = seconds((float64(p.Burst) - tokens) / p.Rate)
+	if !res.Allowed {
+		res.RetryAfter = seconds((1 - tokens) / p.Rate)
+	}
+	return res, true
+}
+
+func (l *Limiter) route(method string, path string) int {
+	for i, r := range l.routes {
+		if (r.Method == "" || r.Method == method) && strings.HasPrefix(path, r.Prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sweep drops the full buckets, which are no different from new ones. It
+// must be called with the lock held.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepEvery {
+		return
+	}
+	l.lastSweep = now
+	for k, b := range l.buckets {
+		if b.TokensAt(now) >= float64(b.Burst()) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
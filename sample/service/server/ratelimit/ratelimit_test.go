@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("post /decks=1:5, /=10:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Route{
+		{Method: "POST", Prefix: "/decks", Policy: Policy{Rate: 1, Burst: 5}},
+		{Prefix: "/", Policy: Policy{Rate: 10, Burst: 20}},
+	}
+	if len(routes) != len(want) || routes[0] != want[0] || routes[1] != want[1] {
+		t.Fatalf("parsed %+v, want %+v", routes, want)
+	}
+	for _, spec := range []string{"/decks", "decks=1:5", "/=0:5", "/=1:0", "/=1", "GET POST /=1:1"} {
+		if _, err := ParseRoutes(spec); err == nil {
+			t.Errorf("parsed %q", spec)
+		}
+	}
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter([]Route{
+		{Prefix: "/", Policy: Policy{Rate: 10, Burst: 20}},
+		{Method: "POST", Prefix: "/decks", Policy: Policy{Rate: 1, Burst: 2}},
+	})
+	l.now = func() time.Time { return now }
+
+	if _, ok := l.Allow("GET", "/metrics", "ip:1"); !ok {
+		t.Fatal("no route for /metrics")
+	}
+	for i := 0; i < 2; i++ {
+		if res, _ := l.Allow("POST", "/decks/d/cards", "ip:1"); !res.Allowed || res.Remaining != 1-i {
+			t.Fatalf("request %d: %+v", i, res)
+		}
+	}
+	res, _ := l.Allow("POST", "/decks", "ip:1")
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 2*time.Second {
+		t.Fatalf("over the burst: %+v", res)
+	}
+	// Other clients and other routes have their own buckets.
+	if res, _ := l.Allow("POST", "/decks", "ip:2"); !res.Allowed {
+		t.Fatalf("other client: %+v", res)
+	}
+	if res, _ := l.Allow("GET", "/decks", "ip:1"); !res.Allowed || res.Limit != 20 {
+		t.Fatalf("other route: %+v", res)
+	}
+
+	now = now.Add(time.Second)
+	if res, _ := l.Allow("POST", "/decks", "ip:1"); !res.Allowed {
+		t.Fatalf("after a refill: %+v", res)
+	}
+}
+
+func TestLimiterDropsFullBuckets(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewLimiter([]Route{{Prefix: "/", Policy: Policy{Rate: 1, Burst: 1}}})
+	l.now = func() time.Time { return now }
+	l.Allow("GET", "/", "ip:1")
+	now = now.Add(sweepEvery)
+	l.Allow("GET", "/", "ip:2")
+	if _, ok := l.buckets[bucketKey{client: "ip:1"}]; ok || len(l.buckets) != 1 {
+		t.Fatalf("buckets %v, want only ip:2", l.buckets)
+	}
+}
@@ -0,0 +1,209 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+// HTTPHandler answers 429 Too Many Requests, with a Retry-After header, to
+// clients exceeding the rate of the route of their request, and passes the
+// others to next. Every limited response carries the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers.
+//
+// Clients are told apart as ClientKey does. HTTPHandler goes in front of
+// auth.HTTPHandler, so that requests without valid credentials are limited
+// too: it authenticates requests itself with a, when not nil.
+func HTTPHandler(l *Limiter, a *auth.Authenticator, proxies Proxies, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok := l.Allow(r.Method, r.URL.Path, ClientKey(r, a, proxies))
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		w.Header().Set("RateLimit-Reset", ceilSeconds(res.Reset))
+		if !res.Allowed {
+			w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "rate limit exceeded",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnaryServerInterceptor applies the limits of l to gRPC calls as to the HTTP
+// requests of the same route, answering ResourceExhausted to clients
+// exceeding them. route returns the HTTP method and path a request stands
+// for, empty strings for calls never limited. The rate limit headers of
+// HTTPHandler are sent as metadata (ratelimit-limit, ratelimit-remaining,
+// ratelimit-reset and retry-after).
+//
+// Clients are told apart as GRPCClientKey does. Like HTTPHandler, the
+// interceptor goes in front of auth.UnaryServerInterceptor and authenticates
+// calls itself with a, when not nil.
+func UnaryServerInterceptor(l *Limiter, a *auth.Authenticator, route func(req interface{}) (method string, path string)) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method, path := route(req)
+		if method == "" {
+			return handler(ctx, req)
+		}
+		res, ok := l.Allow(method, path, GRPCClientKey(ctx, a))
+		if !ok {
+			return handler(ctx, req)
+		}
+		md := metadata.Pairs(
+			"ratelimit-limit", strconv.Itoa(res.Limit),
+			"ratelimit-remaining", strconv.Itoa(res.Remaining),
+			"ratelimit-reset", ceilSeconds(res.Reset),
+		)
+		if !res.Allowed {
+			md.Set("retry-after", ceilSeconds(res.RetryAfter))
+			grpc.SetHeader(ctx, md)
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		grpc.SetHeader(ctx, md)
+		return handler(ctx, req)
+	}
+}
+
+// GRPCClientKey identifies the client of a gRPC call as ClientKey does, from
+// its authorization or x-api-key metadata. The address is always the one of
+// the peer: gRPC calls carry no X-Forwarded-For.
+func GRPCClientKey(ctx context.Context, a *auth.Authenticator) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return "principal:" + p.Subject
+	}
+	if a != nil {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if p, err := a.Authenticate(first(md.Get("authorization")), first(md.Get("x-api-key"))); err == nil {
+			return "principal:" + p.Subject
+		}
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "ip:"
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+	return "ip:" + host
+}
+
+// ClientKey identifies the client of r: "principal:{subject}" when
+// authenticated, by auth.HTTPHandler or by a, "ip:{address}" otherwise.
+//
+// The address is the one of the peer, unless it is one of the trusted
+// proxies: X-Forwarded-For is then read from the right, the address being
+// the last one appended by a trusted proxy. The entries on its left are set
+// by the client and never trusted.
+func ClientKey(r *http.Request, a *auth.Authenticator, proxies Proxies) string {
+	if p, ok := auth.FromContext(r.Context()); ok {
+		return "principal:" + p.Subject
+	}
+	if a != nil {
+		if p, err := a.Authenticate(r.Header.Get("Authorization"), r.Header.Get(auth.APIKeyHeader)); err == nil {
+			return "principal:" + p.Subject
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !proxies.contains(host) {
+		return "ip:" + host
+	}
+	var forwarded []string
+	for _, f := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(f, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break // not appended by a proxy of ours
+		}
+		host = hop
+		if !proxies.contains(hop) {
+			break
+		}
+	}
+	return "ip:" + host
+}
+
+// Proxies lists the networks of the reverse proxies trusted to append the
+// address of their peer to X-Forwarded-For.
+type Proxies []*net.IPNet
+
+// ParseProxies parses comma separated IP addresses or CIDR networks, such as
+// "10.0.0.0/8,192.0.2.1".
+func ParseProxies(s string) (Proxies, error) {
+	var proxies Proxies
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if !strings.Contains(spec, "/") {
+			ip := net.ParseIP(spec)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy %q: invalid IP address", spec)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(spec)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: invalid network", spec)
+		}
+		proxies = append(proxies, n)
+	}
+	return proxies, nil
+}
+
+func (p Proxies) contains(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range p {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+func authenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := ioutil.WriteFile(path, []byte("alice "+auth.HashAPIKey("a")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := auth.LoadAPIKeys(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &auth.Authenticator{APIKeys: keys}
+}
+
+func TestClientKey(t *testing.T) {
+	a := authenticator(t)
+	proxies, err := ParseProxies("10.0.0.0/8, 192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		remote, forwarded, apiKey string
+		want                      string
+	}{
+		"authenticated":   {"198.51.100.7:1234", "", "a", "principal:alice"},
+		"invalid key":     {"198.51.100.7:1234", "", "wrong", "ip:198.51.100.7"},
+		"untrusted peer":  {"198.51.100.7:1234", "203.0.113.9", "", "ip:198.51.100.7"},
+		"trusted proxy":   {"10.1.2.3:1234", "203.0.113.9", "", "ip:203.0.113.9"},
+		"proxy chain":     {"10.1.2.3:1234", "203.0.113.9, 192.0.2.1", "", "ip:203.0.113.9"},
+		"spoofed entries": {"10.1.2.3:1234", "1.2.3.4, 203.0.113.9", "", "ip:203.0.113.9"},
+		"garbage":         {"10.1.2.3:1234", "unknown", "", "ip:10.1.2.3"},
+	} {
+		r := httptest.NewRequest("GET", "/decks", nil)
+		r.RemoteAddr = tc.remote
+		if tc.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", tc.forwarded)
+		}
+		if tc.apiKey != "" {
+			r.Header.Set(auth.APIKeyHeader, tc.apiKey)
+		}
+		if got := ClientKey(r, a, proxies); got != tc.want {
+			t.Errorf("%s: %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	l := NewLimiter([]Route{{Method: "POST", Prefix: "/decks", Policy: Policy{Rate: 1, Burst: 1}}})
+	h := HTTPHandler(l, nil, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(method string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "/decks", nil)
+		r.RemoteAddr = "198.51.100.7:1234"
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve("POST"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("first request: %d %v", w.Code, w.Header())
+	}
+	w := serve("POST")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" || w.Header().Get("RateLimit-Limit") != "1" {
+		t.Fatalf("over the limit: %d %v", w.Code, w.Header())
+	}
+	if w := serve("GET"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "" {
+		t.Fatalf("unlimited route: %d %v", w.Code, w.Header())
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	a := authenticator(t)
+	l := NewLimiter([]Route{{Method: "POST", Prefix: "/decks", Policy: Policy{Rate: 1, Burst: 1}}})
+	route := func(req interface{}) (string, string) {
+		if req == "limited" {
+			return "POST", "/decks"
+		}
+		return "", ""
+	}
+	interceptor := UnaryServerInterceptor(l, a, route)
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.Sample/PostDeck"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return req, nil }
+	call := func(ctx context.Context, req string) error {
+		_, err := interceptor(ctx, req, info, handler)
+		return err
+	}
+
+	anonymous := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("198.51.100.7"), Port: 1234}})
+	if err := call(anonymous, "limited"); err != nil {
+		t.Fatal(err)
+	}
+	if err := call(anonymous, "limited"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("over the limit: %v, want ResourceExhausted", err)
+	}
+	if err := call(anonymous, "other"); err != nil {
+		t.Fatalf("unlimited call: %v", err)
+	}
+
+	// Authenticated clients have their own buckets, shared with HTTP.
+	alice := metadata.NewIncomingContext(anonymous, metadata.Pairs("x-api-key", "a"))
+	if err := call(alice, "limited"); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := l.Allow("POST", "/decks", "principal:alice"); res.Allowed {
+		t.Fatal("HTTP requests do not share the bucket of gRPC calls")
+	}
+}
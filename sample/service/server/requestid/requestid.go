@@ -0,0 +1,74 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Header carries the request ID of HTTP requests and responses.
+const Header = "X-Request-ID"
+
+// maxLen bounds the length of request IDs accepted from callers.
+const maxLen = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// New returns a random request ID.
+func New() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HTTPHandler stores the X-Request-ID of incoming requests in their context,
+// generating one when the header is missing or malformed, and echoes it in
+// the response.
+func HTTPHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !valid(id) {
+			id = New()
+		}
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// HTTPClientBefore is a go-kit http RequestFunc forwarding the request ID of
+// ctx, if any, to the outgoing request.
+func HTTPClientBefore(ctx context.Context, r *http.Request) context.Context {
+	if id := FromContext(ctx); id != "" {
+		r.Header.Set(Header, id)
+	}
+	return ctx
+}
+
+// valid accepts short IDs made of letters, digits and -_.: only, so they are
+// safe to echo and to log.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
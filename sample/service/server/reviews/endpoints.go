@@ -0,0 +1,96 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the reviews API
+type Endpoints struct {
+	PostReviewEndpoint endpoint.Endpoint
+	GetReviewsEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		PostReviewEndpoint: MakePostReviewEndpoint(s),
+		GetReviewsEndpoint: MakeGetReviewsEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		PostReviewEndpoint: httptransport.NewClient("POST", tgt, encodePostReviewRequest, decodePostReviewResponse, options...).Endpoint(),
+		GetReviewsEndpoint: httptransport.NewClient("GET", tgt, encodeGetReviewsRequest, decodeGetReviewsResponse, options...).Endpoint(),
+	}, nil
+}
+
+// PostReview implements Service. Primarily useful in a client.
+func (e Endpoints) PostReview(ctx context.Context, r clientModel.Review) (clientModel.Review, error) {
+	response, err := e.PostReviewEndpoint(ctx, clientRequest.PostReview{Review: r})
+	if err != nil {
+		return clientModel.Review{}, err
+	}
+	resp, ok := response.(clientResponse.PostReview)
+	if !ok {
+		return clientModel.Review{}, ErrUnexpectedResponse
+	}
+	return resp.Review, resp.Err
+}
+
+// GetReviews implements Service. Primarily useful in a client.
+func (e Endpoints) GetReviews(ctx context.Context, deckID string) ([]clientModel.Review, error) {
+	response, err := e.GetReviewsEndpoint(ctx, clientRequest.GetReviews{DeckID: deckID})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.GetReviews)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Reviews, resp.Err
+}
+
+// MakePostReviewEndpoint returns an endpoint via the passed service.
+func MakePostReviewEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.PostReview)
+		r, e := s.PostReview(ctx, req.Review)
+		return clientResponse.PostReview{Review: r, Err: e}, e
+	}
+}
+
+// MakeGetReviewsEndpoint returns an endpoint via the passed service.
+func MakeGetReviewsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetReviews)
+		r, e := s.GetReviews(ctx, req.DeckID)
+		return clientResponse.GetReviews{Reviews: r, Err: e}, e
+	}
+}
@@ -0,0 +1,79 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+)
+
+// Service records study reviews of Cards.
+type Service interface {
+	PostReview(ctx context.Context, r clientModel.Review) (clientModel.Review, error)
+	GetReviews(ctx context.Context, deckID string) ([]clientModel.Review, error)
+}
+
+var (
+	// ErrInvalidGrade : Review grade out of the GradeAgain..GradeEasy range
+	ErrInvalidGrade = errors.New("invalid grade")
+)
+
+type inmemService struct {
+	mtx     sync.RWMutex
+	decks   server.SampleService
+	reviews map[reviewKey][]clientModel.Review
+}
+
+// reviewKey scopes reviews by reviewer and Deck, the Deck by its owner
+// and ID whichever reference it was reviewed through.
+type reviewKey struct {
+	reviewer string
+	owner    string
+	deckID   string
+}
+
+func newReviewKey(ctx context.Context, deckID string) reviewKey {
+	owner, id := server.ParseDeckRef(ctx, deckID)
+	return reviewKey{reviewer: auth.Subject(ctx), owner: owner, deckID: id}
+}
+
+// NewInmemService In Memory Service Constructor. Reviewed Cards are looked
+// up in decks, reviews are kept per reviewer.
+func NewInmemService(decks server.SampleService) Service {
+	return &inmemService{
+		decks:   decks,
+		reviews: map[reviewKey][]clientModel.Review{},
+	}
+}
+
+func (s *inmemService) PostReview(ctx context.Context, r clientModel.Review) (clientModel.Review, error) {
+	if r.Grade < clientModel.GradeAgain || r.Grade > clientModel.GradeEasy {
+		return clientModel.Review{}, ErrInvalidGrade
+	}
+	if _, err := s.decks.GetCard(ctx, r.DeckID, r.CardID); err != nil {
+		return clientModel.Review{}, err
+	}
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	r.Time = r.Time.UTC()
+	k := newReviewKey(ctx, r.DeckID)
+	r.DeckID = server.DeckRef(ctx, k.owner, k.deckID)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.reviews[k] = append(s.reviews[k], r)
+	return r, nil
+}
+
+func (s *inmemService) GetReviews(ctx context.Context, deckID string) ([]clientModel.Review, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	k := newReviewKey(ctx, deckID)
+	res := make([]clientModel.Review, len(s.reviews[k]))
+	copy(res, s.reviews[k])
+	return res, nil
+}
@@ -0,0 +1,152 @@
+package reviews
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrMissingDeck is returned when listing reviews without a deck parameter.
+	ErrMissingDeck = errors.New("missing deck parameter")
+)
+
+// MakeHTTPHandler mounts all of the reviews endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /reviews                         records a Card review
+	// GET     /reviews?deck=:id                retrieves the reviews of a Deck
+
+	r.Methods("POST").Path("/reviews").Handler(httptransport.NewServer(
+		e.PostReviewEndpoint,
+		decodePostReviewRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/reviews").Handler(httptransport.NewServer(
+		e.GetReviewsEndpoint,
+		decodeGetReviewsRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodePostReviewRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var review clientModel.Review
+	if e := json.NewDecoder(r.Body).Decode(&review); e != nil {
+		return nil, e
+	}
+	return clientRequest.PostReview{Review: review}, nil
+}
+
+func decodeGetReviewsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	deckID := r.URL.Query().Get("deck")
+	if deckID == "" {
+		return nil, ErrMissingDeck
+	}
+	return clientRequest.GetReviews{DeckID: deckID}, nil
+}
+
+func encodePostReviewRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/reviews")
+	r := request.(clientRequest.PostReview)
+	req.URL.Path = "/reviews"
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(r.Review); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func encodeGetReviewsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/reviews")
+	r := request.(clientRequest.GetReviews)
+	req.URL.Path = "/reviews"
+	q := req.URL.Query()
+	q.Set("deck", r.DeckID)
+	req.URL.RawQuery = q.Encode()
+	return nil
+}
+
+func decodePostReviewResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.PostReview
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetReviewsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetReviews
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrInvalidGrade, ErrMissingDeck} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrInvalidGrade, ErrMissingDeck:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
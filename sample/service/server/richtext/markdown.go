@@ -0,0 +1,246 @@
+package richtext
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Markdown renders a subset of Markdown as HTML: paragraphs, headings,
+// emphasis, links, images, block quotes, lists, rules, code spans and
+// fenced code blocks, whose language hint becomes a language-* class.
+// Math is passed through for client side typesetting: $inline$ as
+// \(...\) in a math inline span, $$display$$ as \[...\] in a math display
+// block. Raw HTML is escaped, and the result still needs sanitising for
+// its URLs.
+func Markdown(src string) string {
+	var b strings.Builder
+	blocks(&b, strings.Split(strings.Replace(src, "\r\n", "\n", -1), "\n"))
+	return b.String()
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*$`)
+	rulePattern    = regexp.MustCompile(`^(?:-(?:\s*-){2,}|\*(?:\s*\*){2,}|_(?:\s*_){2,})$`)
+	bulletPattern  = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	numberPattern  = regexp.MustCompile(`^\d{1,9}[.)]\s+(.*)$`)
+	langPattern    = regexp.MustCompile(`^[A-Za-z0-9_+#.-]+$`)
+)
+
+func blocks(b *strings.Builder, lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + inline(strings.Join(para, "\n")) + "</p>\n")
+			para = nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~"):
+			flush()
+			fence := line[:3]
+			b.WriteString("<pre><code")
+			if lang := strings.Fields(line[3:]); len(lang) > 0 && langPattern.MatchString(lang[0]) {
+				b.WriteString(` class="language-` + lang[0] + `"`)
+			}
+			b.WriteString(">")
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString(html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case strings.HasPrefix(line, "$$"):
+			flush()
+			var math []string
+			if rest := line[2:]; strings.HasSuffix(rest, "$$") {
+				math = append(math, strings.TrimSuffix(rest, "$$"))
+			} else {
+				math = append(math, rest)
+				for i++; i < len(lines); i++ {
+					l := strings.TrimSpace(lines[i])
+					if strings.HasSuffix(l, "$$") {
+						math = append(math, strings.TrimSuffix(l, "$$"))
+						break
+					}
+					math = append(math, lines[i])
+				}
+			}
+			b.WriteString(`<div class="math display">\[` + html.EscapeString(strings.TrimSpace(strings.Join(math, "\n"))) + `\]</div>` + "\n")
+
+		case headingPattern.MatchString(line):
+			flush()
+			m := headingPattern.FindStringSubmatch(line)
+			level := strconv.Itoa(len(m[1]))
+			b.WriteString("<h" + level + ">" + inline(m[2]) + "</h" + level + ">\n")
+
+		case rulePattern.MatchString(line):
+			flush()
+			b.WriteString("<hr>\n")
+
+		case strings.HasPrefix(line, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				l := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(l, " "))
+			}
+			i--
+			b.WriteString("<blockquote>\n")
+			blocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+
+		case bulletPattern.MatchString(line) || numberPattern.MatchString(line):
+			flush()
+			item, tag := bulletPattern, "ul"
+			if !bulletPattern.MatchString(line) {
+				item, tag = numberPattern, "ol"
+			}
+			var items []string
+			for ; i < len(lines); i++ {
+				l := strings.TrimSpace(lines[i])
+				if m := item.FindStringSubmatch(l); m != nil {
+					items = append(items, m[1])
+				} else if len(items) > 0 && l != "" && (lines[i][0] == ' ' || lines[i][0] == '\t') {
+					items[len(items)-1] += "\n" + l // continuation line
+				} else {
+					break
+				}
+			}
+			i--
+			b.WriteString("<" + tag + ">\n")
+			for _, it := range items {
+				b.WriteString("<li>" + inline(it) + "</li>\n")
+			}
+			b.WriteString("</" + tag + ">\n")
+
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+}
+
+// inline renders the spans of a block.
+func inline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!$<>|~", s[i+1]) >= 0:
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			if end := strings.Index(s[i+n:], s[i:i+n]); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(strings.TrimSpace(s[i+n:i+n+end])) + "</code>")
+				i += n + end + n
+				continue
+			}
+
+		case strings.HasPrefix(s[i:], "$$"):
+			if end := strings.Index(s[i+2:], "$$"); end > 0 {
+				b.WriteString(`<span class="math display">\[` + html.EscapeString(s[i+2:i+2+end]) + `\]</span>`)
+				i += end + 4
+				continue
+			}
+
+		case c == '$':
+			if end := strings.IndexByte(s[i+1:], '$'); end > 0 && s[i+1] != ' ' && s[i+end] != ' ' {
+				b.WriteString(`<span class="math inline">\(` + html.EscapeString(s[i+1:i+1+end]) + `\)</span>`)
+				i += end + 2
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(s[i+1:], "["):
+			if text, dest, n, ok := link(s[i+1:]); ok {
+				b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(text) + `">`)
+				i += 1 + n
+				continue
+			}
+
+		case c == '[':
+			if text, dest, n, ok := link(s[i:]); ok {
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `">` + inline(text) + "</a>")
+				i += n
+				continue
+			}
+
+		case (c == '*' || c == '_') && !(c == '_' && i > 0 && isWordByte(s[i-1])):
+			if delim := s[i : i+1]; strings.HasPrefix(s[i+1:], delim) {
+				if end := strings.Index(s[i+2:], delim+delim); end > 0 {
+					b.WriteString("<strong>" + inline(s[i+2:i+2+end]) + "</strong>")
+					i += end + 4
+					continue
+				}
+			} else if end := strings.Index(s[i+1:], delim); end > 0 && s[i+1] != ' ' {
+				b.WriteString("<em>" + inline(s[i+1:i+1+end]) + "</em>")
+				i += end + 2
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// link reads [text](destination "title") at the start of s, returning its
+// text, its destination and its length.
+func link(s string) (text string, dest string, n int, ok bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if !strings.HasPrefix(s[i+1:], "(") {
+				return "", "", 0, false
+			}
+			end := closingParen(s[i+2:])
+			if end < 0 {
+				return "", "", 0, false
+			}
+			fields := strings.Fields(s[i+2 : i+2+end])
+			if len(fields) == 0 {
+				return "", "", 0, false
+			}
+			return s[1:i], strings.Trim(fields[0], "<>"), i + 3 + end, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// closingParen returns the index of the parenthesis closing s, parentheses
+// within being balanced, or -1.
+func closingParen(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,89 @@
+package richtext
+
+import (
+	"errors"
+	"html"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// Formats of the faces of Cards. Faces without format are plain text.
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// RenderHTML renders faces as sanitised HTML, the only rendering so far.
+const RenderHTML = "html"
+
+var (
+	// ErrInvalidFormat : Card face format other than plain, markdown or html
+	ErrInvalidFormat = errors.New("invalid card format, use plain, markdown or html")
+	// ErrInvalidRender : rendering other than html
+	ErrInvalidRender = errors.New("invalid render, use html")
+)
+
+// NormalizeFormat returns format lower-cased, failing with ErrInvalidFormat
+// for unknown formats.
+func NormalizeFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case "", FormatPlain, FormatMarkdown, FormatHTML:
+		return format, nil
+	}
+	return "", ErrInvalidFormat
+}
+
+// Render returns text, a face in format, as sanitised HTML. Plain text is
+// escaped, Markdown rendered and HTML stripped of what is not allowed.
+func Render(format string, text string) string {
+	switch format {
+	case FormatMarkdown:
+		return Sanitize(Markdown(text))
+	case FormatHTML:
+		return Sanitize(text)
+	}
+	return strings.Replace(html.EscapeString(text), "\n", "<br>\n", -1)
+}
+
+// CheckRender fails with ErrInvalidRender for unknown renderings. An empty
+// render leaves faces as stored.
+func CheckRender(render string) error {
+	if render != "" && render != RenderHTML {
+		return ErrInvalidRender
+	}
+	return nil
+}
+
+// RenderCards returns cards with their faces rendered as render asks,
+// as is when render is empty.
+func RenderCards(cards []clientModel.Card, render string) []clientModel.Card {
+	if render == "" || cards == nil {
+		return cards
+	}
+	res := make([]clientModel.Card, len(cards))
+	for i, c := range cards {
+		res[i] = RenderCard(c, render)
+	}
+	return res
+}
+
+// RenderCard returns c with its faces rendered as render asks, as is when
+// render is empty.
+func RenderCard(c clientModel.Card, render string) clientModel.Card {
+	if render == "" {
+		return c
+	}
+	c.First = Render(c.Format, c.First)
+	c.Second = Render(c.Format, c.Second)
+	c.Format = FormatHTML
+	return c
+}
+
+// RenderDeck returns d with the faces of its Cards rendered as render asks.
+func RenderDeck(d clientModel.Deck, render string) clientModel.Deck {
+	d.Cards = RenderCards(d.Cards, render)
+	return d
+}
@@ -0,0 +1,149 @@
+package richtext
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// allowed lists the elements kept by Sanitize, with their attributes.
+var allowed = map[string][]string{
+	"a": {"href", "title"}, "img": {"src", "alt", "title"},
+	"p": nil, "br": nil, "hr": nil, "blockquote": nil, "pre": nil,
+	"code": {"class"}, "span": {"class"}, "div": {"class"},
+	"b": nil, "strong": nil, "i": nil, "em": nil, "u": nil, "s": nil, "del": nil, "sub": nil, "sup": nil,
+	"ul": nil, "ol": nil, "li": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"table": nil, "thead": nil, "tbody": nil, "tr": nil, "th": nil, "td": nil,
+}
+
+// dropped lists the elements removed along with their content.
+var dropped = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "frame": true,
+	"frameset": true, "noscript": true, "template": true, "svg": true, "math": true, "textarea": true,
+	"select": true, "title": true, "head": true,
+}
+
+// void lists the allowed elements without end tags.
+var void = map[string]bool{"br": true, "hr": true, "img": true}
+
+var (
+	classPattern = regexp.MustCompile(`^[A-Za-z0-9 _-]+$`)
+	mediaPattern = regexp.MustCompile(`^media:([0-9a-f]{64})$`)
+)
+
+// Sanitize keeps the text of s and the elements and attributes allowed,
+// scripts, styles and embedded content being dropped. Links and images
+// only keep http, https (and mailto for links) or relative URLs, media
+// references becoming their download URL. Unclosed elements are closed.
+func Sanitize(s string) string {
+	z := xhtml.NewTokenizer(strings.NewReader(s))
+	var b strings.Builder
+	var open []string
+	skip := 0 // depth within dropped elements
+	for {
+		tt := z.Next()
+		switch tt {
+		case xhtml.ErrorToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				b.WriteString("</" + open[i] + ">")
+			}
+			return b.String()
+
+		case xhtml.TextToken:
+			if skip == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			t := z.Token()
+			if dropped[t.Data] {
+				if tt == xhtml.StartTagToken {
+					skip++
+				}
+				continue
+			}
+			attrs, ok := allowed[t.Data]
+			if skip > 0 || !ok {
+				continue
+			}
+			b.WriteString("<" + t.Data)
+			for _, a := range t.Attr {
+				if v, ok := attribute(t.Data, a, attrs); ok {
+					b.WriteString(" " + a.Key + `="` + html.EscapeString(v) + `"`)
+				}
+			}
+			if t.Data == "a" {
+				b.WriteString(` rel="nofollow noopener noreferrer"`)
+			}
+			b.WriteString(">")
+			if !void[t.Data] && tt == xhtml.StartTagToken {
+				open = append(open, t.Data)
+			}
+
+		case xhtml.EndTagToken:
+			t := z.Token()
+			if dropped[t.Data] {
+				if skip > 0 {
+					skip--
+				}
+				continue
+			}
+			if skip > 0 {
+				continue
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == t.Data {
+					for j := len(open) - 1; j >= i; j-- {
+						b.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+}
+
+// attribute returns the value kept for attribute a of element, if any.
+func attribute(element string, a xhtml.Attribute, attrs []string) (string, bool) {
+	if a.Namespace != "" {
+		return "", false
+	}
+	for _, name := range attrs {
+		if a.Key != name {
+			continue
+		}
+		switch name {
+		case "href", "src":
+			return safeURL(a.Val, name == "href")
+		case "class":
+			return a.Val, classPattern.MatchString(a.Val)
+		}
+		return a.Val, true
+	}
+	return "", false
+}
+
+// safeURL returns raw when it is relative or uses a safe scheme, and the
+// download URL of media references.
+func safeURL(raw string, link bool) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if m := mediaPattern.FindStringSubmatch(raw); m != nil {
+		return "/media/" + m[1], true
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https":
+		return raw, true
+	case "mailto":
+		return raw, link
+	}
+	return "", false
+}
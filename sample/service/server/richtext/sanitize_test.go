@@ -0,0 +1,75 @@
+package richtext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	for _, tc := range []struct {
+		in, want string
+	}{
+		// Scripts and embedded content go along with their content.
+		{`<p>hi<script>alert(1)</script></p>`, `<p>hi</p>`},
+		{`<SCRIPT src="x.js"></SCRIPT>ok`, `ok`},
+		{`<scr<script></script>ipt>alert(1)</script>`, `ipt&gt;alert(1)`},
+		{`<style>p{}</style><iframe src="x"></iframe><svg onload="alert(1)"><text>x</text></svg>ok`, `ok`},
+		// Raw text elements end at their end tag, even within an attribute.
+		{`<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>`, `<img src="x">&#34;&gt;`},
+		// Event handlers and other attributes are dropped.
+		{`<img src="a.png" onerror="alert(1)">`, `<img src="a.png">`},
+		{`<p onclick="alert(1)" style="color:red">x</p>`, `<p>x</p>`},
+		{`<b ONMOUSEOVER=alert(1)>x</b>`, `<b>x</b>`},
+		{`<span class="math inline" id="x">x</span>`, `<span class="math inline">x</span>`},
+		{`<span class="x&quot; onclick=&quot;y">x</span>`, `<span>x</span>`},
+		// Only safe URL schemes are kept.
+		{`<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{`<a href=" JaVaScRiPt:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{`<a href="javascript&colon;alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{`<a href="jav&#x09;ascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{`<a href="vbscript:msgbox">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{`<img src="data:image/svg+xml;base64,PHN2Zz4=">`, `<img>`},
+		{`<img src="mailto:a@example.com">`, `<img>`},
+		{`<a href="mailto:a@example.com">x</a>`, `<a href="mailto:a@example.com" rel="nofollow noopener noreferrer">x</a>`},
+		{`<a href="https://example.com/?a=1&amp;b=2">x</a>`, `<a href="https://example.com/?a=1&amp;b=2" rel="nofollow noopener noreferrer">x</a>`},
+		{`<img src="media:` + hash + `" alt="x">`, `<img src="/media/` + hash + `" alt="x">`},
+		// Text is escaped, unknown elements unwrapped and open ones closed.
+		{`1 < 2 & <unknown>3</unknown>`, `1 &lt; 2 &amp; 3`},
+		{`<ul><li><b>x`, `<ul><li><b>x</b></li></ul>`},
+		{`<i>x</b>y</i>`, `<i>xy</i>`},
+	} {
+		if got := Sanitize(tc.in); got != tc.want {
+			t.Errorf("Sanitize(%q)\n got %q\nwant %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, tc := range []struct {
+		format, text string
+		want         string
+	}{
+		{"", "<b>1 < 2</b>\nx", "&lt;b&gt;1 &lt; 2&lt;/b&gt;<br>\nx"},
+		{FormatHTML, `<b onclick="x()">bold</b>`, `<b>bold</b>`},
+		{FormatMarkdown, `[x](javascript:alert(1))`, `<p><a rel="nofollow noopener noreferrer">x</a></p>`},
+		{FormatMarkdown, `<script>alert(1)</script>`, `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`},
+		{FormatMarkdown, `![x](media:` + strings.Repeat("0", 64) + `)`, `<p><img src="/media/` + strings.Repeat("0", 64) + `" alt="x"></p>`},
+	} {
+		if got := strings.TrimSpace(Render(tc.format, tc.text)); got != tc.want {
+			t.Errorf("Render(%q, %q)\n got %q\nwant %q", tc.format, tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeFormat(t *testing.T) {
+	if f, err := NormalizeFormat(" Markdown "); err != nil || f != FormatMarkdown {
+		t.Fatalf("normalized %q (%v)", f, err)
+	}
+	if _, err := NormalizeFormat("rtf"); err != ErrInvalidFormat {
+		t.Fatalf("rtf: %v, want %v", err, ErrInvalidFormat)
+	}
+	if err := CheckRender("pdf"); err != ErrInvalidRender {
+		t.Fatalf("pdf: %v, want %v", err, ErrInvalidRender)
+	}
+}
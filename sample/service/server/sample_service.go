@@ -18,4 +18,17 @@ type SampleService interface {
 	GetCard(ctx context.Context, DeckID string, CardID string) (client.Card, error)
 	PostCard(ctx context.Context, DeckID string, a model.Card) error
 	DeleteCard(ctx context.Context, DeckID string, CardID string) error
+	// ApplyDecks posts, puts and deletes Decks, in order, as a whole: when
+	// one of the writes fails, none is stored and its error is returned.
+	ApplyDecks(ctx context.Context, writes []DeckWrite) error
+}
+
+// DeckWrite is one of the writes of ApplyDecks. Kind is one of
+// data.WritePostDeck, data.WritePutDeck and data.WriteDeleteDeck, ID
+// references the Deck the way the other methods do, and Deck is the content
+// posted or put.
+type DeckWrite struct {
+	Kind string
+	ID   string
+	Deck model.Deck
 }
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the search API
+type Endpoints struct {
+	SearchEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		SearchEndpoint: MakeSearchEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		SearchEndpoint: httptransport.NewClient("GET", tgt, encodeSearchRequest, decodeSearchResponse, options...).Endpoint(),
+	}, nil
+}
+
+// Search implements Service. Primarily useful in a client.
+func (e Endpoints) Search(ctx context.Context, query string, tagFilter string, limit int) ([]clientModel.SearchHit, error) {
+	response, err := e.SearchEndpoint(ctx, clientRequest.Search{Query: query, Tags: tagFilter, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.Search)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Hits, resp.Err
+}
+
+// MakeSearchEndpoint returns an endpoint via the passed service.
+func MakeSearchEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.Search)
+		h, e := s.Search(ctx, req.Query, req.Tags, req.Limit)
+		return clientResponse.Search{Hits: h, Err: e}, e
+	}
+}
@@ -0,0 +1,364 @@
+package search
+
+import (
+	"context"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+// Fields of the indexed documents: the name of Decks, the faces of Cards.
+const (
+	FieldName   = "name"
+	FieldFirst  = "first"
+	FieldSecond = "second"
+)
+
+// fieldWeights boost matches in Deck names.
+var fieldWeights = map[string]float64{
+	FieldName:   2,
+	FieldFirst:  1,
+	FieldSecond: 1,
+}
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// prefixWeight discounts the occurrences matched by a prefix only.
+const prefixWeight = 0.5
+
+// snippetContext is the number of bytes kept before the first match of
+// long fields, snippetLength the length of their snippets.
+const (
+	snippetContext = 40
+	snippetLength  = 160
+)
+
+// docKey identifies a document: a Deck when card is empty, a Card
+// otherwise.
+type docKey struct {
+	owner string
+	deck  string
+	card  string
+}
+
+type deckKey struct {
+	owner string
+	deck  string
+}
+
+type document struct {
+	fields map[string]string
+	tokens map[string][]token
+	length int
+	tags   []string
+}
+
+// Hit is a document matching a query, with its matches highlighted by
+// <mark> tags in the HTML escaped Snippets of its fields.
+type Hit struct {
+	Owner    string
+	DeckID   string
+	CardID   string
+	Score    float64
+	Snippets map[string]string
+}
+
+// Index is an in-memory inverted index of Deck names and Card faces.
+type Index struct {
+	mtx      sync.RWMutex
+	docs     map[docKey]*document
+	decks    map[deckKey]map[string]struct{} // Card IDs per Deck
+	postings map[string]map[docKey]struct{}
+	length   int // tokens of all documents
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	x := &Index{}
+	x.reset()
+	return x
+}
+
+func (x *Index) reset() {
+	x.docs = map[docKey]*document{}
+	x.decks = map[deckKey]map[string]struct{}{}
+	x.postings = map[string]map[docKey]struct{}{}
+	x.length = 0
+}
+
+// Rebuild replaces the content of the Index with the Decks of repo.
+func (x *Index) Rebuild(ctx context.Context, repo data.SampleRepository) error {
+	decks, err := repo.GetAllDecks(ctx)
+	if err != nil {
+		return err
+	}
+	x.mtx.Lock()
+	defer x.mtx.Unlock()
+	x.reset()
+	for _, d := range decks {
+		x.putDeck(d.Owner, d)
+	}
+	return nil
+}
+
+// PutDeck indexes the Deck of owner and its Cards, replacing any previous
+// version.
+func (x *Index) PutDeck(owner string, d model.Deck) {
+	x.mtx.Lock()
+	defer x.mtx.Unlock()
+	x.putDeck(owner, d)
+}
+
+// DeleteDeck removes the Deck of owner and its Cards.
+func (x *Index) DeleteDeck(owner string, deckID string) {
+	x.mtx.Lock()
+	defer x.mtx.Unlock()
+	x.deleteDeck(owner, deckID)
+}
+
+// PutCard indexes a Card of the Deck of owner, replacing any previous
+// version.
+func (x *Index) PutCard(owner string, deckID string, c model.Card) {
+	x.mtx.Lock()
+	defer x.mtx.Unlock()
+	x.putCard(owner, deckID, c)
+}
+
+// DeleteCard removes a Card of the Deck of owner.
+func (x *Index) DeleteCard(owner string, deckID string, cardID string) {
+	x.mtx.Lock()
+	defer x.mtx.Unlock()
+	x.remove(docKey{owner: owner, deck: deckID, card: cardID})
+	delete(x.decks[deckKey{owner: owner, deck: deckID}], cardID)
+}
+
+func (x *Index) putDeck(owner string, d model.Deck) {
+	x.deleteDeck(owner, d.ID)
+	x.decks[deckKey{owner: owner, deck: d.ID}] = map[string]struct{}{}
+	x.add(docKey{owner: owner, deck: d.ID}, map[string]string{FieldName: d.Name}, d.Tags)
+	for _, c := range d.Cards {
+		x.putCard(owner, d.ID, c)
+	}
+}
+
+func (x *Index) deleteDeck(owner string, deckID string) {
+	k := deckKey{owner: owner, deck: deckID}
+	for cardID := range x.decks[k] {
+		x.remove(docKey{owner: owner, deck: deckID, card: cardID})
+	}
+	delete(x.decks, k)
+	x.remove(docKey{owner: owner, deck: deckID})
+}
+
+func (x *Index) putCard(owner string, deckID string, c model.Card) {
+	k := docKey{owner: owner, deck: deckID, card: c.ID}
+	x.remove(k)
+	cards, ok := x.decks[deckKey{owner: owner, deck: deckID}]
+	if !ok {
+		cards = map[string]struct{}{}
+		x.decks[deckKey{owner: owner, deck: deckID}] = cards
+	}
+	cards[c.ID] = struct{}{}
+	x.add(k, map[string]string{FieldFirst: c.First, FieldSecond: c.Second}, c.Tags)
+}
+
+func (x *Index) add(k docKey, fields map[string]string, docTags []string) {
+	doc := &document{fields: fields, tokens: map[string][]token{}, tags: docTags}
+	for field, text := range fields {
+		tokens := tokenize(text)
+		doc.tokens[field] = tokens
+		doc.length += len(tokens)
+		for _, t := range tokens {
+			if x.postings[t.term] == nil {
+				x.postings[t.term] = map[docKey]struct{}{}
+			}
+			x.postings[t.term][k] = struct{}{}
+		}
+	}
+	x.docs[k] = doc
+	x.length += doc.length
+}
+
+func (x *Index) remove(k docKey) {
+	doc, ok := x.docs[k]
+	if !ok {
+		return
+	}
+	for _, tokens := range doc.tokens {
+		for _, t := range tokens {
+			delete(x.postings[t.term], k)
+			if len(x.postings[t.term]) == 0 {
+				delete(x.postings, t.term)
+			}
+		}
+	}
+	x.length -= doc.length
+	delete(x.docs, k)
+}
+
+// span is a match in a field.
+type span struct {
+	start, end int
+}
+
+// Search returns the best limit documents matching all the clauses of
+// query among the Decks for which visible is true, best first, and whose
+// tags match filter when not nil (Cards inheriting those of their Deck).
+// Queries are made of words, "quoted phrases", and words with a trailing *
+// matching as prefixes, ignoring case and accents. Matches are ranked with
+// BM25, Deck names weighing more than Card faces, and prefix matches less
+// than exact ones.
+func (x *Index) Search(query string, visible func(owner string, deckID string) bool, filter tags.Expr, limit int) []Hit {
+	clauses := parseQuery(query)
+	if len(clauses) == 0 {
+		return []Hit{}
+	}
+	x.mtx.RLock()
+	defer x.mtx.RUnlock()
+
+	n := float64(len(x.docs))
+	avgLength := float64(x.length) / math.Max(n, 1)
+	scores := map[docKey]float64{}
+	spans := map[docKey]map[string][]span{}
+	for i, c := range clauses {
+		// Occurrences of c, weighted by field and exactness, per document.
+		tf := map[docKey]float64{}
+		for k := range x.candidates(c) {
+			if i > 0 {
+				if _, ok := scores[k]; !ok {
+					continue
+				}
+			}
+			if !visible(k.owner, k.deck) || (filter != nil && !filter.Match(x.tagsOf(k))) {
+				continue
+			}
+			doc := x.docs[k]
+			for field, tokens := range doc.tokens {
+				for j := range tokens {
+					ok, exact := c.match(tokens, j)
+					if !ok {
+						continue
+					}
+					w := fieldWeights[field]
+					if !exact {
+						w *= prefixWeight
+					}
+					tf[k] += w
+					if spans[k] == nil {
+						spans[k] = map[string][]span{}
+					}
+					spans[k][field] = append(spans[k][field], span{start: tokens[j].start, end: tokens[j+len(c.terms)-1].end})
+				}
+			}
+		}
+		df := float64(len(tf))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		next := map[docKey]float64{}
+		for k, f := range tf {
+			norm := f * (k1 + 1) / (f + k1*(1-b+b*float64(x.docs[k].length)/avgLength))
+			next[k] = scores[k] + idf*norm*float64(len(c.terms))
+		}
+		scores = next
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for k, score := range scores {
+		h := Hit{Owner: k.owner, DeckID: k.deck, CardID: k.card, Score: score, Snippets: map[string]string{}}
+		for field, s := range spans[k] {
+			h.Snippets[field] = snippet(x.docs[k].fields[field], s)
+		}
+		hits = append(hits, h)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].DeckID != hits[j].DeckID {
+			return hits[i].DeckID < hits[j].DeckID
+		}
+		return hits[i].CardID < hits[j].CardID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// tagsOf returns the tags of a document, along with those of its Deck for
+// Cards.
+func (x *Index) tagsOf(k docKey) []string {
+	doc := x.docs[k]
+	if k.card == "" {
+		return doc.tags
+	}
+	var deck []string
+	if d, ok := x.docs[docKey{owner: k.owner, deck: k.deck}]; ok {
+		deck = d.tags
+	}
+	return append(append([]string(nil), doc.tags...), deck...)
+}
+
+// candidates returns the documents holding the first term of c.
+func (x *Index) candidates(c clause) map[docKey]struct{} {
+	first := c.terms[0]
+	if !c.prefix || len(c.terms) > 1 {
+		return x.postings[first]
+	}
+	res := map[docKey]struct{}{}
+	for term, docs := range x.postings {
+		if strings.HasPrefix(term, first) {
+			for k := range docs {
+				res[k] = struct{}{}
+			}
+		}
+	}
+	return res
+}
+
+// snippet returns text, or an extract of it around its first match when
+// long, HTML escaped with its matches in <mark> tags.
+func snippet(text string, spans []span) string {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	from, to := 0, len(text)
+	if len(text) > snippetLength {
+		if from = spans[0].start - snippetContext; from < 0 {
+			from = 0
+		}
+		from = runeStart(text, from)
+		if to = from + snippetLength; to < len(text) {
+			to = runeStart(text, to)
+		} else {
+			to = len(text)
+		}
+	}
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	at := from
+	for _, s := range spans {
+		if s.start < at || s.end > to {
+			continue // overlapping or out of the extract
+		}
+		b.WriteString(html.EscapeString(text[at:s.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[s.start:s.end]))
+		b.WriteString("</mark>")
+		at = s.end
+	}
+	b.WriteString(html.EscapeString(text[at:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+func everything(string, string) bool { return true }
+
+// newIndex indexes a few Decks of alice and bob.
+func newIndex() *Index {
+	x := NewIndex()
+	x.PutDeck("alice", model.Deck{ID: "verbs", Name: "French verbs", Tags: []string{"fr"}, Cards: []model.Card{
+		{ID: "etre", First: "être", Second: "to be"},
+		{ID: "avoir", First: "avoir", Second: "to have", Tags: []string{"irregular"}},
+		{ID: "verbe", First: "le verbe", Second: "the verb"},
+	}})
+	x.PutDeck("alice", model.Deck{ID: "misc", Name: "Misc", Cards: []model.Card{
+		{ID: "b", First: "bee", Second: "to be or not to be"},
+	}})
+	x.PutDeck("bob", model.Deck{ID: "verbs", Name: "Bob's verbs"})
+	return x
+}
+
+func ids(hits []Hit) string {
+	var res []string
+	for _, h := range hits {
+		res = append(res, h.Owner+"/"+h.DeckID+"/"+h.CardID)
+	}
+	return strings.Join(res, " ")
+}
+
+func TestSearch(t *testing.T) {
+	x := newIndex()
+	alice := func(owner string, _ string) bool { return owner == "alice" }
+	for _, tc := range []struct {
+		query string
+		want  string
+	}{
+		// Deck names weigh more than Card faces.
+		{"verb", "alice/verbs/verbe"},
+		{"verbs", "alice/verbs/"},
+		{"verb*", "alice/verbs/ alice/verbs/verbe"},
+		{"ETRE", "alice/verbs/etre"},
+		{`"to be"`, "alice/misc/b alice/verbs/etre"},
+		{`"be to"`, ""},
+		{"to have", "alice/verbs/avoir"},
+		{"nothing", ""},
+	} {
+		if got := ids(x.Search(tc.query, alice, nil, 0)); got != tc.want {
+			t.Errorf("%q: %s, want %s", tc.query, got, tc.want)
+		}
+	}
+
+	if got := ids(x.Search("verbs", everything, nil, 0)); got != "alice/verbs/ bob/verbs/" && got != "bob/verbs/ alice/verbs/" {
+		t.Errorf("visible to all: %s", got)
+	}
+	if got := x.Search("be", alice, nil, 1); len(got) != 1 {
+		t.Errorf("limit 1: %d hits", len(got))
+	}
+}
+
+func TestSearchTagFilter(t *testing.T) {
+	x := newIndex()
+	// Cards inherit the tags of their Deck.
+	e, _ := tags.Parse("fr AND NOT irregular")
+	if got := ids(x.Search("to*", everything, e, 0)); got != "alice/verbs/etre" {
+		t.Fatalf("filtered %s", got)
+	}
+}
+
+func TestIndexUpdates(t *testing.T) {
+	x := newIndex()
+	x.DeleteCard("alice", "verbs", "etre")
+	x.PutCard("alice", "misc", model.Card{ID: "b", First: "bee", Second: "abeille"})
+	if got := ids(x.Search(`"to be"`, everything, nil, 0)); got != "" {
+		t.Fatalf("after updates: %s", got)
+	}
+	x.DeleteDeck("alice", "verbs")
+	if got := ids(x.Search("avoir", everything, nil, 0)); got != "" {
+		t.Fatalf("cards of a deleted deck: %s", got)
+	}
+	// Only the 6 tokens of "Misc", "bee", "abeille" and "Bob's verbs" are left.
+	if x.length != 6 {
+		t.Fatalf("length %d after updates", x.length)
+	}
+}
+
+func TestSnippets(t *testing.T) {
+	x := NewIndex()
+	long := strings.Repeat("filler ", 30) + "the <b>café</b> " + strings.Repeat("filler ", 30)
+	x.PutDeck("alice", model.Deck{ID: "d", Cards: []model.Card{{ID: "c", First: long, Second: "Cafe"}}})
+	hits := x.Search("cafe", everything, nil, 0)
+	if len(hits) != 1 {
+		t.Fatalf("hits %+v", hits)
+	}
+	first := hits[0].Snippets[FieldFirst]
+	if !strings.HasPrefix(first, "…") || !strings.HasSuffix(first, "…") || !strings.Contains(first, "&lt;b&gt;<mark>café</mark>&lt;/b&gt;") {
+		t.Errorf("snippet of a long face %q", first)
+	}
+	if got := hits[0].Snippets[FieldSecond]; got != "<mark>Cafe</mark>" {
+		t.Errorf("snippet of a short face %q", got)
+	}
+}
@@ -0,0 +1,73 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// clause is a sequence of terms to be found next to each other. Single
+// words are clauses of one term. The last term matches as a prefix when
+// prefix is set.
+type clause struct {
+	terms  []string
+	prefix bool
+}
+
+// parseQuery splits a query into clauses, all of which must match:
+// "quoted phrases", words, and words with a trailing * matching as
+// prefixes. Words made of several tokens, such as "ice-cream", are
+// phrases.
+func parseQuery(q string) []clause {
+	var clauses []clause
+	add := func(text string) {
+		prefix := strings.HasSuffix(strings.TrimRightFunc(text, unicode.IsSpace), "*")
+		var terms []string
+		for _, t := range tokenize(text) {
+			terms = append(terms, t.term)
+		}
+		if len(terms) > 0 {
+			clauses = append(clauses, clause{terms: terms, prefix: prefix})
+		}
+	}
+	for q != "" {
+		if strings.HasPrefix(q, `"`) {
+			end := strings.Index(q[1:], `"`)
+			if end < 0 {
+				add(q[1:])
+				break
+			}
+			add(q[1 : end+1])
+			q = q[end+2:]
+			continue
+		}
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		end := strings.IndexFunc(q, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		if end < 0 {
+			end = len(q)
+		}
+		add(q[:end])
+		q = q[end:]
+	}
+	return clauses
+}
+
+// match tells whether c occurs in tokens at i, and whether all of its terms
+// match exactly rather than as a prefix.
+func (c clause) match(tokens []token, i int) (ok bool, exact bool) {
+	if i+len(c.terms) > len(tokens) {
+		return false, false
+	}
+	exact = true
+	for j, term := range c.terms {
+		got := tokens[i+j].term
+		if got == term {
+			continue
+		}
+		if c.prefix && j == len(c.terms)-1 && strings.HasPrefix(got, term) {
+			exact = false
+			continue
+		}
+		return false, false
+	}
+	return true, exact
+}
@@ -0,0 +1,44 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		want  []clause
+	}{
+		{"", nil},
+		{`  "" * `, nil},
+		{"Été  verbs", []clause{{terms: []string{"ete"}}, {terms: []string{"verbs"}}}},
+		{"verb*", []clause{{terms: []string{"verb"}, prefix: true}}},
+		{`"to be" or`, []clause{{terms: []string{"to", "be"}}, {terms: []string{"or"}}}},
+		{`"to b*"`, []clause{{terms: []string{"to", "b"}, prefix: true}}},
+		{"ice-cream", []clause{{terms: []string{"ice", "cream"}}}},
+		{`"unterminated phrase`, []clause{{terms: []string{"unterminated", "phrase"}}}},
+		{`a"b c"`, []clause{{terms: []string{"a"}}, {terms: []string{"b", "c"}}}},
+	} {
+		if got := parseQuery(tc.query); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: %+v, want %+v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	text := "L'Été, c'est «chaud»!"
+	var terms []string
+	for _, tok := range tokenize(text) {
+		terms = append(terms, tok.term)
+		if fold(text[tok.start:tok.end]) != tok.term {
+			t.Errorf("%q at %d:%d, want %q", text[tok.start:tok.end], tok.start, tok.end, tok.term)
+		}
+	}
+	if want := []string{"l", "ete", "c", "est", "chaud"}; !reflect.DeepEqual(terms, want) {
+		t.Fatalf("terms %q, want %q", terms, want)
+	}
+	if got := Normalize("  Crème   BRÛLÉE "); got != "creme brulee" {
+		t.Fatalf("normalized %q", got)
+	}
+}
@@ -0,0 +1,82 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/sharing"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+// Service searches the Decks and Cards of the caller, and those shared with
+// it, optionally restricted to those matching a tag expression.
+type Service interface {
+	Search(ctx context.Context, query string, tagFilter string, limit int) ([]clientModel.SearchHit, error)
+}
+
+// Bounds of the number of hits of a search.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	// ErrMissingQuery : empty search query
+	ErrMissingQuery = errors.New("missing query")
+	// ErrInvalidLimit : limit out of the 0..MaxLimit range
+	ErrInvalidLimit = errors.New("invalid limit")
+)
+
+type indexService struct {
+	index *Index
+	acl   sharing.Authorizer
+}
+
+// NewService Index Service Constructor. Decks shared through acl are
+// searched too, unless acl is nil.
+func NewService(index *Index, acl sharing.Authorizer) Service {
+	return &indexService{
+		index: index,
+		acl:   acl,
+	}
+}
+
+// Search returns the best limit hits of query, DefaultLimit when zero.
+func (s *indexService) Search(ctx context.Context, query string, tagFilter string, limit int) ([]clientModel.SearchHit, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, ErrMissingQuery
+	}
+	if limit < 0 || limit > MaxLimit {
+		return nil, ErrInvalidLimit
+	}
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	var filter tags.Expr
+	if tagFilter != "" {
+		expr, err := tags.Parse(tagFilter)
+		if err != nil {
+			return nil, err
+		}
+		filter = expr
+	}
+	caller := auth.Subject(ctx)
+	visible := func(owner string, deckID string) bool {
+		return owner == caller || (s.acl != nil && s.acl.Role(ctx, owner, deckID, caller) != "")
+	}
+	hits := s.index.Search(query, visible, filter, limit)
+	res := make([]clientModel.SearchHit, 0, len(hits))
+	for _, h := range hits {
+		res = append(res, clientModel.SearchHit{
+			DeckID:   server.DeckRef(ctx, h.Owner, h.DeckID),
+			CardID:   h.CardID,
+			Score:    h.Score,
+			Snippets: h.Snippets,
+		})
+	}
+	return res, nil
+}
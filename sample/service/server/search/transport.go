@@ -0,0 +1,117 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+// MakeHTTPHandler mounts all of the search endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// GET     /search?q=:query&tags=:expr&limit=:limit    searches Deck names and Card faces
+
+	r.Methods("GET").Path("/search").Handler(httptransport.NewServer(
+		e.SearchEndpoint,
+		decodeSearchRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeSearchRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	q := r.URL.Query()
+	req := clientRequest.Search{Query: q.Get("q"), Tags: q.Get("tags")}
+	if l := q.Get("limit"); l != "" {
+		if req.Limit, err = strconv.Atoi(l); err != nil {
+			return nil, ErrInvalidLimit
+		}
+	}
+	return req, nil
+}
+
+func encodeSearchRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/search")
+	r := request.(clientRequest.Search)
+	req.URL.Path = "/search"
+	q := req.URL.Query()
+	q.Set("q", r.Query)
+	if r.Tags != "" {
+		q.Set("tags", r.Tags)
+	}
+	if r.Limit != 0 {
+		q.Set("limit", strconv.Itoa(r.Limit))
+	}
+	req.URL.RawQuery = q.Encode()
+	return nil
+}
+
+func decodeSearchResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.Search
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrMissingQuery, ErrInvalidLimit} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrMissingQuery, ErrInvalidLimit:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
@@ -0,0 +1,221 @@
+package sharing
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the sharing API
+type Endpoints struct {
+	PostMemberEndpoint      endpoint.Endpoint
+	GetMembersEndpoint      endpoint.Endpoint
+	DeleteMemberEndpoint    endpoint.Endpoint
+	PostShareLinkEndpoint   endpoint.Endpoint
+	GetShareLinksEndpoint   endpoint.Endpoint
+	DeleteShareLinkEndpoint endpoint.Endpoint
+	GetSharedDeckEndpoint   endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		PostMemberEndpoint:      MakePostMemberEndpoint(s),
+		GetMembersEndpoint:      MakeGetMembersEndpoint(s),
+		DeleteMemberEndpoint:    MakeDeleteMemberEndpoint(s),
+		PostShareLinkEndpoint:   MakePostShareLinkEndpoint(s),
+		GetShareLinksEndpoint:   MakeGetShareLinksEndpoint(s),
+		DeleteShareLinkEndpoint: MakeDeleteShareLinkEndpoint(s),
+		GetSharedDeckEndpoint:   MakeGetSharedDeckEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		PostMemberEndpoint:      httptransport.NewClient("POST", tgt, encodePostMemberRequest, decodePostMemberResponse, options...).Endpoint(),
+		GetMembersEndpoint:      httptransport.NewClient("GET", tgt, encodeGetMembersRequest, decodeGetMembersResponse, options...).Endpoint(),
+		DeleteMemberEndpoint:    httptransport.NewClient("DELETE", tgt, encodeDeleteMemberRequest, decodeDeleteMemberResponse, options...).Endpoint(),
+		PostShareLinkEndpoint:   httptransport.NewClient("POST", tgt, encodePostShareLinkRequest, decodePostShareLinkResponse, options...).Endpoint(),
+		GetShareLinksEndpoint:   httptransport.NewClient("GET", tgt, encodeGetShareLinksRequest, decodeGetShareLinksResponse, options...).Endpoint(),
+		DeleteShareLinkEndpoint: httptransport.NewClient("DELETE", tgt, encodeDeleteShareLinkRequest, decodeDeleteShareLinkResponse, options...).Endpoint(),
+		GetSharedDeckEndpoint:   httptransport.NewClient("GET", tgt, encodeGetSharedDeckRequest, decodeGetSharedDeckResponse, options...).Endpoint(),
+	}, nil
+}
+
+// PostMember implements Service. Primarily useful in a client.
+func (e Endpoints) PostMember(ctx context.Context, deckID string, m clientModel.Member) (clientModel.Member, error) {
+	response, err := e.PostMemberEndpoint(ctx, clientRequest.PostMember{DeckID: deckID, Member: m})
+	if err != nil {
+		return clientModel.Member{}, err
+	}
+	resp, ok := response.(clientResponse.PostMember)
+	if !ok {
+		return clientModel.Member{}, ErrUnexpectedResponse
+	}
+	return resp.Member, resp.Err
+}
+
+// GetMembers implements Service. Primarily useful in a client.
+func (e Endpoints) GetMembers(ctx context.Context, deckID string) ([]clientModel.Member, error) {
+	response, err := e.GetMembersEndpoint(ctx, clientRequest.GetMembers{DeckID: deckID})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.GetMembers)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Members, resp.Err
+}
+
+// DeleteMember implements Service. Primarily useful in a client.
+func (e Endpoints) DeleteMember(ctx context.Context, deckID string, user string) error {
+	response, err := e.DeleteMemberEndpoint(ctx, clientRequest.DeleteMember{DeckID: deckID, User: user})
+	if err != nil {
+		return err
+	}
+	resp, ok := response.(clientResponse.DeleteMember)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
+	return resp.Err
+}
+
+// PostShareLink implements Service. Primarily useful in a client.
+func (e Endpoints) PostShareLink(ctx context.Context, deckID string) (clientModel.ShareLink, error) {
+	response, err := e.PostShareLinkEndpoint(ctx, clientRequest.PostShareLink{DeckID: deckID})
+	if err != nil {
+		return clientModel.ShareLink{}, err
+	}
+	resp, ok := response.(clientResponse.PostShareLink)
+	if !ok {
+		return clientModel.ShareLink{}, ErrUnexpectedResponse
+	}
+	return resp.Link, resp.Err
+}
+
+// GetShareLinks implements Service. Primarily useful in a client.
+func (e Endpoints) GetShareLinks(ctx context.Context, deckID string) ([]clientModel.ShareLink, error) {
+	response, err := e.GetShareLinksEndpoint(ctx, clientRequest.GetShareLinks{DeckID: deckID})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.GetShareLinks)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Links, resp.Err
+}
+
+// DeleteShareLink implements Service. Primarily useful in a client.
+func (e Endpoints) DeleteShareLink(ctx context.Context, deckID string, token string) error {
+	response, err := e.DeleteShareLinkEndpoint(ctx, clientRequest.DeleteShareLink{DeckID: deckID, Token: token})
+	if err != nil {
+		return err
+	}
+	resp, ok := response.(clientResponse.DeleteShareLink)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
+	return resp.Err
+}
+
+// GetSharedDeck implements Service. Primarily useful in a client.
+func (e Endpoints) GetSharedDeck(ctx context.Context, token string) (clientModel.Deck, error) {
+	response, err := e.GetSharedDeckEndpoint(ctx, clientRequest.GetSharedDeck{Token: token})
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	resp, ok := response.(clientResponse.GetSharedDeck)
+	if !ok {
+		return clientModel.Deck{}, ErrUnexpectedResponse
+	}
+	return resp.Deck, resp.Err
+}
+
+// MakePostMemberEndpoint returns an endpoint via the passed service.
+func MakePostMemberEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.PostMember)
+		r, e := s.PostMember(ctx, req.DeckID, req.Member)
+		return clientResponse.PostMember{Member: r, Err: e}, e
+	}
+}
+
+// MakeGetMembersEndpoint returns an endpoint via the passed service.
+func MakeGetMembersEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetMembers)
+		r, e := s.GetMembers(ctx, req.DeckID)
+		return clientResponse.GetMembers{Members: r, Err: e}, e
+	}
+}
+
+// MakeDeleteMemberEndpoint returns an endpoint via the passed service.
+func MakeDeleteMemberEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.DeleteMember)
+		e := s.DeleteMember(ctx, req.DeckID, req.User)
+		return clientResponse.DeleteMember{Err: e}, e
+	}
+}
+
+// MakePostShareLinkEndpoint returns an endpoint via the passed service.
+func MakePostShareLinkEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.PostShareLink)
+		r, e := s.PostShareLink(ctx, req.DeckID)
+		return clientResponse.PostShareLink{Link: r, Err: e}, e
+	}
+}
+
+// MakeGetShareLinksEndpoint returns an endpoint via the passed service.
+func MakeGetShareLinksEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetShareLinks)
+		r, e := s.GetShareLinks(ctx, req.DeckID)
+		return clientResponse.GetShareLinks{Links: r, Err: e}, e
+	}
+}
+
+// MakeDeleteShareLinkEndpoint returns an endpoint via the passed service.
+func MakeDeleteShareLinkEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.DeleteShareLink)
+		e := s.DeleteShareLink(ctx, req.DeckID, req.Token)
+		return clientResponse.DeleteShareLink{Err: e}, e
+	}
+}
+
+// MakeGetSharedDeckEndpoint returns an endpoint via the passed service.
+func MakeGetSharedDeckEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetSharedDeck)
+		r, e := s.GetSharedDeck(ctx, req.Token)
+		return clientResponse.GetSharedDeck{Deck: r, Err: e}, e
+	}
+}
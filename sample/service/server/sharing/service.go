@@ -0,0 +1,277 @@
+package sharing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+)
+
+// Service manages the members and the public share links of Decks.
+// Deck IDs may reference Decks of other users (see server.ParseDeckRef),
+// only their owner and admins manage them.
+type Service interface {
+	PostMember(ctx context.Context, deckID string, m clientModel.Member) (clientModel.Member, error)
+	GetMembers(ctx context.Context, deckID string) ([]clientModel.Member, error)
+	DeleteMember(ctx context.Context, deckID string, user string) error
+	PostShareLink(ctx context.Context, deckID string) (clientModel.ShareLink, error)
+	GetShareLinks(ctx context.Context, deckID string) ([]clientModel.ShareLink, error)
+	DeleteShareLink(ctx context.Context, deckID string, token string) error
+	GetSharedDeck(ctx context.Context, token string) (clientModel.Deck, error)
+}
+
+// Authorizer tells the role of users on the Decks of others.
+type Authorizer interface {
+	// Role returns the role of user on the Deck id of owner, an empty
+	// string if user is not a member.
+	Role(ctx context.Context, owner string, id string, user string) string
+	// SharedWith returns references to the Decks user is a member of.
+	SharedWith(ctx context.Context, user string) []string
+	// Members returns the users the Deck id of owner is shared with.
+	Members(ctx context.Context, owner string, id string) []string
+	// Forget drops the members and share links of a deleted Deck.
+	Forget(ctx context.Context, owner string, id string)
+}
+
+var (
+	// ErrInvalidRole : Role is not one of viewer, editor or admin
+	ErrInvalidRole = errors.New("invalid role")
+	// ErrInvalidMember : Member without user, or the owner of the Deck
+	ErrInvalidMember = errors.New("invalid member")
+)
+
+// rank orders roles by privilege.
+var rank = map[string]int{
+	clientModel.RoleViewer: 1,
+	clientModel.RoleEditor: 2,
+	clientModel.RoleAdmin:  3,
+}
+
+// Allows reports whether role grants at least the privileges of min.
+func Allows(role string, min string) bool {
+	return rank[role] > 0 && rank[role] >= rank[min]
+}
+
+type deckKey struct {
+	owner string
+	id    string
+}
+
+// ACL keeps Deck members and share links in memory. It implements both
+// Service and Authorizer.
+type ACL struct {
+	mtx     sync.RWMutex
+	repo    data.SampleRepository
+	members map[deckKey]map[string]string // user -> role
+	links   map[string]deckLink           // token -> link
+}
+
+type deckLink struct {
+	deckKey
+	createdAt time.Time
+}
+
+// NewACL In Memory Service Constructor. Decks are looked up in repo, which
+// must be safe for concurrent use as the service writes to it meanwhile.
+func NewACL(repo data.SampleRepository) *ACL {
+	return &ACL{
+		repo:    repo,
+		members: map[deckKey]map[string]string{},
+		links:   map[string]deckLink{},
+	}
+}
+
+// manage resolves a Deck the caller may manage. It must be called with the
+// lock held.
+func (s *ACL) manage(ctx context.Context, ref string) (deckKey, error) {
+	owner, id := server.ParseDeckRef(ctx, ref)
+	k := deckKey{owner: owner, id: id}
+	caller := auth.Subject(ctx)
+	if owner != caller {
+		role := s.members[k][caller]
+		if role == "" {
+			return deckKey{}, data.ErrNotFound
+		}
+		if !Allows(role, clientModel.RoleAdmin) {
+			return deckKey{}, server.ErrForbidden
+		}
+	}
+	if _, err := s.repo.GetDeck(ctx, owner, id); err != nil {
+		return deckKey{}, err
+	}
+	return k, nil
+}
+
+func (s *ACL) PostMember(ctx context.Context, deckID string, m clientModel.Member) (clientModel.Member, error) {
+	if rank[m.Role] == 0 {
+		return clientModel.Member{}, ErrInvalidRole
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	k, err := s.manage(ctx, deckID)
+	if err != nil {
+		return clientModel.Member{}, err
+	}
+	if m.User == "" || m.User == k.owner {
+		return clientModel.Member{}, ErrInvalidMember
+	}
+	if s.members[k] == nil {
+		s.members[k] = map[string]string{}
+	}
+	s.members[k][m.User] = m.Role // grant or change
+	return m, nil
+}
+
+func (s *ACL) GetMembers(ctx context.Context, deckID string) ([]clientModel.Member, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	k, err := s.manage(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	res := []clientModel.Member{}
+	for user, role := range s.members[k] {
+		res = append(res, clientModel.Member{User: user, Role: role})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].User < res[j].User })
+	return res, nil
+}
+
+func (s *ACL) DeleteMember(ctx context.Context, deckID string, user string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	k, err := s.manage(ctx, deckID)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.members[k][user]; !ok {
+		return data.ErrNotFound
+	}
+	delete(s.members[k], user)
+	if len(s.members[k]) == 0 {
+		delete(s.members, k)
+	}
+	return nil
+}
+
+func (s *ACL) PostShareLink(ctx context.Context, deckID string) (clientModel.ShareLink, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	k, err := s.manage(ctx, deckID)
+	if err != nil {
+		return clientModel.ShareLink{}, err
+	}
+	token, err := newToken()
+	if err != nil {
+		return clientModel.ShareLink{}, err
+	}
+	l := deckLink{deckKey: k, createdAt: time.Now().UTC()}
+	s.links[token] = l
+	return clientModel.ShareLink{Token: token, DeckID: deckID, CreatedAt: l.createdAt}, nil
+}
+
+func (s *ACL) GetShareLinks(ctx context.Context, deckID string) ([]clientModel.ShareLink, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	k, err := s.manage(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	res := []clientModel.ShareLink{}
+	for token, l := range s.links {
+		if l.deckKey == k {
+			res = append(res, clientModel.ShareLink{Token: token, DeckID: deckID, CreatedAt: l.createdAt})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].CreatedAt.Before(res[j].CreatedAt) })
+	return res, nil
+}
+
+func (s *ACL) DeleteShareLink(ctx context.Context, deckID string, token string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	k, err := s.manage(ctx, deckID)
+	if err != nil {
+		return err
+	}
+	if l, ok := s.links[token]; !ok || l.deckKey != k {
+		return data.ErrNotFound
+	}
+	delete(s.links, token)
+	return nil
+}
+
+// GetSharedDeck returns the Deck of a share link. It needs no principal.
+func (s *ACL) GetSharedDeck(ctx context.Context, token string) (clientModel.Deck, error) {
+	s.mtx.RLock()
+	l, ok := s.links[token]
+	s.mtx.RUnlock()
+	if !ok {
+		return clientModel.Deck{}, data.ErrNotFound
+	}
+	d, err := s.repo.GetDeck(ctx, l.owner, l.id)
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	return mapper.ToClientDeck(d), nil
+}
+
+func (s *ACL) Role(ctx context.Context, owner string, id string, user string) string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.members[deckKey{owner: owner, id: id}][user]
+}
+
+func (s *ACL) Members(ctx context.Context, owner string, id string) []string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	var users []string
+	for user := range s.members[deckKey{owner: owner, id: id}] {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	return users
+}
+
+func (s *ACL) SharedWith(ctx context.Context, user string) []string {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	var refs []string
+	for k, members := range s.members {
+		if _, ok := members[user]; ok {
+			refs = append(refs, k.owner+server.DeckRefSeparator+k.id)
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+func (s *ACL) Forget(ctx context.Context, owner string, id string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	k := deckKey{owner: owner, id: id}
+	delete(s.members, k)
+	for token, l := range s.links {
+		if l.deckKey == k {
+			delete(s.links, token)
+		}
+	}
+}
+
+// newToken returns 256 random bits, URL safe.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,326 @@
+package sharing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the sharing endpoints into an http.Handler.
+// GET /shared/{token} is meant to be served without authentication.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /decks/:id/members               grants a role on the Deck to a user
+	// GET     /decks/:id/members               retrieves the members of the Deck
+	// DELETE  /decks/:id/members/:user         revokes the role of a user
+	// POST    /decks/:id/links                 creates a public read-only share link
+	// GET     /decks/:id/links                 retrieves the share links of the Deck
+	// DELETE  /decks/:id/links/:token          revokes a share link
+	// GET     /shared/:token                   retrieves the Deck of a share link
+
+	r.Methods("POST").Path("/decks/{id}/members").Handler(httptransport.NewServer(
+		e.PostMemberEndpoint,
+		decodePostMemberRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/decks/{id}/members").Handler(httptransport.NewServer(
+		e.GetMembersEndpoint,
+		decodeGetMembersRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("DELETE").Path("/decks/{id}/members/{user}").Handler(httptransport.NewServer(
+		e.DeleteMemberEndpoint,
+		decodeDeleteMemberRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/decks/{id}/links").Handler(httptransport.NewServer(
+		e.PostShareLinkEndpoint,
+		decodePostShareLinkRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/decks/{id}/links").Handler(httptransport.NewServer(
+		e.GetShareLinksEndpoint,
+		decodeGetShareLinksRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("DELETE").Path("/decks/{id}/links/{token}").Handler(httptransport.NewServer(
+		e.DeleteShareLinkEndpoint,
+		decodeDeleteShareLinkRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/shared/{token}").Handler(httptransport.NewServer(
+		e.GetSharedDeckEndpoint,
+		decodeGetSharedDeckRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodePostMemberRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	var member clientModel.Member
+	if e := json.NewDecoder(r.Body).Decode(&member); e != nil {
+		return nil, e
+	}
+	return clientRequest.PostMember{DeckID: id, Member: member}, nil
+}
+
+func decodeGetMembersRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetMembers{DeckID: id}, nil
+}
+
+func decodeDeleteMemberRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	user, ok := vars["user"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.DeleteMember{DeckID: id, User: user}, nil
+}
+
+func decodePostShareLinkRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.PostShareLink{DeckID: id}, nil
+}
+
+func decodeGetShareLinksRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetShareLinks{DeckID: id}, nil
+}
+
+func decodeDeleteShareLinkRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	token, ok := vars["token"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.DeleteShareLink{DeckID: id, Token: token}, nil
+}
+
+func decodeGetSharedDeckRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	token, ok := vars["token"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetSharedDeck{Token: token}, nil
+}
+
+func encodePostMemberRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}/members")
+	r := request.(clientRequest.PostMember)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/members"
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(r.Member); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func encodeGetMembersRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/members")
+	r := request.(clientRequest.GetMembers)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/members"
+	return nil
+}
+
+func encodeDeleteMemberRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("DELETE").Path("/decks/{id}/members/{user}")
+	r := request.(clientRequest.DeleteMember)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/members/" + url.QueryEscape(r.User)
+	return nil
+}
+
+func encodePostShareLinkRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}/links")
+	r := request.(clientRequest.PostShareLink)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/links"
+	return nil
+}
+
+func encodeGetShareLinksRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/links")
+	r := request.(clientRequest.GetShareLinks)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/links"
+	return nil
+}
+
+func encodeDeleteShareLinkRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("DELETE").Path("/decks/{id}/links/{token}")
+	r := request.(clientRequest.DeleteShareLink)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/links/" + url.QueryEscape(r.Token)
+	return nil
+}
+
+func encodeGetSharedDeckRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/shared/{token}")
+	r := request.(clientRequest.GetSharedDeck)
+	req.URL.Path = "/shared/" + url.QueryEscape(r.Token)
+	return nil
+}
+
+func decodePostMemberResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.PostMember
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetMembersResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetMembers
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeDeleteMemberResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.DeleteMember
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodePostShareLinkResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.PostShareLink
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetShareLinksResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetShareLinks
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeDeleteShareLinkResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.DeleteShareLink
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetSharedDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetSharedDeck
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrInvalidRole, ErrInvalidMember} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrInvalidRole, ErrInvalidMember:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/reviews"
+)
+
+// cacheKey identifies computed Stats: those of a Deck, by owner and ID, or
+// of all the Decks of the reviewer, over a number of days from a given day.
+type cacheKey struct {
+	reviewer string
+	owner    string
+	deckID   string
+	all      bool
+	days     int
+	today    string
+}
+
+type cacheEntry struct {
+	stats   clientModel.Stats
+	expires time.Time
+}
+
+// Cache keeps computed Stats until the Reviews they were computed from
+// change, or ttl passes for the changes of the Decks themselves.
+type Cache struct {
+	mtx     sync.Mutex
+	ttl     time.Duration
+	entries map[cacheKey]cacheEntry
+}
+
+// NewCache Cache Constructor. A zero ttl disables caching.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: map[cacheKey]cacheEntry{},
+	}
+}
+
+func (c *Cache) get(k cacheKey) (clientModel.Stats, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	e, ok := c.entries[k]
+	if !ok || time.Now().After(e.expires) {
+		return clientModel.Stats{}, false
+	}
+	return e.stats, true
+}
+
+func (c *Cache) put(k cacheKey, stats clientModel.Stats) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	now := time.Now()
+	for old, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, old)
+		}
+	}
+	c.entries[k] = cacheEntry{stats: stats, expires: now.Add(c.ttl)}
+}
+
+// Invalidate drops the Stats of the Deck deckID of owner reviewed by
+// reviewer, along with those of all of their Decks.
+func (c *Cache) Invalidate(reviewer string, owner string, deckID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for k := range c.entries {
+		if k.reviewer == reviewer && (k.all || k.owner == owner && k.deckID == deckID) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// ReviewsMiddleware : Invalidate the Stats of the Decks reviewed through
+// input reviews Service.
+func ReviewsMiddleware(cache *Cache) func(reviews.Service) reviews.Service {
+	return func(next reviews.Service) reviews.Service {
+		return &reviewsMiddleware{
+			next:  next,
+			cache: cache,
+		}
+	}
+}
+
+type reviewsMiddleware struct {
+	next  reviews.Service
+	cache *Cache
+}
+
+func (mw reviewsMiddleware) PostReview(ctx context.Context, r clientModel.Review) (clientModel.Review, error) {
+	r, err := mw.next.PostReview(ctx, r)
+	if err == nil {
+		owner, deckID := server.ParseDeckRef(ctx, r.DeckID)
+		mw.cache.Invalidate(auth.Subject(ctx), owner, deckID)
+	}
+	return r, err
+}
+
+func (mw reviewsMiddleware) GetReviews(ctx context.Context, deckID string) ([]clientModel.Review, error) {
+	return mw.next.GetReviews(ctx, deckID)
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/reviews"
+)
+
+// newStats returns a Service caching Stats for ttl, over the Deck d of
+// alice, along with the Reviews it reads, the Decks and the context of
+// alice.
+func newStats(t *testing.T, ttl time.Duration) (Service, reviews.Service, server.SampleService, context.Context) {
+	t.Helper()
+	ctx := auth.NewContext(context.Background(), auth.Principal{Subject: "alice"})
+	decks := server.NewService(inmem.NewInmemRepository())
+	if err := decks.PostDeck(ctx, model.Deck{ID: "d", Cards: []model.Card{{ID: "a"}, {ID: "b"}}}); err != nil {
+		t.Fatal(err)
+	}
+	cache := NewCache(ttl)
+	rs := ReviewsMiddleware(cache)(reviews.NewInmemService(decks))
+	return NewService(decks, rs, cache), rs, decks, ctx
+}
+
+func TestCacheReferences(t *testing.T) {
+	s, rs, decks, ctx := newStats(t, time.Hour)
+	if _, err := rs.PostReview(ctx, clientModel.Review{DeckID: "alice~d", CardID: "a", Grade: clientModel.GradeGood}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both references count the same Reviews, and share their Stats.
+	st, err := s.GetDeckStats(ctx, "d", 0)
+	if err != nil || st.Reviews != 1 || st.Maturity.New != 1 {
+		t.Fatalf("stats of d %+v (%v)", st, err)
+	}
+	if again, _ := s.GetDeckStats(ctx, "alice~d", 0); !again.Computed.Equal(st.Computed) || again.DeckID != "d" {
+		t.Fatalf("stats of alice~d %+v, not cached", again)
+	}
+
+	// Changes of the Cards wait for the ttl, Reviews through either
+	// reference drop the Stats.
+	if err := decks.PostCard(ctx, "d", model.Card{ID: "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.GetDeckStats(ctx, "d", 0); again.Cards != 2 {
+		t.Fatalf("stats of d %+v, not cached", again)
+	}
+	all, _ := s.GetStats(ctx, 0)
+	if _, err := rs.PostReview(ctx, clientModel.Review{DeckID: "d", CardID: "b", Grade: clientModel.GradeGood}); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := s.GetDeckStats(ctx, "alice~d", 0); st.Cards != 3 || st.Reviews != 2 {
+		t.Fatalf("stats of alice~d %+v after a review", st)
+	}
+	if again, _ := s.GetStats(ctx, 0); again.Computed.Equal(all.Computed) || again.Reviews != 2 {
+		t.Fatalf("stats of all decks %+v after a review", again)
+	}
+	if got, _ := rs.GetReviews(ctx, "alice~d"); len(got) != 2 || got[0].DeckID != "d" {
+		t.Fatalf("reviews %+v", got)
+	}
+}
+
+func TestCacheScope(t *testing.T) {
+	c := NewCache(time.Hour)
+	for _, k := range []cacheKey{
+		{reviewer: "alice", owner: "alice", deckID: "d"},
+		{reviewer: "alice", owner: "bob", deckID: "d"},
+		{reviewer: "alice", all: true},
+		{reviewer: "bob", owner: "alice", deckID: "d"},
+	} {
+		c.put(k, clientModel.Stats{})
+	}
+	c.Invalidate("alice", "alice", "d")
+	for k, want := range map[cacheKey]bool{
+		{reviewer: "alice", owner: "alice", deckID: "d"}: false,
+		{reviewer: "alice", owner: "bob", deckID: "d"}:   true,
+		{reviewer: "alice", all: true}:                   false,
+		{reviewer: "bob", owner: "alice", deckID: "d"}:   true,
+	} {
+		if _, ok := c.get(k); ok != want {
+			t.Errorf("%+v cached %v, want %v", k, ok, want)
+		}
+	}
+
+	// A zero ttl caches nothing.
+	s, _, _, ctx := newStats(t, 0)
+	st, _ := s.GetStats(ctx, 0)
+	time.Sleep(time.Millisecond)
+	if again, _ := s.GetStats(ctx, 0); again.Computed.Equal(st.Computed) {
+		t.Fatalf("cached with a zero ttl")
+	}
+}
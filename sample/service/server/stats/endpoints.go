@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the learning statistics API
+type Endpoints struct {
+	GetDeckStatsEndpoint endpoint.Endpoint
+	GetStatsEndpoint     endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		GetDeckStatsEndpoint: MakeGetDeckStatsEndpoint(s),
+		GetStatsEndpoint:     MakeGetStatsEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		GetDeckStatsEndpoint: httptransport.NewClient("GET", tgt, encodeGetDeckStatsRequest, decodeGetDeckStatsResponse, options...).Endpoint(),
+		GetStatsEndpoint:     httptransport.NewClient("GET", tgt, encodeGetStatsRequest, decodeGetStatsResponse, options...).Endpoint(),
+	}, nil
+}
+
+// GetDeckStats implements Service. Primarily useful in a client.
+func (e Endpoints) GetDeckStats(ctx context.Context, deckID string, days int) (clientModel.Stats, error) {
+	response, err := e.GetDeckStatsEndpoint(ctx, clientRequest.GetDeckStats{DeckID: deckID, Days: days})
+	if err != nil {
+		return clientModel.Stats{}, err
+	}
+	resp, ok := response.(clientResponse.GetDeckStats)
+	if !ok {
+		return clientModel.Stats{}, ErrUnexpectedResponse
+	}
+	return resp.Stats, resp.Err
+}
+
+// GetStats implements Service. Primarily useful in a client.
+func (e Endpoints) GetStats(ctx context.Context, days int) (clientModel.Stats, error) {
+	response, err := e.GetStatsEndpoint(ctx, clientRequest.GetStats{Days: days})
+	if err != nil {
+		return clientModel.Stats{}, err
+	}
+	resp, ok := response.(clientResponse.GetStats)
+	if !ok {
+		return clientModel.Stats{}, ErrUnexpectedResponse
+	}
+	return resp.Stats, resp.Err
+}
+
+// MakeGetDeckStatsEndpoint returns an endpoint via the passed service.
+func MakeGetDeckStatsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetDeckStats)
+		st, e := s.GetDeckStats(ctx, req.DeckID, req.Days)
+		return clientResponse.GetDeckStats{Stats: st, Err: e}, e
+	}
+}
+
+// MakeGetStatsEndpoint returns an endpoint via the passed service.
+func MakeGetStatsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetStats)
+		st, e := s.GetStats(ctx, req.Days)
+		return clientResponse.GetStats{Stats: st, Err: e}, e
+	}
+}
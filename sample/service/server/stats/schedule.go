@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+const (
+	day = 24 * time.Hour
+	// InitialEase is the ease of Cards never reviewed: how many times
+	// longer their interval gets when recalled.
+	InitialEase = 2.5
+	// MinEase bounds the ease of Cards often forgotten.
+	MinEase = 1.3
+	// RelearnInterval is the interval of forgotten Cards.
+	RelearnInterval = 10 * time.Minute
+	// MatureInterval is the interval from which Cards are mature.
+	MatureInterval = 21 * day
+)
+
+// cardKey identifies a Card across Decks.
+type cardKey struct {
+	deckID string
+	cardID string
+}
+
+// card is the schedule of a Card, as its Reviews left it. Schedules follow
+// SM-2: forgotten Cards are asked again in RelearnInterval, learnt ones the
+// next day (4 days when easy), then in intervals growing by their ease,
+// which again, hard and easy grades lower or raise.
+type card struct {
+	first    time.Time
+	last     time.Time
+	reviews  int
+	grades   int // sum of the grades
+	ease     float64
+	interval time.Duration
+}
+
+// due returns when c is due again.
+func (c *card) due() time.Time {
+	return c.last.Add(c.interval)
+}
+
+func (c *card) review(r clientModel.Review) {
+	if c.reviews == 0 {
+		c.first = r.Time
+		c.ease = InitialEase
+	}
+	c.reviews++
+	c.grades += r.Grade
+	c.last = r.Time
+	learning := c.interval < day
+	switch {
+	case r.Grade == clientModel.GradeAgain:
+		c.interval = RelearnInterval
+		c.ease -= 0.2
+	case learning && r.Grade == clientModel.GradeEasy:
+		c.interval = 4 * day
+	case learning:
+		c.interval = day
+	case r.Grade == clientModel.GradeHard:
+		c.interval = time.Duration(float64(c.interval) * 1.2)
+		c.ease -= 0.15
+	case r.Grade == clientModel.GradeGood:
+		c.interval = time.Duration(float64(c.interval) * c.ease)
+	default:
+		c.interval = time.Duration(float64(c.interval) * c.ease * 1.3)
+		c.ease += 0.15
+	}
+	if c.ease < MinEase {
+		c.ease = MinEase
+	}
+}
+
+// review is a Review of a Card of a Deck.
+type review struct {
+	deckID string
+	clientModel.Review
+}
+
+// replay schedules Cards from their Reviews, taken in time order, calling
+// visit before each of them is applied.
+func replay(reviews []review, visit func(r review, c *card)) map[cardKey]*card {
+	sorted := append([]review(nil), reviews...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	cards := map[cardKey]*card{}
+	for _, r := range sorted {
+		k := cardKey{deckID: r.deckID, cardID: r.CardID}
+		c, ok := cards[k]
+		if !ok {
+			c = &card{}
+			cards[k] = c
+		}
+		if visit != nil {
+			visit(r, c)
+		}
+		c.review(r.Review)
+	}
+	return cards
+}
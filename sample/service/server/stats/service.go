@@ -0,0 +1,247 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/reviews"
+)
+
+// Service computes learning statistics from the Reviews of the caller,
+// over the days before and after today.
+type Service interface {
+	// GetDeckStats sums up the study of a Deck.
+	GetDeckStats(ctx context.Context, deckID string, days int) (clientModel.Stats, error)
+	// GetStats sums up the study of all the Decks of the caller.
+	GetStats(ctx context.Context, days int) (clientModel.Stats, error)
+}
+
+const (
+	// DefaultDays is the number of days of activity and forecast when not
+	// given.
+	DefaultDays = 30
+	// MaxDays bounds the number of days of activity and forecast.
+	MaxDays = 365
+)
+
+var (
+	// ErrInvalidDays : number of days out of 1..MaxDays
+	ErrInvalidDays = errors.New("invalid days, use 1 to 365")
+)
+
+// dateLayout formats the days of Stats.
+const dateLayout = "2006-01-02"
+
+// retentionAges bounds the card ages of retention buckets, in days.
+var retentionAges = [][2]int{{0, 7}, {7, 30}, {30, 90}, {90, 0}}
+
+type statsService struct {
+	decks   server.SampleService
+	reviews reviews.Service
+	cache   *Cache
+}
+
+// NewService Service Constructor. Cards are read from decks and their
+// Reviews from reviews, Stats being kept in cache until these change.
+func NewService(decks server.SampleService, reviews reviews.Service, cache *Cache) Service {
+	return &statsService{
+		decks:   decks,
+		reviews: reviews,
+		cache:   cache,
+	}
+}
+
+func (s *statsService) GetDeckStats(ctx context.Context, deckID string, days int) (clientModel.Stats, error) {
+	if days == 0 {
+		days = DefaultDays
+	}
+	if days < 1 || days > MaxDays {
+		return clientModel.Stats{}, ErrInvalidDays
+	}
+	// The Deck is read first to check the caller can still see it.
+	d, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return clientModel.Stats{}, err
+	}
+	now := time.Now().UTC()
+	owner, id := server.ParseDeckRef(ctx, deckID)
+	k := cacheKey{reviewer: auth.Subject(ctx), owner: owner, deckID: id, days: days, today: now.Format(dateLayout)}
+	if st, ok := s.cache.get(k); ok {
+		return st, nil
+	}
+	var rs []review
+	if rs, err = s.reviewsOf(ctx, d.ID, rs); err != nil {
+		return clientModel.Stats{}, err
+	}
+	st := compute(now, days, cardsOf(d.ID, d.Cards, nil), rs)
+	st.DeckID = d.ID
+	s.cache.put(k, st)
+	return st, nil
+}
+
+func (s *statsService) GetStats(ctx context.Context, days int) (clientModel.Stats, error) {
+	if days == 0 {
+		days = DefaultDays
+	}
+	if days < 1 || days > MaxDays {
+		return clientModel.Stats{}, ErrInvalidDays
+	}
+	now := time.Now().UTC()
+	k := cacheKey{reviewer: auth.Subject(ctx), all: true, days: days, today: now.Format(dateLayout)}
+	if st, ok := s.cache.get(k); ok {
+		return st, nil
+	}
+	decks, err := s.decks.GetDecks(ctx)
+	if err != nil {
+		return clientModel.Stats{}, err
+	}
+	var keys []cardKey
+	var rs []review
+	for _, d := range decks {
+		keys = cardsOf(d.ID, d.Cards, keys)
+		if rs, err = s.reviewsOf(ctx, d.ID, rs); err != nil {
+			return clientModel.Stats{}, err
+		}
+	}
+	st := compute(now, days, keys, rs)
+	s.cache.put(k, st)
+	return st, nil
+}
+
+// reviewsOf appends the Reviews of a Deck to rs.
+func (s *statsService) reviewsOf(ctx context.Context, deckID string, rs []review) ([]review, error) {
+	deckReviews, err := s.reviews.GetReviews(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range deckReviews {
+		rs = append(rs, review{deckID: deckID, Review: r})
+	}
+	return rs, nil
+}
+
+// cardsOf appends the keys of the Cards of a Deck to keys.
+func cardsOf(deckID string, cards []clientModel.Card, keys []cardKey) []cardKey {
+	for _, c := range cards {
+		keys = append(keys, cardKey{deckID: deckID, cardID: c.ID})
+	}
+	return keys
+}
+
+// compute returns the Stats of the Cards of keys from their Reviews, over
+// the days days up to today and from today. Reviews of Cards since deleted
+// only count in the activity and retention.
+func compute(now time.Time, days int, keys []cardKey, rs []review) clientModel.Stats {
+	today := now.Truncate(day)
+	st := clientModel.Stats{
+		Cards:     len(keys),
+		Reviews:   len(rs),
+		Retention: make([]clientModel.RetentionBucket, len(retentionAges)),
+		Forecast:  make([]clientModel.DueDay, days),
+		Days:      make([]clientModel.StudyDay, days),
+		Computed:  now,
+	}
+	for i, ages := range retentionAges {
+		st.Retention[i] = clientModel.RetentionBucket{MinAgeDays: ages[0], MaxAgeDays: ages[1]}
+	}
+	first := today.Add(-time.Duration(days-1) * day)
+	for i := range st.Days {
+		st.Days[i].Date = first.Add(time.Duration(i) * day).Format(dateLayout)
+		st.Forecast[i].Date = today.Add(time.Duration(i) * day).Format(dateLayout)
+	}
+
+	studied := map[time.Time]bool{}
+	cards := replay(rs, func(r review, c *card) {
+		reviewed := r.Time.Truncate(day)
+		studied[reviewed] = true
+		if i := int(reviewed.Sub(first) / day); !reviewed.Before(first) && i < days {
+			st.Days[i].Reviews++
+			st.Days[i].DurationMs += r.DurationMs
+		}
+		if c.reviews == 0 {
+			return
+		}
+		age := int(r.Time.Sub(c.first) / day)
+		for i, ages := range retentionAges {
+			if age >= ages[0] && (ages[1] == 0 || age < ages[1]) {
+				st.Retention[i].Reviews++
+				if r.Grade > clientModel.GradeAgain {
+					st.Retention[i].Recalled++
+				}
+			}
+		}
+	})
+	for i, b := range st.Retention {
+		if b.Reviews > 0 {
+			st.Retention[i].Retention = float64(b.Recalled) / float64(b.Reviews)
+		}
+	}
+
+	reviewed := 0
+	for _, k := range keys {
+		c, ok := cards[k]
+		switch {
+		case !ok:
+			st.Maturity.New++
+			continue
+		case c.interval < day:
+			st.Maturity.Learning++
+		case c.interval < MatureInterval:
+			st.Maturity.Young++
+		default:
+			st.Maturity.Mature++
+		}
+		reviewed++
+		st.AverageEase += c.ease
+		// Difficulty goes from 0, always easy, to 1, always forgotten.
+		grade := float64(c.grades) / float64(c.reviews)
+		st.AverageDifficulty += (clientModel.GradeEasy - grade) / (clientModel.GradeEasy - clientModel.GradeAgain)
+		if i := int(c.due().Truncate(day).Sub(today) / day); i < days {
+			if i < 0 {
+				i = 0
+			}
+			st.Forecast[i].Due++
+		}
+	}
+	if reviewed > 0 {
+		st.AverageEase /= float64(reviewed)
+		st.AverageDifficulty /= float64(reviewed)
+	}
+	st.Streak = streak(studied, today)
+	return st
+}
+
+// streak returns the current and longest runs of days studied.
+func streak(studied map[time.Time]bool, today time.Time) clientModel.Streak {
+	var res clientModel.Streak
+	d := today
+	if !studied[d] {
+		d = d.Add(-day)
+	}
+	for studied[d] {
+		res.Current++
+		d = d.Add(-day)
+	}
+	var sorted []time.Time
+	for d := range studied {
+		sorted = append(sorted, d)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	run := 0
+	for i, d := range sorted {
+		if i > 0 && d.Sub(sorted[i-1]) == day {
+			run++
+		} else {
+			run = 1
+		}
+		if run > res.Longest {
+			res.Longest = run
+		}
+	}
+	return res
+}
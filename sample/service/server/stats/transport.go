@@ -0,0 +1,171 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the learning statistics endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// GET     /decks/:id/stats?days=           retrieves the learning statistics of a Deck
+	// GET     /stats?days=                     retrieves those of all the Decks of the caller
+
+	r.Methods("GET").Path("/decks/{id}/stats").Handler(httptransport.NewServer(
+		e.GetDeckStatsEndpoint,
+		decodeGetDeckStatsRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/stats").Handler(httptransport.NewServer(
+		e.GetStatsEndpoint,
+		decodeGetStatsRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeGetDeckStatsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	days, err := decodeDays(r)
+	if err != nil {
+		return nil, err
+	}
+	return clientRequest.GetDeckStats{DeckID: id, Days: days}, nil
+}
+
+func decodeGetStatsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	days, err := decodeDays(r)
+	if err != nil {
+		return nil, err
+	}
+	return clientRequest.GetStats{Days: days}, nil
+}
+
+// decodeDays reads the optional days parameter of r.
+func decodeDays(r *http.Request) (int, error) {
+	days := r.URL.Query().Get("days")
+	if days == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(days)
+	if err != nil || n == 0 {
+		return 0, ErrInvalidDays
+	}
+	return n, nil
+}
+
+func encodeGetDeckStatsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/stats")
+	r := request.(clientRequest.GetDeckStats)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/stats"
+	req.URL.RawQuery = daysQuery(r.Days)
+	return nil
+}
+
+func encodeGetStatsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/stats")
+	r := request.(clientRequest.GetStats)
+	req.URL.Path = "/stats"
+	req.URL.RawQuery = daysQuery(r.Days)
+	return nil
+}
+
+func daysQuery(days int) string {
+	if days == 0 {
+		return ""
+	}
+	return url.Values{"days": {strconv.Itoa(days)}}.Encode()
+}
+
+func decodeGetDeckStatsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetDeckStats
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetStatsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetStats
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrInvalidDays} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrInvalidDays:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
@@ -0,0 +1,121 @@
+package tagging
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the tagging API
+type Endpoints struct {
+	TagDeckEndpoint  endpoint.Endpoint
+	TagCardsEndpoint endpoint.Endpoint
+	GetTagsEndpoint  endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		TagDeckEndpoint:  MakeTagDeckEndpoint(s),
+		TagCardsEndpoint: MakeTagCardsEndpoint(s),
+		GetTagsEndpoint:  MakeGetTagsEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		TagDeckEndpoint:  httptransport.NewClient("POST", tgt, encodeTagDeckRequest, decodeTagDeckResponse, options...).Endpoint(),
+		TagCardsEndpoint: httptransport.NewClient("POST", tgt, encodeTagCardsRequest, decodeTagCardsResponse, options...).Endpoint(),
+		GetTagsEndpoint:  httptransport.NewClient("GET", tgt, encodeGetTagsRequest, decodeGetTagsResponse, options...).Endpoint(),
+	}, nil
+}
+
+// TagDeck implements Service. Primarily useful in a client.
+func (e Endpoints) TagDeck(ctx context.Context, deckID string, change clientModel.TagChange) (clientModel.Deck, error) {
+	response, err := e.TagDeckEndpoint(ctx, clientRequest.TagDeck{DeckID: deckID, Change: change})
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	resp, ok := response.(clientResponse.TagDeck)
+	if !ok {
+		return clientModel.Deck{}, ErrUnexpectedResponse
+	}
+	return resp.Deck, resp.Err
+}
+
+// TagCards implements Service. Primarily useful in a client.
+func (e Endpoints) TagCards(ctx context.Context, deckID string, change clientModel.TagChange) ([]clientModel.Card, error) {
+	response, err := e.TagCardsEndpoint(ctx, clientRequest.TagCards{DeckID: deckID, Change: change})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.TagCards)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Cards, resp.Err
+}
+
+// GetTags implements Service. Primarily useful in a client.
+func (e Endpoints) GetTags(ctx context.Context, prefix string, limit int) ([]clientModel.TagCount, error) {
+	response, err := e.GetTagsEndpoint(ctx, clientRequest.GetTags{Prefix: prefix, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.GetTags)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Tags, resp.Err
+}
+
+// MakeTagDeckEndpoint returns an endpoint via the passed service.
+func MakeTagDeckEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.TagDeck)
+		d, e := s.TagDeck(ctx, req.DeckID, req.Change)
+		return clientResponse.TagDeck{Deck: d, Err: e}, e
+	}
+}
+
+// MakeTagCardsEndpoint returns an endpoint via the passed service.
+func MakeTagCardsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.TagCards)
+		c, e := s.TagCards(ctx, req.DeckID, req.Change)
+		return clientResponse.TagCards{Cards: c, Err: e}, e
+	}
+}
+
+// MakeGetTagsEndpoint returns an endpoint via the passed service.
+func MakeGetTagsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetTags)
+		t, e := s.GetTags(ctx, req.Prefix, req.Limit)
+		return clientResponse.GetTags{Tags: t, Err: e}, e
+	}
+}
@@ -0,0 +1,159 @@
+package tagging
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+// Service edits the tags of Decks and Cards in bulk, and completes tags.
+type Service interface {
+	// TagDeck changes the tags of a Deck, the Cards being left as is.
+	TagDeck(ctx context.Context, deckID string, change clientModel.TagChange) (clientModel.Deck, error)
+	// TagCards changes the tags of the Cards of a Deck selected by change,
+	// and returns them.
+	TagCards(ctx context.Context, deckID string, change clientModel.TagChange) ([]clientModel.Card, error)
+	// GetTags returns the tags of the caller's Decks and Cards starting with
+	// prefix, the most used first. A zero limit applies DefaultLimit.
+	GetTags(ctx context.Context, prefix string, limit int) ([]clientModel.TagCount, error)
+}
+
+// Bounds of the number of completed tags.
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+var (
+	// ErrEmptyChange : tag change neither adding nor removing tags
+	ErrEmptyChange = errors.New("no tags to add or remove")
+	// ErrInvalidLimit : limit out of the 0..MaxLimit range
+	ErrInvalidLimit = errors.New("invalid limit")
+)
+
+type deckService struct {
+	decks server.SampleService
+}
+
+// NewService Service Constructor. Decks are read and written through
+// decks, as a whole: changes made to a Deck by other calls during a bulk
+// edit may be lost.
+func NewService(decks server.SampleService) Service {
+	return &deckService{
+		decks: decks,
+	}
+}
+
+func (s *deckService) TagDeck(ctx context.Context, deckID string, change clientModel.TagChange) (clientModel.Deck, error) {
+	if len(change.Add) == 0 && len(change.Remove) == 0 {
+		return clientModel.Deck{}, ErrEmptyChange
+	}
+	d, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	d.Tags = tags.Apply(d.Tags, change.Add, change.Remove)
+	if err := s.decks.PutDeck(ctx, deckID, mapper.FromClientDeck(d)); err != nil {
+		return clientModel.Deck{}, err
+	}
+	return d, nil
+}
+
+func (s *deckService) TagCards(ctx context.Context, deckID string, change clientModel.TagChange) ([]clientModel.Card, error) {
+	if len(change.Add) == 0 && len(change.Remove) == 0 {
+		return nil, ErrEmptyChange
+	}
+	expr, err := tags.Parse(change.Filter)
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(change.CardIDs))
+	for _, id := range change.CardIDs {
+		ids[id] = false
+	}
+	changed := []clientModel.Card{}
+	for i, c := range d.Cards {
+		if _, ok := ids[c.ID]; ok {
+			ids[c.ID] = true
+		} else if len(ids) > 0 {
+			continue
+		}
+		if !expr.Match(tags.Of(c, d.Tags)) {
+			continue
+		}
+		c.Tags = tags.Apply(c.Tags, change.Add, change.Remove)
+		d.Cards[i] = c
+		changed = append(changed, c)
+	}
+	for _, found := range ids {
+		if !found {
+			return nil, data.ErrNotFound
+		}
+	}
+	if len(changed) == 0 {
+		return changed, nil
+	}
+	if err := s.decks.PutDeck(ctx, deckID, mapper.FromClientDeck(d)); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+func (s *deckService) GetTags(ctx context.Context, prefix string, limit int) ([]clientModel.TagCount, error) {
+	if limit < 0 || limit > MaxLimit {
+		return nil, ErrInvalidLimit
+	}
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	decks, err := s.decks.GetDecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Tags are counted by their lower case form, under their first spelling.
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	counts := make(map[string]*clientModel.TagCount)
+	count := func(tags []string) {
+		for _, t := range tags {
+			key := strings.ToLower(t)
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if counts[key] == nil {
+				counts[key] = &clientModel.TagCount{Tag: t}
+			}
+			counts[key].Count++
+		}
+	}
+	for _, d := range decks {
+		count(d.Tags)
+		for _, c := range d.Cards {
+			count(c.Tags)
+		}
+	}
+	res := make([]clientModel.TagCount, 0, len(counts))
+	for _, c := range counts {
+		res = append(res, *c)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return strings.ToLower(res[i].Tag) < strings.ToLower(res[j].Tag)
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
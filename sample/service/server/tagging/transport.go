@@ -0,0 +1,208 @@
+package tagging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the tagging endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /decks/:id/tags                 adds and removes tags of a Deck
+	// POST    /decks/:id/cards/tags           adds and removes tags of Cards of a Deck
+	// GET     /tags?prefix=:p&limit=:l        completes tags, with their use counts
+
+	r.Methods("POST").Path("/decks/{id}/tags").Handler(httptransport.NewServer(
+		e.TagDeckEndpoint,
+		decodeTagDeckRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/decks/{id}/cards/tags").Handler(httptransport.NewServer(
+		e.TagCardsEndpoint,
+		decodeTagCardsRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/tags").Handler(httptransport.NewServer(
+		e.GetTagsEndpoint,
+		decodeGetTagsRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeTagDeckRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	var change clientModel.TagChange
+	if e := json.NewDecoder(r.Body).Decode(&change); e != nil {
+		return nil, e
+	}
+	return clientRequest.TagDeck{DeckID: id, Change: change}, nil
+}
+
+func decodeTagCardsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	var change clientModel.TagChange
+	if e := json.NewDecoder(r.Body).Decode(&change); e != nil {
+		return nil, e
+	}
+	return clientRequest.TagCards{DeckID: id, Change: change}, nil
+}
+
+func decodeGetTagsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	q := r.URL.Query()
+	req := clientRequest.GetTags{Prefix: q.Get("prefix")}
+	if l := q.Get("limit"); l != "" {
+		if req.Limit, err = strconv.Atoi(l); err != nil {
+			return nil, ErrInvalidLimit
+		}
+	}
+	return req, nil
+}
+
+func encodeTagDeckRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}/tags")
+	r := request.(clientRequest.TagDeck)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/tags"
+	return encodeBody(req, r.Change)
+}
+
+func encodeTagCardsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}/cards/tags")
+	r := request.(clientRequest.TagCards)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/cards/tags"
+	return encodeBody(req, r.Change)
+}
+
+func encodeGetTagsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/tags")
+	r := request.(clientRequest.GetTags)
+	req.URL.Path = "/tags"
+	q := req.URL.Query()
+	if r.Prefix != "" {
+		q.Set("prefix", r.Prefix)
+	}
+	if r.Limit != 0 {
+		q.Set("limit", strconv.Itoa(r.Limit))
+	}
+	req.URL.RawQuery = q.Encode()
+	return nil
+}
+
+func encodeBody(req *http.Request, body interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func decodeTagDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.TagDeck
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeTagCardsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.TagCards
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetTagsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetTags
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrEmptyChange, ErrInvalidLimit} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrEmptyChange, ErrInvalidLimit:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
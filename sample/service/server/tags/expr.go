@@ -0,0 +1,211 @@
+package tags
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidExpression : tag filter not following the expression syntax
+var ErrInvalidExpression = errors.New("invalid tag expression")
+
+// Expr is a boolean expression over the tags of a Deck or a Card.
+type Expr interface {
+	Match(tags []string) bool
+}
+
+type (
+	anyExpr struct{}
+	tagExpr string
+	notExpr struct{ e Expr }
+	andExpr struct{ l, r Expr }
+	orExpr  struct{ l, r Expr }
+)
+
+func (anyExpr) Match([]string) bool        { return true }
+func (e tagExpr) Match(tags []string) bool { return Has(tags, string(e)) }
+func (e notExpr) Match(tags []string) bool { return !e.e.Match(tags) }
+func (e andExpr) Match(tags []string) bool { return e.l.Match(tags) && e.r.Match(tags) }
+func (e orExpr) Match(tags []string) bool  { return e.l.Match(tags) || e.r.Match(tags) }
+
+// Parse reads a tag expression such as
+//
+//	tag:verbs AND NOT (tag:irregular OR tag:"past tense")
+//
+// Terms are tag:NAME, tag:"QUOTED NAME" or plain names, combined with
+// NOT, AND and OR, from the tightest binding, and parentheses (keywords
+// ignoring case). Terms next to each other are ANDed. Tags are compared
+// ignoring case. Blank expressions match everything.
+func Parse(s string) (Expr, error) {
+	toks, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return anyExpr{}, nil
+	}
+	p := &parser{toks: toks}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, ErrInvalidExpression
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokTag tokenKind = iota
+	tokAnd
+	tokOr
+	tokNot
+	tokOpen
+	tokClose
+)
+
+type token struct {
+	kind tokenKind
+	tag  string
+}
+
+func lex(s string) ([]token, error) {
+	var toks []token
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			return toks, nil
+		}
+		switch s[0] {
+		case '(':
+			toks, s = append(toks, token{kind: tokOpen}), s[1:]
+			continue
+		case ')':
+			toks, s = append(toks, token{kind: tokClose}), s[1:]
+			continue
+		}
+		prefixed := len(s) >= 4 && strings.EqualFold(s[:4], "tag:")
+		if prefixed {
+			s = s[4:]
+		}
+		var word string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.Index(s[1:], `"`)
+			if end < 0 {
+				return nil, ErrInvalidExpression
+			}
+			word, s = s[1:end+1], s[end+2:]
+			prefixed = true // quoted names are never keywords
+		} else {
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			word, s = s[:end], s[end:]
+		}
+		if !prefixed {
+			switch strings.ToUpper(word) {
+			case "AND":
+				toks = append(toks, token{kind: tokAnd})
+				continue
+			case "OR":
+				toks = append(toks, token{kind: tokOr})
+				continue
+			case "NOT":
+				toks = append(toks, token{kind: tokNot})
+				continue
+			}
+		}
+		if word = strings.TrimSpace(word); word == "" {
+			return nil, ErrInvalidExpression
+		}
+		toks = append(toks, token{kind: tokTag, tag: word})
+	}
+}
+
+// parser is a recursive descent parser over the tokens of an expression.
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() (tokenKind, bool) {
+	if p.pos >= len(p.toks) {
+		return 0, false
+	}
+	return p.toks[p.pos].kind, true
+}
+
+func (p *parser) or() (Expr, error) {
+	l, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if k, ok := p.peek(); !ok || k != tokOr {
+			return l, nil
+		}
+		p.pos++
+		r, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l = orExpr{l, r}
+	}
+}
+
+func (p *parser) and() (Expr, error) {
+	l, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		k, ok := p.peek()
+		if !ok || k == tokOr || k == tokClose {
+			return l, nil
+		}
+		if k == tokAnd {
+			p.pos++
+		}
+		r, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		l = andExpr{l, r}
+	}
+}
+
+func (p *parser) not() (Expr, error) {
+	k, ok := p.peek()
+	if !ok {
+		return nil, ErrInvalidExpression
+	}
+	switch k {
+	case tokNot:
+		p.pos++
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	case tokOpen:
+		p.pos++
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if k, ok := p.peek(); !ok || k != tokClose {
+			return nil, ErrInvalidExpression
+		}
+		p.pos++
+		return e, nil
+	case tokTag:
+		p.pos++
+		return tagExpr(p.toks[p.pos-1].tag), nil
+	}
+	return nil, ErrInvalidExpression
+}
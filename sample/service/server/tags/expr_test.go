@@ -0,0 +1,57 @@
+package tags
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		expr  string
+		tags  []string
+		match bool
+	}{
+		{"", nil, true},
+		{"  ", []string{"verbs"}, true},
+		{"tag:verbs", []string{"Verbs"}, true},
+		{"verbs", []string{"nouns"}, false},
+		{"TAG:verbs and not irregular", []string{"verbs", "irregular"}, false},
+		{"verbs NOT irregular", []string{"verbs"}, true},
+		{"verbs irregular", []string{"verbs"}, false},
+		// NOT binds tighter than AND, AND tighter than OR.
+		{"NOT a AND b", []string{"b"}, true},
+		{"a OR b AND c", []string{"a"}, true},
+		{"(a OR b) AND c", []string{"a"}, false},
+		{"NOT NOT a", []string{"a"}, true},
+		// Quoted names are never keywords, and may hold spaces.
+		{`tag:"past tense"`, []string{"Past Tense"}, true},
+		{`"OR"`, []string{"or"}, true},
+		{`verbs AND NOT (irregular OR tag:"past tense")`, []string{"verbs", "past tense"}, false},
+		{`verbs AND NOT (irregular OR tag:"past tense")`, []string{"verbs", "present"}, true},
+	} {
+		e, err := Parse(tc.expr)
+		if err != nil {
+			t.Errorf("%q: %v", tc.expr, err)
+			continue
+		}
+		if got := e.Match(tc.tags); got != tc.match {
+			t.Errorf("%q on %v: %v, want %v", tc.expr, tc.tags, got, tc.match)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"AND",
+		"a AND",
+		"a OR OR b",
+		"NOT",
+		"(a",
+		"a)",
+		"()",
+		`tag:"unterminated`,
+		`tag:""`,
+		"tag:",
+	} {
+		if _, err := Parse(expr); err != ErrInvalidExpression {
+			t.Errorf("%q: %v, want %v", expr, err, ErrInvalidExpression)
+		}
+	}
+}
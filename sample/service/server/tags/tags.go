@@ -0,0 +1,69 @@
+package tags
+
+import (
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// Normalize trims tags and drops the empty ones and those equal, ignoring
+// case, to a previous one.
+func Normalize(tags []string) []string {
+	var res []string
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t != "" && !Has(res, t) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Has tells whether tags holds tag, ignoring case.
+func Has(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns tags with add appended and remove left out.
+func Apply(tags []string, add []string, remove []string) []string {
+	var res []string
+	for _, t := range Normalize(append(append([]string(nil), tags...), add...)) {
+		if !Has(remove, t) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Of returns the tags a Card is filtered on: its own and those of its Deck.
+func Of(c clientModel.Card, deck []string) []string {
+	return Normalize(append(append([]string(nil), c.Tags...), deck...))
+}
+
+// FilterDecks returns the Decks whose own tags match e.
+func FilterDecks(decks []clientModel.Deck, e Expr) []clientModel.Deck {
+	res := []clientModel.Deck{}
+	for _, d := range decks {
+		if e.Match(d.Tags) {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+// FilterCards returns the Cards matching e, deck being the tags of their
+// Deck.
+func FilterCards(cards []clientModel.Card, deck []string, e Expr) []clientModel.Card {
+	res := []clientModel.Card{}
+	for _, c := range cards {
+		if e.Match(Of(c, deck)) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
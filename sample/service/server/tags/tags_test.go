@@ -0,0 +1,37 @@
+package tags
+
+import (
+	"reflect"
+	"testing"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+func TestNormalize(t *testing.T) {
+	got := Normalize([]string{" verbs ", "", "Verbs", "fr", "  "})
+	if want := []string{"verbs", "fr"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("normalized %q, want %q", got, want)
+	}
+	if got := Apply([]string{"verbs", "fr"}, []string{"es", "FR"}, []string{"VERBS"}); !reflect.DeepEqual(got, []string{"fr", "es"}) {
+		t.Fatalf("applied %q", got)
+	}
+}
+
+func TestFilterCards(t *testing.T) {
+	cards := []clientModel.Card{
+		{ID: "a", Tags: []string{"irregular"}},
+		{ID: "b"},
+	}
+	e, err := Parse("verbs AND NOT irregular")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cards match on the tags of their Deck too.
+	got := FilterCards(cards, []string{"verbs"}, e)
+	if len(got) != 1 || got[0].ID != "b" {
+		t.Fatalf("filtered %+v, want b", got)
+	}
+	if got := FilterCards(cards, nil, e); len(got) != 0 {
+		t.Fatalf("filtered %+v without the deck tags", got)
+	}
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	model "github.com/TangiFavennec/go-service-sample/sample/service/model"
+)
+
+// NewRepository wraps repo so that every call runs in a child span of the
+// span found in its context.
+func NewRepository(tracer trace.Tracer, repo data.SampleRepository) data.SampleRepository {
+	return &repository{
+		next:   repo,
+		tracer: tracer,
+	}
+}
+
+type repository struct {
+	next   data.SampleRepository
+	tracer trace.Tracer
+}
+
+func (s *repository) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "SampleRepository."+method, trace.WithAttributes(attrs...))
+}
+
+func (s *repository) PostDeck(ctx context.Context, owner string, p model.Deck) (err error) {
+	ctx, span := s.start(ctx, "PostDeck", attribute.String("deck.owner", owner), attribute.String("deck.id", p.ID))
+	defer func() { End(span, err) }()
+	return s.next.PostDeck(ctx, owner, p)
+}
+
+func (s *repository) GetDeck(ctx context.Context, owner string, id string) (p model.Deck, err error) {
+	ctx, span := s.start(ctx, "GetDeck", attribute.String("deck.owner", owner), attribute.String("deck.id", id))
+	defer func() { End(span, err) }()
+	return s.next.GetDeck(ctx, owner, id)
+}
+
+func (s *repository) PutDeck(ctx context.Context, owner string, id string, p model.Deck) (err error) {
+	ctx, span := s.start(ctx, "PutDeck", attribute.String("deck.owner", owner), attribute.String("deck.id", id))
+	defer func() { End(span, err) }()
+	return s.next.PutDeck(ctx, owner, id, p)
+}
+
+func (s *repository) GetDecks(ctx context.Context, owner string) (decks []model.Deck, err error) {
+	ctx, span := s.start(ctx, "GetDecks", attribute.String("deck.owner", owner))
+	defer func() {
+		span.SetAttributes(attribute.Int("decks.count", len(decks)))
+		End(span, err)
+	}()
+	return s.next.GetDecks(ctx, owner)
+}
+
+func (s *repository) GetAllDecks(ctx context.Context) (decks []model.Deck, err error) {
+	ctx, span := s.start(ctx, "GetAllDecks")
+	defer func() {
+		span.SetAttributes(attribute.Int("decks.count", len(decks)))
+		End(span, err)
+	}()
+	return s.next.GetAllDecks(ctx)
+}
+
+func (s *repository) DeleteDeck(ctx context.Context, owner string, id string) (err error) {
+	ctx, span := s.start(ctx, "DeleteDeck", attribute.String("deck.owner", owner), attribute.String("deck.id", id))
+	defer func() { End(span, err) }()
+	return s.next.DeleteDeck(ctx, owner, id)
+}
+
+func (s *repository) GetCards(ctx context.Context, owner string, DeckID string) (cards []model.Card, err error) {
+	ctx, span := s.start(ctx, "GetCards", attribute.String("deck.owner", owner), attribute.String("deck.id", DeckID))
+	defer func() {
+		span.SetAttributes(attribute.Int("cards.count", len(cards)))
+		End(span, err)
+	}()
+	return s.next.GetCards(ctx, owner, DeckID)
+}
+
+func (s *repository) GetCard(ctx context.Context, owner string, DeckID string, CardID string) (a model.Card, err error) {
+	ctx, span := s.start(ctx, "GetCard", attribute.String("deck.owner", owner), attribute.String("deck.id", DeckID), attribute.String("card.id", CardID))
+	defer func() { End(span, err) }()
+	return s.next.GetCard(ctx, owner, DeckID, CardID)
+}
+
+func (s *repository) PostCard(ctx context.Context, owner string, DeckID string, a model.Card) (err error) {
+	ctx, span := s.start(ctx, "PostCard", attribute.String("deck.owner", owner), attribute.String("deck.id", DeckID), attribute.String("card.id", a.ID))
+	defer func() { End(span, err) }()
+	return s.next.PostCard(ctx, owner, DeckID, a)
+}
+
+func (s *repository) DeleteCard(ctx context.Context, owner string, DeckID string, CardID string) (err error) {
+	ctx, span := s.start(ctx, "DeleteCard", attribute.String("deck.owner", owner), attribute.String("deck.id", DeckID), attribute.String("card.id", CardID))
+	defer func() { End(span, err) }()
+	return s.next.DeleteCard(ctx, owner, DeckID, CardID)
+}
+
+func (s *repository) Apply(ctx context.Context, batch []data.Write) (err error) {
+	ctx, span := s.start(ctx, "Apply", attribute.Int("writes.count", len(batch)))
+	defer func() { End(span, err) }()
+	return s.next.Apply(ctx, batch)
+}
@@ -0,0 +1,123 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/endpoints"
+	middlewares "github.com/TangiFavennec/go-service-sample/sample/service/server/middlewares"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tracing"
+)
+
+// setup registers a TracerProvider recording every span, and serves the
+// deck API traced the same way as decksvc.
+func setup(t *testing.T) (*tracetest.SpanRecorder, *httptest.Server) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracing.Register(tp)
+	tracer := tp.Tracer(tracing.InstrumentationName)
+	repo := tracing.NewRepository(tracer, inmem.NewInmemRepository())
+	s := middlewares.TracingMiddleware(tracer)(server.NewService(repo))
+	srv := httptest.NewServer(endpoints.MakeHTTPHandler(s, log.NewNopLogger()))
+	t.Cleanup(srv.Close)
+	return recorder, srv
+}
+
+// span returns the only ended span of kind named name.
+func span(t *testing.T, recorder *tracetest.SpanRecorder, name string, kind trace.SpanKind) sdktrace.ReadOnlySpan {
+	t.Helper()
+	var found []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == name && s.SpanKind() == kind {
+			found = append(found, s)
+		}
+	}
+	if len(found) != 1 {
+		t.Fatalf("%d %s spans named %q, want 1", len(found), kind, name)
+	}
+	return found[0]
+}
+
+func assertChild(t *testing.T, child, parent sdktrace.ReadOnlySpan) {
+	t.Helper()
+	if child.Parent().SpanID() != parent.SpanContext().SpanID() || child.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("%q is not a child of %q", child.Name(), parent.Name())
+	}
+}
+
+func TestServerSpanChain(t *testing.T) {
+	recorder, srv := setup(t)
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	req, _ := http.NewRequest("POST", srv.URL+"/decks", strings.NewReader(`{"id":"d1","name":"Verbs"}`))
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	srv.Close() // waits for the server span to end
+
+	serverSpan := span(t, recorder, "POST /decks", trace.SpanKindServer)
+	if got := serverSpan.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("server span in trace %s, want the inbound %s", got, traceID)
+	}
+	if got := serverSpan.Parent().SpanID().String(); got != parentID || !serverSpan.Parent().IsRemote() {
+		t.Errorf("server span parent %s (remote %t), want the inbound %s", got, serverSpan.Parent().IsRemote(), parentID)
+	}
+	service := span(t, recorder, "SampleService.PostDeck", trace.SpanKindInternal)
+	assertChild(t, service, serverSpan)
+	assertChild(t, span(t, recorder, "SampleRepository.PostDeck", trace.SpanKindInternal), service)
+}
+
+func TestClientPropagation(t *testing.T) {
+	recorder, srv := setup(t)
+	var traceparent string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		srv.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+	e, err := endpoints.MakeClientEndpoints(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, caller := otel.Tracer("test").Start(context.Background(), "caller")
+	err = e.PostDeck(ctx, model.Deck{ID: "d1", Name: "Verbs"})
+	caller.End()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy.Close()
+
+	client := span(t, recorder, "SampleService.PostDeck", trace.SpanKindClient)
+	assertChild(t, client, span(t, recorder, "caller", trace.SpanKindInternal))
+
+	// The outbound traceparent carries the client span.
+	sc := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{"traceparent": traceparent}))
+	if sc.TraceID() != client.SpanContext().TraceID() || sc.SpanID() != client.SpanContext().SpanID() {
+		t.Fatalf("outbound traceparent %q, want the client span %s", traceparent, client.SpanContext().SpanID())
+	}
+	serverSpan := span(t, recorder, "POST /decks", trace.SpanKindServer)
+	assertChild(t, serverSpan, client)
+	assertChild(t, span(t, recorder, "SampleService.PostDeck", trace.SpanKindInternal), serverSpan)
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPServerBefore is a go-kit http RequestFunc extracting the W3C
+// traceparent of the incoming request and starting a server span named
+// after the matched route. The span is ended by HTTPServerFinalizer.
+func HTTPServerBefore(ctx context.Context, r *http.Request) context.Context {
+	route := r.URL.Path
+	if cr := mux.CurrentRoute(r); cr != nil {
+		if tpl, err := cr.GetPathTemplate(); err == nil {
+			route = tpl
+		}
+	}
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+	ctx, _ = otel.Tracer(InstrumentationName).Start(ctx, r.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("http.route", route),
+			attribute.String("url.path", r.URL.Path),
+		),
+	)
+	return ctx
+}
+
+// HTTPServerFinalizer is a go-kit http ServerFinalizerFunc ending the span
+// started by HTTPServerBefore with the response status code.
+func HTTPServerFinalizer(ctx context.Context, code int, r *http.Request) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int("http.response.status_code", code))
+	if code >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, strconv.Itoa(code))
+	}
+	span.End()
+}
+
+// HTTPClientBefore is a go-kit http RequestFunc injecting the trace context
+// of ctx into the outgoing request headers.
+func HTTPClientBefore(ctx context.Context, r *http.Request) context.Context {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+	return ctx
+}
+
+// ClientMiddleware starts a client span around each call of the wrapped
+// endpoint, so HTTPClientBefore propagates it to the server.
+func ClientMiddleware(operation string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+			ctx, span := otel.Tracer(InstrumentationName).Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient))
+			defer func() { End(span, err) }()
+			return next(ctx, request)
+		}
+	}
+}
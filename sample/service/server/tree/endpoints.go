@@ -0,0 +1,171 @@
+package tree
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+)
+
+// ErrUnexpectedResponse is returned by the client methods of Endpoints when
+// an endpoint answers with a response of the wrong type.
+var ErrUnexpectedResponse = errors.New("unexpected response type")
+
+// Endpoints for the deck tree API
+type Endpoints struct {
+	MoveDeckEndpoint      endpoint.Endpoint
+	GetChildrenEndpoint   endpoint.Endpoint
+	GetTreeEndpoint       endpoint.Endpoint
+	GetStudyCardsEndpoint endpoint.Endpoint
+	DeleteSubtreeEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		MoveDeckEndpoint:      MakeMoveDeckEndpoint(s),
+		GetChildrenEndpoint:   MakeGetChildrenEndpoint(s),
+		GetTreeEndpoint:       MakeGetTreeEndpoint(s),
+		GetStudyCardsEndpoint: MakeGetStudyCardsEndpoint(s),
+		DeleteSubtreeEndpoint: MakeDeleteSubtreeEndpoint(s),
+	}
+}
+
+// MakeClientEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the remote instance, via a transport/http.Client.
+func MakeClientEndpoints(instance string, options ...httptransport.ClientOption) (Endpoints, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	tgt, err := url.Parse(instance)
+	if err != nil {
+		return Endpoints{}, err
+	}
+	tgt.Path = ""
+
+	return Endpoints{
+		MoveDeckEndpoint:      httptransport.NewClient("POST", tgt, encodeMoveDeckRequest, decodeMoveDeckResponse, options...).Endpoint(),
+		GetChildrenEndpoint:   httptransport.NewClient("GET", tgt, encodeGetChildrenRequest, decodeGetChildrenResponse, options...).Endpoint(),
+		GetTreeEndpoint:       httptransport.NewClient("GET", tgt, encodeGetTreeRequest, decodeGetTreeResponse, options...).Endpoint(),
+		GetStudyCardsEndpoint: httptransport.NewClient("GET", tgt, encodeGetStudyCardsRequest, decodeGetStudyCardsResponse, options...).Endpoint(),
+		DeleteSubtreeEndpoint: httptransport.NewClient("DELETE", tgt, encodeDeleteSubtreeRequest, decodeDeleteSubtreeResponse, options...).Endpoint(),
+	}, nil
+}
+
+// MoveDeck implements Service. Primarily useful in a client.
+func (e Endpoints) MoveDeck(ctx context.Context, deckID string, parent string) (clientModel.Deck, error) {
+	response, err := e.MoveDeckEndpoint(ctx, clientRequest.MoveDeck{DeckID: deckID, Parent: parent})
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	resp, ok := response.(clientResponse.MoveDeck)
+	if !ok {
+		return clientModel.Deck{}, ErrUnexpectedResponse
+	}
+	return resp.Deck, resp.Err
+}
+
+// GetChildren implements Service. Primarily useful in a client.
+func (e Endpoints) GetChildren(ctx context.Context, deckID string) ([]clientModel.Deck, error) {
+	response, err := e.GetChildrenEndpoint(ctx, clientRequest.GetChildren{DeckID: deckID})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.GetChildren)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Decks, resp.Err
+}
+
+// GetTree implements Service. Primarily useful in a client.
+func (e Endpoints) GetTree(ctx context.Context, deckID string) (clientModel.DeckNode, error) {
+	response, err := e.GetTreeEndpoint(ctx, clientRequest.GetTree{DeckID: deckID})
+	if err != nil {
+		return clientModel.DeckNode{}, err
+	}
+	resp, ok := response.(clientResponse.GetTree)
+	if !ok {
+		return clientModel.DeckNode{}, ErrUnexpectedResponse
+	}
+	return resp.Tree, resp.Err
+}
+
+// GetStudyCards implements Service. Primarily useful in a client.
+func (e Endpoints) GetStudyCards(ctx context.Context, deckID string, tagFilter string) ([]clientModel.StudyCard, error) {
+	response, err := e.GetStudyCardsEndpoint(ctx, clientRequest.GetStudyCards{DeckID: deckID, Tags: tagFilter})
+	if err != nil {
+		return nil, err
+	}
+	resp, ok := response.(clientResponse.GetStudyCards)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return resp.Cards, resp.Err
+}
+
+// DeleteSubtree implements Service. Primarily useful in a client.
+func (e Endpoints) DeleteSubtree(ctx context.Context, deckID string, children string) error {
+	response, err := e.DeleteSubtreeEndpoint(ctx, clientRequest.DeleteSubtree{DeckID: deckID, Children: children})
+	if err != nil {
+		return err
+	}
+	resp, ok := response.(clientResponse.DeleteSubtree)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
+	return resp.Err
+}
+
+// MakeMoveDeckEndpoint returns an endpoint via the passed service.
+func MakeMoveDeckEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.MoveDeck)
+		d, e := s.MoveDeck(ctx, req.DeckID, req.Parent)
+		return clientResponse.MoveDeck{Deck: d, Err: e}, e
+	}
+}
+
+// MakeGetChildrenEndpoint returns an endpoint via the passed service.
+func MakeGetChildrenEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetChildren)
+		d, e := s.GetChildren(ctx, req.DeckID)
+		return clientResponse.GetChildren{Decks: d, Err: e}, e
+	}
+}
+
+// MakeGetTreeEndpoint returns an endpoint via the passed service.
+func MakeGetTreeEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetTree)
+		t, e := s.GetTree(ctx, req.DeckID)
+		return clientResponse.GetTree{Tree: t, Err: e}, e
+	}
+}
+
+// MakeGetStudyCardsEndpoint returns an endpoint via the passed service.
+func MakeGetStudyCardsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetStudyCards)
+		c, e := s.GetStudyCards(ctx, req.DeckID, req.Tags)
+		return clientResponse.GetStudyCards{Cards: c, Err: e}, e
+	}
+}
+
+// MakeDeleteSubtreeEndpoint returns an endpoint via the passed service.
+func MakeDeleteSubtreeEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.DeleteSubtree)
+		e := s.DeleteSubtree(ctx, req.DeckID, req.Children)
+		return clientResponse.DeleteSubtree{Err: e}, e
+	}
+}
@@ -0,0 +1,258 @@
+package tree
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	mapper "github.com/TangiFavennec/go-service-sample/sample/service/server/mappers"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/tags"
+)
+
+// Service organises Decks into trees, a Deck having at most one parent
+// among the Decks of its owner. Subtrees only hold the Decks the caller
+// can see.
+type Service interface {
+	// MoveDeck gives a Deck another parent, none when parent is empty.
+	MoveDeck(ctx context.Context, deckID string, parent string) (clientModel.Deck, error)
+	// GetChildren returns the direct sub-decks of a Deck.
+	GetChildren(ctx context.Context, deckID string) ([]clientModel.Deck, error)
+	// GetTree returns the subtree of a Deck, along with its card counts.
+	GetTree(ctx context.Context, deckID string) (clientModel.DeckNode, error)
+	// GetStudyCards returns the Cards of the subtree of a Deck, depth first,
+	// those matching the tagFilter expression only when set.
+	GetStudyCards(ctx context.Context, deckID string, tagFilter string) ([]clientModel.StudyCard, error)
+	// DeleteSubtree deletes a Deck along with its sub-decks (DeleteCascade),
+	// or once they are moved to its parent (DeleteReparent), changing either
+	// every Deck involved or none. Decks having sub-decks cannot be deleted
+	// otherwise.
+	DeleteSubtree(ctx context.Context, deckID string, children string) error
+}
+
+// Choices for the sub-decks of a deleted Deck.
+const (
+	DeleteCascade  = "cascade"
+	DeleteReparent = "reparent"
+)
+
+var (
+	// ErrInvalidChildren : unknown choice for the sub-decks of a deleted Deck
+	ErrInvalidChildren = errors.New("invalid children choice, use cascade or reparent")
+)
+
+type deckService struct {
+	decks server.SampleService
+}
+
+// NewService Service Constructor. Decks are read and written through
+// decks.
+func NewService(decks server.SampleService) Service {
+	return &deckService{
+		decks: decks,
+	}
+}
+
+// key identifies a Deck among the Decks visible to the caller.
+type key struct {
+	owner string
+	id    string
+}
+
+// forest indexes the Decks visible to the caller, and their sub-decks.
+type forest struct {
+	decks    map[key]clientModel.Deck
+	children map[key][]key
+}
+
+func (s *deckService) forest(ctx context.Context) (*forest, error) {
+	decks, err := s.decks.GetDecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f := &forest{decks: map[key]clientModel.Deck{}, children: map[key][]key{}}
+	for _, d := range decks {
+		owner, id := server.ParseDeckRef(ctx, d.ID)
+		f.decks[key{owner: owner, id: id}] = d
+	}
+	for k, d := range f.decks {
+		parent := key{owner: k.owner, id: d.Parent}
+		if _, ok := f.decks[parent]; ok && d.Parent != "" {
+			f.children[parent] = append(f.children[parent], k)
+		}
+	}
+	for _, children := range f.children {
+		sort.Slice(children, func(i, j int) bool {
+			a, b := f.decks[children[i]], f.decks[children[j]]
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+			return a.ID < b.ID
+		})
+	}
+	return f, nil
+}
+
+// path joins the names of the visible ancestors of k and its own.
+func (f *forest) path(k key) string {
+	var names []string
+	seen := map[key]bool{}
+	for d, ok := f.decks[k]; ok && !seen[k]; d, ok = f.decks[k] {
+		seen[k] = true
+		name := d.Name
+		if name == "" {
+			name = k.id
+		}
+		names = append([]string{name}, names...)
+		k = key{owner: k.owner, id: d.Parent}
+	}
+	return strings.Join(names, server.DeckPathSeparator)
+}
+
+// walk calls fn on k and its visible sub-decks, depth first, parents
+// first.
+func (f *forest) walk(k key, fn func(k key) error) error {
+	if err := fn(k); err != nil {
+		return err
+	}
+	for _, c := range f.children[k] {
+		if err := f.walk(c, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// root checks the caller can see the Deck, and returns it with the forest
+// it belongs to.
+func (s *deckService) root(ctx context.Context, deckID string) (*forest, key, error) {
+	d, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return nil, key{}, err
+	}
+	f, err := s.forest(ctx)
+	if err != nil {
+		return nil, key{}, err
+	}
+	owner, id := server.ParseDeckRef(ctx, deckID)
+	k := key{owner: owner, id: id}
+	f.decks[k] = d
+	return f, k, nil
+}
+
+func (s *deckService) MoveDeck(ctx context.Context, deckID string, parent string) (clientModel.Deck, error) {
+	d, err := s.decks.GetDeck(ctx, deckID)
+	if err != nil {
+		return clientModel.Deck{}, err
+	}
+	// Parents may be referenced like the Deck, as long as they share its owner.
+	owner, _ := server.ParseDeckRef(ctx, deckID)
+	if strings.Contains(parent, server.DeckRefSeparator) {
+		parentOwner, id := server.ParseDeckRef(ctx, parent)
+		if parentOwner != owner {
+			return clientModel.Deck{}, server.ErrInvalidParent
+		}
+		parent = id
+	}
+	d.Parent = parent
+	if err := s.decks.PutDeck(ctx, deckID, mapper.FromClientDeck(d)); err != nil {
+		return clientModel.Deck{}, err
+	}
+	return d, nil
+}
+
+func (s *deckService) GetChildren(ctx context.Context, deckID string) ([]clientModel.Deck, error) {
+	f, k, err := s.root(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	res := []clientModel.Deck{}
+	for _, c := range f.children[k] {
+		res = append(res, f.decks[c])
+	}
+	return res, nil
+}
+
+func (s *deckService) GetTree(ctx context.Context, deckID string) (clientModel.DeckNode, error) {
+	f, k, err := s.root(ctx, deckID)
+	if err != nil {
+		return clientModel.DeckNode{}, err
+	}
+	return f.node(k), nil
+}
+
+func (f *forest) node(k key) clientModel.DeckNode {
+	d := f.decks[k]
+	n := clientModel.DeckNode{
+		ID:        d.ID,
+		Name:      d.Name,
+		Path:      f.path(k),
+		CardCount: len(d.Cards),
+	}
+	n.TotalCardCount = n.CardCount
+	for _, c := range f.children[k] {
+		child := f.node(c)
+		n.TotalCardCount += child.TotalCardCount
+		n.Children = append(n.Children, child)
+	}
+	return n
+}
+
+func (s *deckService) GetStudyCards(ctx context.Context, deckID string, tagFilter string) ([]clientModel.StudyCard, error) {
+	expr, err := tags.Parse(tagFilter)
+	if err != nil {
+		return nil, err
+	}
+	f, k, err := s.root(ctx, deckID)
+	if err != nil {
+		return nil, err
+	}
+	res := []clientModel.StudyCard{}
+	f.walk(k, func(k key) error {
+		d := f.decks[k]
+		for _, c := range tags.FilterCards(d.Cards, d.Tags, expr) {
+			res = append(res, clientModel.StudyCard{DeckID: d.ID, Card: c})
+		}
+		return nil
+	})
+	return res, nil
+}
+
+func (s *deckService) DeleteSubtree(ctx context.Context, deckID string, children string) error {
+	switch children {
+	case "":
+		return s.decks.DeleteDeck(ctx, deckID)
+	case DeleteCascade, DeleteReparent:
+	default:
+		return ErrInvalidChildren
+	}
+	f, k, err := s.root(ctx, deckID)
+	if err != nil {
+		return err
+	}
+	// The writes are stored as one batch, checked as a whole first.
+	var writes []server.DeckWrite
+	if children == DeleteReparent {
+		parent := f.decks[k].Parent
+		for _, c := range f.children[k] {
+			d := f.decks[c]
+			d.Parent = parent
+			writes = append(writes, server.DeckWrite{Kind: data.WritePutDeck, ID: d.ID, Deck: mapper.FromClientDeck(d)})
+		}
+		writes = append(writes, server.DeckWrite{Kind: data.WriteDeleteDeck, ID: deckID})
+		return s.decks.ApplyDecks(ctx, writes)
+	}
+	// Cascade: sub-decks go before their parent.
+	var order []key
+	f.walk(k, func(k key) error {
+		order = append(order, k)
+		return nil
+	})
+	for i := len(order) - 1; i >= 0; i-- {
+		writes = append(writes, server.DeckWrite{Kind: data.WriteDeleteDeck, ID: f.decks[order[i]].ID})
+	}
+	return s.decks.ApplyDecks(ctx, writes)
+}
@@ -0,0 +1,120 @@
+package tree
+
+import (
+	"context"
+	"testing"
+
+	data "github.com/TangiFavennec/go-service-sample/sample/service/data"
+	inmem "github.com/TangiFavennec/go-service-sample/sample/service/data/other"
+	"github.com/TangiFavennec/go-service-sample/sample/service/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	middlewares "github.com/TangiFavennec/go-service-sample/sample/service/server/middlewares"
+)
+
+// newForest returns a Service over the Decks of alice
+//
+//	Languages
+//	├── French (1 card)
+//	└── Spanish
+//	    └── Verbs (2 cards)
+//
+// along with the Decks it goes through and the context of alice.
+func newForest(t *testing.T) (Service, server.SampleService, context.Context) {
+	t.Helper()
+	ctx := auth.NewContext(context.Background(), auth.Principal{Subject: "alice"})
+	decks := server.NewService(middlewares.RepositoryTreeMiddleware()(inmem.NewInmemRepository()))
+	for _, d := range []model.Deck{
+		{ID: "lang", Name: "Languages"},
+		{ID: "es", Name: "Spanish", Parent: "lang"},
+		{ID: "fr", Name: "French", Parent: "lang", Cards: []model.Card{{ID: "c", First: "être", Second: "to be"}}},
+		{ID: "verbs", Name: "Verbs", Parent: "es", Cards: []model.Card{{ID: "c1", First: "ser", Second: "to be"}, {ID: "c2", First: "tener", Second: "to have"}}},
+	} {
+		if err := decks.PostDeck(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewService(decks), decks, ctx
+}
+
+func TestMoveDeck(t *testing.T) {
+	s, decks, ctx := newForest(t)
+	for name, tc := range map[string]struct {
+		deck, parent string
+	}{
+		"itself":       {"es", "es"},
+		"sub-deck":     {"lang", "verbs"},
+		"missing":      {"es", "nope"},
+		"other owner":  {"es", "bob~lang"},
+		"other as ref": {"alice~es", "bob~lang"},
+	} {
+		if _, err := s.MoveDeck(ctx, tc.deck, tc.parent); err != server.ErrInvalidParent {
+			t.Errorf("%s: %v, want %v", name, err, server.ErrInvalidParent)
+		}
+	}
+
+	if _, err := s.MoveDeck(ctx, "verbs", "alice~fr"); err != nil {
+		t.Fatal(err)
+	}
+	children, err := s.GetChildren(ctx, "fr")
+	if err != nil || len(children) != 1 || children[0].ID != "verbs" {
+		t.Fatalf("children of fr %+v (%v), want verbs", children, err)
+	}
+	if _, err := s.MoveDeck(ctx, "verbs", ""); err != nil {
+		t.Fatal(err)
+	}
+	if d, _ := decks.GetDeck(ctx, "verbs"); d.Parent != "" {
+		t.Fatalf("verbs still under %q", d.Parent)
+	}
+}
+
+func TestGetTree(t *testing.T) {
+	s, _, ctx := newForest(t)
+	root, err := s.GetTree(ctx, "lang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.TotalCardCount != 3 || root.CardCount != 0 || len(root.Children) != 2 {
+		t.Fatalf("root %+v", root)
+	}
+	// Children are sorted by name.
+	fr, es := root.Children[0], root.Children[1]
+	if fr.ID != "fr" || es.ID != "es" || es.TotalCardCount != 2 {
+		t.Fatalf("children %+v", root.Children)
+	}
+	if verbs := es.Children[0]; verbs.Path != "Languages::Spanish::Verbs" || verbs.CardCount != 2 {
+		t.Fatalf("verbs %+v", verbs)
+	}
+
+	cards, err := s.GetStudyCards(ctx, "es", "")
+	if err != nil || len(cards) != 2 || cards[0].DeckID != "verbs" {
+		t.Fatalf("study cards %+v (%v)", cards, err)
+	}
+}
+
+func TestDeleteSubtree(t *testing.T) {
+	s, decks, ctx := newForest(t)
+	if err := s.DeleteSubtree(ctx, "es", ""); err != server.ErrHasChildren {
+		t.Fatalf("plain delete: %v, want %v", err, server.ErrHasChildren)
+	}
+	if err := s.DeleteSubtree(ctx, "es", "orphan"); err != ErrInvalidChildren {
+		t.Fatalf("unknown choice: %v, want %v", err, ErrInvalidChildren)
+	}
+
+	if err := s.DeleteSubtree(ctx, "es", DeleteReparent); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := decks.GetDeck(ctx, "verbs"); err != nil || d.Parent != "lang" {
+		t.Fatalf("verbs %+v (%v), want it under lang", d, err)
+	}
+	if _, err := decks.GetDeck(ctx, "es"); err != data.ErrNotFound {
+		t.Fatalf("es: %v, want it deleted", err)
+	}
+
+	if err := s.DeleteSubtree(ctx, "lang", DeleteCascade); err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := decks.GetDecks(ctx); len(left) != 0 {
+		t.Fatalf("left %+v", left)
+	}
+}
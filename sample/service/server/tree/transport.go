@@ -0,0 +1,259 @@
+package tree
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the deck tree endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /decks/:id:move                     moves a Deck under another one, or to the top level
+	// GET     /decks/:id/children                 lists the sub-decks of a Deck
+	// GET     /decks/:id/tree                     retrieves the subtree of a Deck with its card counts
+	// GET     /decks/:id/study?tags=:expr         lists the Cards of the subtree of a Deck to study
+	// DELETE  /decks/:id?children=:choice         removes a Deck with its sub-decks, or moving them up
+
+	r.Methods("POST").Path("/decks/{id}:move").Handler(httptransport.NewServer(
+		e.MoveDeckEndpoint,
+		decodeMoveDeckRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/decks/{id}/children").Handler(httptransport.NewServer(
+		e.GetChildrenEndpoint,
+		decodeGetChildrenRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/decks/{id}/tree").Handler(httptransport.NewServer(
+		e.GetTreeEndpoint,
+		decodeGetTreeRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/decks/{id}/study").Handler(httptransport.NewServer(
+		e.GetStudyCardsEndpoint,
+		decodeGetStudyCardsRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("DELETE").Path("/decks/{id}").Handler(httptransport.NewServer(
+		e.DeleteSubtreeEndpoint,
+		decodeDeleteSubtreeRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodeMoveDeckRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	var move clientModel.DeckMove
+	if e := json.NewDecoder(r.Body).Decode(&move); e != nil {
+		return nil, e
+	}
+	return clientRequest.MoveDeck{DeckID: id, Parent: move.Parent}, nil
+}
+
+func decodeGetChildrenRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetChildren{DeckID: id}, nil
+}
+
+func decodeGetTreeRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetTree{DeckID: id}, nil
+}
+
+func decodeGetStudyCardsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetStudyCards{DeckID: id, Tags: r.URL.Query().Get("tags")}, nil
+}
+
+func decodeDeleteSubtreeRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.DeleteSubtree{DeckID: id, Children: r.URL.Query().Get("children")}, nil
+}
+
+func encodeMoveDeckRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("POST").Path("/decks/{id}:move")
+	r := request.(clientRequest.MoveDeck)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + ":move"
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(clientModel.DeckMove{Parent: r.Parent}); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+func encodeGetChildrenRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/children")
+	r := request.(clientRequest.GetChildren)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/children"
+	return nil
+}
+
+func encodeGetTreeRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/tree")
+	r := request.(clientRequest.GetTree)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/tree"
+	return nil
+}
+
+func encodeGetStudyCardsRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("GET").Path("/decks/{id}/study")
+	r := request.(clientRequest.GetStudyCards)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID) + "/study"
+	if r.Tags != "" {
+		req.URL.RawQuery = url.Values{"tags": {r.Tags}}.Encode()
+	}
+	return nil
+}
+
+func encodeDeleteSubtreeRequest(ctx context.Context, req *http.Request, request interface{}) error {
+	// r.Methods("DELETE").Path("/decks/{id}")
+	r := request.(clientRequest.DeleteSubtree)
+	req.URL.Path = "/decks/" + url.QueryEscape(r.DeckID)
+	if r.Children != "" {
+		req.URL.RawQuery = url.Values{"children": {r.Children}}.Encode()
+	}
+	return nil
+}
+
+func decodeMoveDeckResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.MoveDeck
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetChildrenResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetChildren
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetTreeResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetTree
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeGetStudyCardsResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.GetStudyCards
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeDeleteSubtreeResponse(_ context.Context, resp *http.Response) (interface{}, error) {
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp)
+	}
+	var response clientResponse.DeleteSubtree
+	err := json.NewDecoder(resp.Body).Decode(&response)
+	return response, err
+}
+
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+	for _, known := range []error{ErrInvalidChildren} {
+		if body.Error == known.Error() {
+			return known
+		}
+	}
+	if known := server.KnownError(body.Error); known != nil {
+		return known
+	}
+	return fmt.Errorf("%d %s", resp.StatusCode, body.Error)
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrInvalidChildren:
+		return http.StatusBadRequest
+	default:
+		return server.HTTPStatus(err)
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+type warningsKey struct{}
+
+type warnings struct {
+	mtx  sync.Mutex
+	list []string
+}
+
+// NewWarningsContext returns a copy of ctx collecting the warnings of the
+// calls made with it, such as a Card stored next to a near-duplicate.
+func NewWarningsContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsKey{}, &warnings{})
+}
+
+// AddWarning records a warning in ctx, if it collects them.
+func AddWarning(ctx context.Context, msg string) {
+	if w, ok := ctx.Value(warningsKey{}).(*warnings); ok {
+		w.mtx.Lock()
+		w.list = append(w.list, msg)
+		w.mtx.Unlock()
+	}
+}
+
+// Warnings returns the warnings recorded in ctx.
+func Warnings(ctx context.Context) []string {
+	w, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return nil
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return append([]string(nil), w.list...)
+}
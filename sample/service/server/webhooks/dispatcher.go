@@ -0,0 +1,557 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	mrand "math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/feed"
+)
+
+const (
+	// deliveryLogSize is the number of deliveries kept per webhook.
+	deliveryLogSize = 100
+	// deadLetterSize is the number of dead deliveries kept overall.
+	deadLetterSize = 1000
+	// queueSize bounds the deliveries waiting for a free worker.
+	queueSize = 1024
+)
+
+// Headers set on every delivery request.
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Policy tunes the delivery of webhooks.
+type Policy struct {
+	// MaxAttempts before a delivery is moved to the dead-letter list.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, doubled on every retry
+	// up to MaxBackoff. The actual delay is jittered between half and all of it.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Timeout of a single delivery request.
+	Timeout time.Duration
+}
+
+// DefaultPolicy : 8 attempts spread over about half an hour
+var DefaultPolicy = Policy{
+	MaxAttempts: 8,
+	MinBackoff:  time.Second,
+	MaxBackoff:  10 * time.Minute,
+	Timeout:     10 * time.Second,
+}
+
+type webhook struct {
+	clientModel.Webhook
+	owner string // only the events of the Decks of owner are delivered
+	log   []*delivery
+}
+
+type delivery struct {
+	clientModel.WebhookDelivery
+	payload []byte
+	tries   int // attempts since creation or the last manual redelivery
+}
+
+// Dispatcher implements Service. Once started, it delivers the events of a
+// change feed Broker to the matching webhooks as signed JSON POSTs.
+type Dispatcher struct {
+	mtx        sync.Mutex
+	webhooks   map[string]*webhook
+	order      []string
+	deliveries map[string]*delivery
+	dead       []*delivery
+
+	client *http.Client
+	policy Policy
+	// allowPrivate lets webhooks target forbidden addresses, for tests.
+	allowPrivate bool
+	logger       log.Logger
+	queue        chan string
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewDispatcher returns a Dispatcher sending requests through client, the
+// one of NewClient when nil.
+func NewDispatcher(client *http.Client, policy Policy, logger log.Logger) *Dispatcher {
+	if client == nil {
+		client = NewClient()
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Dispatcher{
+		webhooks:   map[string]*webhook{},
+		deliveries: map[string]*delivery{},
+		client:     client,
+		policy:     policy,
+		logger:     logger,
+		queue:      make(chan string, queueSize),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start consumes the events of the broker and delivers them with the given
+// number of concurrent workers.
+func (d *Dispatcher) Start(b *feed.Broker, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	d.wg.Add(workers + 1)
+	go d.consume(b)
+	for i := 0; i < workers; i++ {
+		go d.work()
+	}
+}
+
+// Stop waits for in-flight deliveries then stops the dispatcher. Pending
+// retries are abandoned.
+func (d *Dispatcher) Stop() {
+	close(d.done)
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) consume(b *feed.Broker) {
+	defer d.wg.Done()
+	var last uint64
+	for {
+		sub := b.SubscribeAll(last)
+	events:
+		for {
+			select {
+			case <-d.done:
+				sub.Close()
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					break events // dropped for lagging behind: resume from last
+				}
+				if e.Type == feed.Reset {
+					d.logger.Log("msg", "change feed events were lost before delivery", "after", last)
+					continue
+				}
+				last = e.ID
+				d.dispatch(e)
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(e feed.Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		d.logger.Log("event", e.ID, "err", err)
+		return
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for _, id := range d.order {
+		w := d.webhooks[id]
+		if w.owner != e.Owner || !subscribed(w.EventTypes, string(e.Type)) {
+			continue
+		}
+		dl := &delivery{
+			WebhookDelivery: clientModel.WebhookDelivery{
+				ID:        newID(),
+				WebhookID: w.ID,
+				EventID:   e.ID,
+				EventType: string(e.Type),
+				Status:    StatusPending,
+				CreatedAt: time.Now().UTC(),
+			},
+			payload: payload,
+		}
+		d.deliveries[dl.ID] = dl
+		d.appendLog(w, dl)
+		d.schedule(dl, 0)
+	}
+}
+
+// appendLog must be called with the lock held.
+func (d *Dispatcher) appendLog(w *webhook, dl *delivery) {
+	w.log = append(w.log, dl)
+	if len(w.log) <= deliveryLogSize {
+		return
+	}
+	// Evict the oldest finished delivery, pending ones are still referenced
+	// by the queue.
+	for i, old := range w.log {
+		if old.Status != StatusPending {
+			w.log = append(w.log[:i], w.log[i+1:]...)
+			if !contains(d.dead, old) {
+				delete(d.deliveries, old.ID)
+			}
+			return
+		}
+	}
+	// Every delivery is pending, the target being down for long: the oldest
+	// one is given up on, and can still be redelivered as a dead letter.
+	old := w.log[0]
+	w.log = w.log[1:]
+	d.bury(old)
+	d.logger.Log("webhook", w.ID, "delivery", old.ID, "msg", "moved to dead letters", "err", "too many pending deliveries")
+}
+
+// bury moves dl to the dead letters, and must be called with the lock held.
+func (d *Dispatcher) bury(dl *delivery) {
+	dl.Status = StatusDead
+	dl.NextAttempt = nil
+	d.dead = append(d.dead, dl)
+	if len(d.dead) > deadLetterSize {
+		old := d.dead[0]
+		d.dead = d.dead[1:]
+		if w, ok := d.webhooks[old.WebhookID]; !ok || !contains(w.log, old) {
+			delete(d.deliveries, old.ID)
+		}
+	}
+}
+
+// schedule must be called with the lock held.
+func (d *Dispatcher) schedule(dl *delivery, delay time.Duration) {
+	if delay > 0 {
+		next := time.Now().Add(delay).UTC()
+		dl.NextAttempt = &next
+	} else {
+		dl.NextAttempt = nil
+	}
+	id := dl.ID
+	time.AfterFunc(delay, func() {
+		select {
+		case d.queue <- id:
+		case <-d.done:
+		}
+	})
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-d.done:
+			return
+		case id := <-d.queue:
+			d.attempt(id)
+		}
+	}
+}
+
+func (d *Dispatcher) attempt(id string) {
+	d.mtx.Lock()
+	dl, ok := d.deliveries[id]
+	if !ok || dl.Status != StatusPending {
+		d.mtx.Unlock()
+		return
+	}
+	w, ok := d.webhooks[dl.WebhookID]
+	if !ok {
+		delete(d.deliveries, id) // webhook deleted meanwhile
+		d.mtx.Unlock()
+		return
+	}
+	target, secret, event, payload := w.URL, w.Secret, dl.EventType, dl.payload
+	d.mtx.Unlock()
+
+	begin := time.Now()
+	code, err := d.send(target, secret, id, event, payload)
+	a := clientModel.WebhookAttempt{
+		Time:       begin.UTC(),
+		StatusCode: code,
+		Duration:   time.Since(begin),
+	}
+	if err == nil && (code < 200 || code > 299) {
+		err = fmt.Errorf("unexpected status %d", code)
+	}
+	if err != nil {
+		a.Error = err.Error()
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	dl.Attempts = append(dl.Attempts, a)
+	dl.tries++
+	if dl.Status != StatusPending {
+		return // given up on meanwhile
+	}
+	switch {
+	case err == nil:
+		dl.Status = StatusSucceeded
+		dl.NextAttempt = nil
+	case dl.tries >= d.policy.MaxAttempts:
+		d.bury(dl)
+		d.logger.Log("webhook", dl.WebhookID, "delivery", dl.ID, "msg", "moved to dead letters", "err", err)
+	default:
+		d.schedule(dl, d.backoff(dl.tries))
+	}
+}
+
+// backoff returns the jittered delay before retry number n (starting at 1).
+func (d *Dispatcher) backoff(n int) time.Duration {
+	b := d.policy.MinBackoff
+	for i := 1; i < n && b < d.policy.MaxBackoff; i++ {
+		b *= 2
+	}
+	if b > d.policy.MaxBackoff {
+		b = d.policy.MaxBackoff
+	}
+	if b <= 0 {
+		return 0
+	}
+	return b/2 + time.Duration(mrand.Int63n(int64(b/2)+1))
+}
+
+func (d *Dispatcher) send(target, secret, id, event string, payload []byte) (int, error) {
+	ctx := context.Background()
+	if d.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.policy.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequest("POST", target, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(HeaderID, id)
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, Sign(secret, ts, payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value of a delivery: the hex encoded
+// HMAC-SHA256 of "{timestamp}.{body}" keyed with the webhook secret.
+// Receivers recompute it to authenticate the request and should reject
+// stale timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Dispatcher) PostWebhook(ctx context.Context, in clientModel.Webhook) (clientModel.Webhook, error) {
+	u, err := url.Parse(in.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return clientModel.Webhook{}, ErrInvalidURL
+	}
+	if !d.allowPrivate {
+		if err := checkHost(ctx, u.Hostname()); err != nil {
+			return clientModel.Webhook{}, err
+		}
+	}
+	for _, t := range in.EventTypes {
+		if !knownEventType(t) {
+			return clientModel.Webhook{}, ErrUnknownEventType
+		}
+	}
+	w := &webhook{Webhook: clientModel.Webhook{
+		ID:         newID(),
+		URL:        in.URL,
+		EventTypes: in.EventTypes,
+		Secret:     in.Secret,
+		CreatedAt:  time.Now().UTC(),
+	}, owner: auth.Subject(ctx)}
+	if w.Secret == "" {
+		w.Secret = newID() + newID()
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.webhooks[w.ID] = w
+	d.order = append(d.order, w.ID)
+	return w.Webhook, nil
+}
+
+// lookup returns webhook id if it belongs to the caller. It must be called
+// with the lock held.
+func (d *Dispatcher) lookup(ctx context.Context, id string) (*webhook, bool) {
+	w, ok := d.webhooks[id]
+	if !ok || w.owner != auth.Subject(ctx) {
+		return nil, false
+	}
+	return w, true
+}
+
+func (d *Dispatcher) GetWebhook(ctx context.Context, id string) (clientModel.Webhook, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	w, ok := d.lookup(ctx, id)
+	if !ok {
+		return clientModel.Webhook{}, ErrNotFound
+	}
+	return redact(w.Webhook), nil
+}
+
+func (d *Dispatcher) GetWebhooks(ctx context.Context) ([]clientModel.Webhook, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	res := []clientModel.Webhook{}
+	for _, id := range d.order {
+		if w, ok := d.lookup(ctx, id); ok {
+			res = append(res, redact(w.Webhook))
+		}
+	}
+	return res, nil
+}
+
+func (d *Dispatcher) DeleteWebhook(ctx context.Context, id string) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	w, ok := d.lookup(ctx, id)
+	if !ok {
+		return ErrNotFound
+	}
+	delete(d.webhooks, id)
+	for i, other := range d.order {
+		if other == id {
+			d.order = append(d.order[:i], d.order[i+1:]...)
+			break
+		}
+	}
+	for _, dl := range w.log {
+		delete(d.deliveries, dl.ID)
+	}
+	dead := d.dead[:0]
+	for _, dl := range d.dead {
+		if dl.WebhookID != id {
+			dead = append(dead, dl)
+		}
+	}
+	d.dead = dead
+	return nil
+}
+
+func (d *Dispatcher) GetDeliveries(ctx context.Context, id string) ([]clientModel.WebhookDelivery, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	w, ok := d.lookup(ctx, id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return snapshot(w.log), nil
+}
+
+func (d *Dispatcher) GetDeadLetters(ctx context.Context) ([]clientModel.WebhookDelivery, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	var dead []*delivery
+	for _, dl := range d.dead {
+		if _, ok := d.lookup(ctx, dl.WebhookID); ok {
+			dead = append(dead, dl)
+		}
+	}
+	return snapshot(dead), nil
+}
+
+func (d *Dispatcher) Redeliver(ctx context.Context, id string, deliveryID string) (clientModel.WebhookDelivery, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	w, ok := d.lookup(ctx, id)
+	if !ok {
+		return clientModel.WebhookDelivery{}, ErrNotFound
+	}
+	dl, ok := d.deliveries[deliveryID]
+	if !ok || dl.WebhookID != id {
+		return clientModel.WebhookDelivery{}, ErrNotFound
+	}
+	if dl.Status == StatusPending {
+		return clientModel.WebhookDelivery{}, ErrDeliveryPending
+	}
+	if dl.Status == StatusDead {
+		for i, other := range d.dead {
+			if other == dl {
+				d.dead = append(d.dead[:i], d.dead[i+1:]...)
+				break
+			}
+		}
+	}
+	dl.Status = StatusPending
+	dl.tries = 0
+	if !contains(w.log, dl) {
+		d.appendLog(w, dl)
+	}
+	d.schedule(dl, 0)
+	return snapshot([]*delivery{dl})[0], nil
+}
+
+func snapshot(list []*delivery) []clientModel.WebhookDelivery {
+	res := make([]clientModel.WebhookDelivery, 0, len(list))
+	for _, dl := range list {
+		c := dl.WebhookDelivery
+		c.Attempts = append([]clientModel.WebhookAttempt(nil), dl.Attempts...)
+		res = append(res, c)
+	}
+	return res
+}
+
+func contains(list []*delivery, dl *delivery) bool {
+	for _, other := range list {
+		if other == dl {
+			return true
+		}
+	}
+	return false
+}
+
+func redact(w clientModel.Webhook) clientModel.Webhook {
+	w.Secret = ""
+	return w
+}
+
+func subscribed(types []string, t string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, other := range types {
+		if other == t {
+			return true
+		}
+	}
+	return false
+}
+
+func knownEventType(t string) bool {
+	switch feed.EventType(t) {
+	case feed.DeckCreated, feed.DeckUpdated, feed.DeckDeleted, feed.CardCreated, feed.CardDeleted:
+		return true
+	}
+	return false
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,274 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/auth"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/feed"
+)
+
+var testPolicy = Policy{
+	MaxAttempts: 3,
+	MinBackoff:  20 * time.Millisecond,
+	MaxBackoff:  80 * time.Millisecond,
+	Timeout:     time.Second,
+}
+
+// receiver is a local webhook endpoint answering the status codes of
+// statuses in turn, then the last one.
+type receiver struct {
+	mtx      sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+	times    []time.Time
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	r.times = append(r.times, time.Now())
+	status := r.statuses[0]
+	if len(r.statuses) > 1 {
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *receiver) count() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return len(r.requests)
+}
+
+// setup starts a Dispatcher of policy delivering to a local receiver, and
+// registers a webhook of alice on it.
+func setup(t *testing.T, policy Policy, statuses ...int) (*Dispatcher, *receiver, clientModel.Webhook) {
+	rcv := &receiver{statuses: statuses}
+	srv := httptest.NewServer(rcv)
+	t.Cleanup(srv.Close)
+	d := NewDispatcher(srv.Client(), policy, log.NewNopLogger())
+	d.allowPrivate = true
+	d.Start(feed.NewBroker(16), 2)
+	t.Cleanup(d.Stop)
+	w, err := d.PostWebhook(alice(), clientModel.Webhook{URL: srv.URL + "/hook", Secret: "s3cr3t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, rcv, w
+}
+
+func alice() context.Context {
+	return auth.NewContext(context.Background(), auth.Principal{Subject: "alice"})
+}
+
+func deckCreated(id uint64) feed.Event {
+	return feed.Event{ID: id, Type: feed.DeckCreated, Owner: "alice", DeckID: "d"}
+}
+
+// waitDelivery waits for the only delivery of w to leave the pending status.
+func waitDelivery(t *testing.T, d *Dispatcher, w clientModel.Webhook) clientModel.WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		dls, err := d.GetDeliveries(alice(), w.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(dls) == 1 && dls[0].Status != StatusPending {
+			return dls[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("delivery still pending")
+	return clientModel.WebhookDelivery{}
+}
+
+func TestDeliverySignature(t *testing.T) {
+	d, rcv, w := setup(t, testPolicy, http.StatusOK)
+	d.dispatch(deckCreated(1))
+	if dl := waitDelivery(t, d, w); dl.Status != StatusSucceeded {
+		t.Fatalf("status %s, want %s", dl.Status, StatusSucceeded)
+	}
+
+	req, body := rcv.requests[0], rcv.bodies[0]
+	if req.URL.Path != "/hook" || req.Method != "POST" {
+		t.Fatalf("got %s %s", req.Method, req.URL.Path)
+	}
+	if req.Header.Get(HeaderEvent) != string(feed.DeckCreated) || req.Header.Get(HeaderID) == "" {
+		t.Fatalf("unexpected headers %v", req.Header)
+	}
+	ts := req.Header.Get(HeaderTimestamp)
+	if !hmac.Equal([]byte(req.Header.Get(HeaderSignature)), []byte(Sign("s3cr3t", ts, body))) {
+		t.Fatal("signature does not verify with the secret")
+	}
+	if hmac.Equal([]byte(req.Header.Get(HeaderSignature)), []byte(Sign("other", ts, body))) {
+		t.Fatal("signature verifies with another secret")
+	}
+	var e feed.Event
+	if err := json.Unmarshal(body, &e); err != nil || e.ID != 1 || e.DeckID != "d" {
+		t.Fatalf("unexpected payload %s (%v)", body, err)
+	}
+}
+
+func TestOtherOwnersAreNotDelivered(t *testing.T) {
+	d, rcv, w := setup(t, testPolicy, http.StatusOK)
+	d.dispatch(feed.Event{ID: 1, Type: feed.DeckCreated, Owner: "bob", DeckID: "d"})
+	d.dispatch(deckCreated(2))
+	if dl := waitDelivery(t, d, w); dl.EventID != 2 {
+		t.Fatalf("delivered event %d, want 2", dl.EventID)
+	}
+	if n := rcv.count(); n != 1 {
+		t.Fatalf("%d requests, want 1", n)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	d, rcv, w := setup(t, testPolicy, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusNoContent)
+	d.dispatch(deckCreated(1))
+	dl := waitDelivery(t, d, w)
+	if dl.Status != StatusSucceeded || len(dl.Attempts) != 3 {
+		t.Fatalf("status %s after %d attempts, want %s after 3", dl.Status, len(dl.Attempts), StatusSucceeded)
+	}
+	if dl.Attempts[0].StatusCode != http.StatusInternalServerError || dl.Attempts[0].Error == "" || dl.Attempts[2].Error != "" {
+		t.Fatalf("unexpected attempts %+v", dl.Attempts)
+	}
+	// Retries wait at least half of the doubling backoff.
+	for i, min := range []time.Duration{testPolicy.MinBackoff / 2, testPolicy.MinBackoff} {
+		if gap := rcv.times[i+1].Sub(rcv.times[i]); gap < min {
+			t.Errorf("retry %d after %s, want at least %s", i+1, gap, min)
+		}
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	d := NewDispatcher(nil, testPolicy, log.NewNopLogger())
+	for n, want := range map[int]time.Duration{1: 20 * time.Millisecond, 2: 40 * time.Millisecond, 3: 80 * time.Millisecond, 10: 80 * time.Millisecond} {
+		for i := 0; i < 100; i++ {
+			if b := d.backoff(n); b < want/2 || b > want {
+				t.Fatalf("backoff(%d) = %s, want within [%s, %s]", n, b, want/2, want)
+			}
+		}
+	}
+}
+
+func TestDeadLetterAndRedelivery(t *testing.T) {
+	d, rcv, w := setup(t, testPolicy, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+	d.dispatch(deckCreated(1))
+	dl := waitDelivery(t, d, w)
+	if dl.Status != StatusDead || len(dl.Attempts) != testPolicy.MaxAttempts {
+		t.Fatalf("status %s after %d attempts, want %s after %d", dl.Status, len(dl.Attempts), StatusDead, testPolicy.MaxAttempts)
+	}
+	dead, err := d.GetDeadLetters(alice())
+	if err != nil || len(dead) != 1 || dead[0].ID != dl.ID {
+		t.Fatalf("dead letters %+v (%v), want %s", dead, err, dl.ID)
+	}
+	if dead, _ := d.GetDeadLetters(auth.NewContext(context.Background(), auth.Principal{Subject: "bob"})); len(dead) != 0 {
+		t.Fatalf("bob sees the dead letters of alice: %+v", dead)
+	}
+
+	if _, err := d.Redeliver(alice(), w.ID, "missing"); err != ErrNotFound {
+		t.Fatalf("redelivering a missing delivery: %v, want %v", err, ErrNotFound)
+	}
+	re, err := d.Redeliver(alice(), w.ID, dl.ID)
+	if err != nil || re.Status != StatusPending {
+		t.Fatalf("redelivery %+v (%v)", re, err)
+	}
+	if dead, _ := d.GetDeadLetters(alice()); len(dead) != 0 {
+		t.Fatalf("redelivery left dead letters %+v", dead)
+	}
+	dl = waitDelivery(t, d, w)
+	if dl.Status != StatusSucceeded || len(dl.Attempts) != testPolicy.MaxAttempts+1 {
+		t.Fatalf("status %s after %d attempts, want %s after %d", dl.Status, len(dl.Attempts), StatusSucceeded, testPolicy.MaxAttempts+1)
+	}
+	if n := rcv.count(); n != testPolicy.MaxAttempts+1 {
+		t.Fatalf("%d requests, want %d", n, testPolicy.MaxAttempts+1)
+	}
+	if _, err := d.Redeliver(alice(), w.ID, dl.ID); err != nil {
+		t.Fatalf("redelivering a succeeded delivery: %v", err)
+	}
+}
+
+func TestForbiddenURLs(t *testing.T) {
+	d := NewDispatcher(nil, testPolicy, log.NewNopLogger())
+	for _, u := range []string{
+		"http://127.0.0.1:8081/media/gc",
+		"http://localhost:8081/media/gc",
+		"http://[::1]/",
+		"http://10.0.0.1/",
+		"https://172.16.5.4/",
+		"http://192.168.1.1/",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[fe80::1]/",
+		"http://[fd00::1]/",
+		"http://0.0.0.0/",
+		"http://100.64.0.1/",
+		"http://[::ffff:127.0.0.1]/",
+	} {
+		if _, err := d.PostWebhook(alice(), clientModel.Webhook{URL: u}); err != ErrForbiddenURL {
+			t.Errorf("%s: %v, want %v", u, err, ErrForbiddenURL)
+		}
+	}
+	if _, err := d.PostWebhook(alice(), clientModel.Webhook{URL: "https://93.184.216.34/hook"}); err != nil {
+		t.Errorf("public address: %v", err)
+	}
+}
+
+func TestClientRefusesForbiddenAddresses(t *testing.T) {
+	rcv := &receiver{statuses: []int{http.StatusOK}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	d := NewDispatcher(nil, Policy{MaxAttempts: 1}, log.NewNopLogger())
+	d.allowPrivate = true // registered anyway, as if it resolved elsewhere then
+	d.Start(feed.NewBroker(16), 1)
+	defer d.Stop()
+	w, err := d.PostWebhook(alice(), clientModel.Webhook{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.dispatch(deckCreated(1))
+	dl := waitDelivery(t, d, w)
+	if dl.Status != StatusDead || !strings.Contains(dl.Attempts[0].Error, ErrForbiddenURL.Error()) {
+		t.Fatalf("status %s, attempts %+v", dl.Status, dl.Attempts)
+	}
+	if n := rcv.count(); n != 0 {
+		t.Fatalf("%d requests reached the receiver", n)
+	}
+}
+
+func TestPendingDeliveriesAreCapped(t *testing.T) {
+	policy := Policy{MaxAttempts: 10, MinBackoff: time.Hour, MaxBackoff: time.Hour, Timeout: time.Second}
+	d, _, w := setup(t, policy, http.StatusServiceUnavailable)
+	for i := 1; i <= deliveryLogSize+5; i++ {
+		d.dispatch(deckCreated(uint64(i)))
+	}
+	dls, err := d.GetDeliveries(alice(), w.ID)
+	if err != nil || len(dls) != deliveryLogSize {
+		t.Fatalf("%d deliveries (%v), want %d", len(dls), err, deliveryLogSize)
+	}
+	dead, _ := d.GetDeadLetters(alice())
+	if len(dead) != 5 || dead[0].EventID != 1 {
+		t.Fatalf("%d dead letters, want the 5 oldest deliveries", len(dead))
+	}
+	d.mtx.Lock()
+	n := len(d.deliveries)
+	d.mtx.Unlock()
+	if n != deliveryLogSize+5 {
+		t.Fatalf("%d deliveries kept, want %d", n, deliveryLogSize+5)
+	}
+}
@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"context"
+
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	clientResponse "github.com/TangiFavennec/go-service-sample/sample/service/client/response"
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Endpoints for the webhooks API
+type Endpoints struct {
+	PostWebhookEndpoint    endpoint.Endpoint
+	GetWebhookEndpoint     endpoint.Endpoint
+	GetWebhooksEndpoint    endpoint.Endpoint
+	DeleteWebhookEndpoint  endpoint.Endpoint
+	GetDeliveriesEndpoint  endpoint.Endpoint
+	GetDeadLettersEndpoint endpoint.Endpoint
+	RedeliverEndpoint      endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeServerEndpoints(s Service) Endpoints {
+	return Endpoints{
+		PostWebhookEndpoint:    MakePostWebhookEndpoint(s),
+		GetWebhookEndpoint:     MakeGetWebhookEndpoint(s),
+		GetWebhooksEndpoint:    MakeGetWebhooksEndpoint(s),
+		DeleteWebhookEndpoint:  MakeDeleteWebhookEndpoint(s),
+		GetDeliveriesEndpoint:  MakeGetDeliveriesEndpoint(s),
+		GetDeadLettersEndpoint: MakeGetDeadLettersEndpoint(s),
+		RedeliverEndpoint:      MakeRedeliverEndpoint(s),
+	}
+}
+
+// MakePostWebhookEndpoint returns an endpoint via the passed service.
+func MakePostWebhookEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.PostWebhook)
+		w, e := s.PostWebhook(ctx, req.Webhook)
+		return clientResponse.PostWebhook{Webhook: w, Err: e}, e
+	}
+}
+
+// MakeGetWebhookEndpoint returns an endpoint via the passed service.
+func MakeGetWebhookEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetWebhook)
+		w, e := s.GetWebhook(ctx, req.ID)
+		return clientResponse.GetWebhook{Webhook: w, Err: e}, e
+	}
+}
+
+// MakeGetWebhooksEndpoint returns an endpoint via the passed service.
+func MakeGetWebhooksEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		w, e := s.GetWebhooks(ctx)
+		return clientResponse.GetWebhooks{Webhooks: w, Err: e}, e
+	}
+}
+
+// MakeDeleteWebhookEndpoint returns an endpoint via the passed service.
+func MakeDeleteWebhookEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.DeleteWebhook)
+		e := s.DeleteWebhook(ctx, req.ID)
+		return clientResponse.DeleteWebhook{Err: e}, e
+	}
+}
+
+// MakeGetDeliveriesEndpoint returns an endpoint via the passed service.
+func MakeGetDeliveriesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.GetWebhookDeliveries)
+		d, e := s.GetDeliveries(ctx, req.ID)
+		return clientResponse.GetWebhookDeliveries{Deliveries: d, Err: e}, e
+	}
+}
+
+// MakeGetDeadLettersEndpoint returns an endpoint via the passed service.
+func MakeGetDeadLettersEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		d, e := s.GetDeadLetters(ctx)
+		return clientResponse.GetWebhookDeliveries{Deliveries: d, Err: e}, e
+	}
+}
+
+// MakeRedeliverEndpoint returns an endpoint via the passed service.
+func MakeRedeliverEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(clientRequest.RedeliverWebhook)
+		d, e := s.Redeliver(ctx, req.ID, req.DeliveryID)
+		return clientResponse.RedeliverWebhook{Delivery: d, Err: e}, e
+	}
+}
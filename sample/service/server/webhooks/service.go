@@ -0,0 +1,39 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+)
+
+// Service manages webhook subscriptions and their deliveries.
+type Service interface {
+	PostWebhook(ctx context.Context, w clientModel.Webhook) (clientModel.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (clientModel.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]clientModel.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	GetDeliveries(ctx context.Context, id string) ([]clientModel.WebhookDelivery, error)
+	GetDeadLetters(ctx context.Context) ([]clientModel.WebhookDelivery, error)
+	Redeliver(ctx context.Context, id string, deliveryID string) (clientModel.WebhookDelivery, error)
+}
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusDead      = "dead"
+)
+
+var (
+	// ErrNotFound : Webhook or delivery not found
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidURL : Webhook URL is not an absolute http(s) URL
+	ErrInvalidURL = errors.New("invalid webhook URL")
+	// ErrForbiddenURL : Webhook URL targets a loopback, private or link-local address
+	ErrForbiddenURL = errors.New("webhook URL targets a loopback, private or link-local address")
+	// ErrUnknownEventType : Webhook subscribes to an event type that does not exist
+	ErrUnknownEventType = errors.New("unknown event type")
+	// ErrDeliveryPending : Redelivery asked for a delivery still being retried
+	ErrDeliveryPending = errors.New("delivery is still pending")
+)
@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// sharedAddressSpace is the carrier-grade NAT range, as internal as the
+// private ranges.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// forbidden tells whether webhooks may not target ip: loopback, private,
+// link-local and other non public unicast addresses.
+func forbidden(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+// checkHost returns ErrForbiddenURL when host is, or resolves to, a
+// forbidden address. Hosts which do not resolve yet are left to the check
+// of NewClient when delivering.
+func checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if forbidden(ip) {
+			return ErrForbiddenURL
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, a := range addrs {
+		if forbidden(a.IP) {
+			return ErrForbiddenURL
+		}
+	}
+	return nil
+}
+
+// NewClient returns the http.Client of NewDispatcher, which refuses to
+// connect to forbidden addresses whatever webhook host names resolve to when
+// delivering, redirects included. Proxies from the environment are ignored
+// for the same reason.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || forbidden(ip) {
+				return ErrForbiddenURL
+			}
+			return nil
+		},
+	}
+	return &http.Client{Transport: &http.Transport{
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}}
+}
@@ -0,0 +1,169 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+
+	clientModel "github.com/TangiFavennec/go-service-sample/sample/service/client/model"
+	clientRequest "github.com/TangiFavennec/go-service-sample/sample/service/client/request"
+	"github.com/TangiFavennec/go-service-sample/sample/service/server/logging"
+)
+
+var (
+	// ErrBadRouting is returned when an expected path variable is missing.
+	// It always indicates programmer error.
+	ErrBadRouting = errors.New("inconsistent mapping between route and handler (programmer error)")
+)
+
+// MakeHTTPHandler mounts all of the webhooks endpoints into an http.Handler.
+func MakeHTTPHandler(s Service, logger log.Logger) http.Handler {
+	r := mux.NewRouter()
+	e := MakeServerEndpoints(s)
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(logging.NewErrorHandler(logger)),
+		httptransport.ServerErrorEncoder(encodeError),
+	}
+
+	// POST    /webhooks                                         registers a webhook
+	// GET     /webhooks                                         lists the webhooks
+	// GET     /webhooks/dead-letters                            lists deliveries that ran out of attempts
+	// GET     /webhooks/:id                                     retrieves the given webhook
+	// DELETE  /webhooks/:id                                     removes the given webhook
+	// GET     /webhooks/:id/deliveries                          retrieves the webhook delivery log
+	// POST    /webhooks/:id/deliveries/:deliveryID/redeliver    delivers again a finished delivery
+
+	r.Methods("POST").Path("/webhooks").Handler(httptransport.NewServer(
+		e.PostWebhookEndpoint,
+		decodePostWebhookRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/webhooks").Handler(httptransport.NewServer(
+		e.GetWebhooksEndpoint,
+		decodeNoRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/webhooks/dead-letters").Handler(httptransport.NewServer(
+		e.GetDeadLettersEndpoint,
+		decodeNoRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/webhooks/{id}").Handler(httptransport.NewServer(
+		e.GetWebhookEndpoint,
+		decodeGetWebhookRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("DELETE").Path("/webhooks/{id}").Handler(httptransport.NewServer(
+		e.DeleteWebhookEndpoint,
+		decodeDeleteWebhookRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("GET").Path("/webhooks/{id}/deliveries").Handler(httptransport.NewServer(
+		e.GetDeliveriesEndpoint,
+		decodeGetDeliveriesRequest,
+		encodeResponse,
+		options...,
+	))
+	r.Methods("POST").Path("/webhooks/{id}/deliveries/{deliveryID}/redeliver").Handler(httptransport.NewServer(
+		e.RedeliverEndpoint,
+		decodeRedeliverRequest,
+		encodeResponse,
+		options...,
+	))
+	return r
+}
+
+func decodePostWebhookRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var w clientModel.Webhook
+	if e := json.NewDecoder(r.Body).Decode(&w); e != nil {
+		return nil, e
+	}
+	return clientRequest.PostWebhook{Webhook: w}, nil
+}
+
+func decodeNoRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return nil, nil
+}
+
+func decodeGetWebhookRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetWebhook{ID: id}, nil
+}
+
+func decodeDeleteWebhookRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.DeleteWebhook{ID: id}, nil
+}
+
+func decodeGetDeliveriesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.GetWebhookDeliveries{ID: id}, nil
+}
+
+func decodeRedeliverRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	vars := mux.Vars(r)
+	id, ok := vars["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	deliveryID, ok := vars["deliveryID"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	return clientRequest.RedeliverWebhook{
+		ID:         id,
+		DeliveryID: deliveryID,
+	}, nil
+}
+
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if err == nil {
+		panic("encodeError with nil error")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(codeFrom(err))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": err.Error(),
+	})
+}
+
+func codeFrom(err error) int {
+	switch err {
+	case ErrNotFound:
+		return http.StatusNotFound
+	case ErrInvalidURL, ErrForbiddenURL, ErrUnknownEventType:
+		return http.StatusBadRequest
+	case ErrDeliveryPending:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}